@@ -10,11 +10,16 @@ import (
 	"time"
 
 	_ "github.com/1cbyc/go-todo-api/docs" // This is required for swag to find your docs
+	"github.com/1cbyc/go-todo-api/internal/auth"
 	"github.com/1cbyc/go-todo-api/internal/config"
 	"github.com/1cbyc/go-todo-api/internal/handlers"
 	"github.com/1cbyc/go-todo-api/internal/middleware"
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/1cbyc/go-todo-api/internal/notify"
 	"github.com/1cbyc/go-todo-api/internal/repository"
+	"github.com/1cbyc/go-todo-api/internal/scheduler"
 	"github.com/1cbyc/go-todo-api/internal/services"
+	"github.com/1cbyc/go-todo-api/internal/storage"
 	"github.com/1cbyc/go-todo-api/pkg/logger"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -43,6 +48,11 @@ import (
 // @name Authorization
 // @description Type "Bearer" followed by a space and JWT token.
 
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name Authorization
+// @description Type "ApiKey" followed by a space and the API key.
+
 func main() {
 	// Load environment variables
 	if err := godotenv.Load(); err != nil {
@@ -71,12 +81,86 @@ func main() {
 
 	// Initialize repositories
 	todoRepo := repository.NewTodoRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	workspaceRepo := repository.NewWorkspaceRepository(db)
+	listRepo := repository.NewListRepository(db)
+	tagRepo := repository.NewTagRepository(db)
+	dependencyRepo := repository.NewDependencyRepository(db)
+	reminderRepo := repository.NewReminderRepository(db)
+	commentRepo := repository.NewCommentRepository(db)
+	attachmentRepo := repository.NewAttachmentRepository(db)
+	timeEntryRepo := repository.NewTimeEntryRepository(db)
+	revisionRepo := repository.NewRevisionRepository(db)
+	viewRepo := repository.NewViewRepository(db)
+	transactor := repository.NewTransactor(db)
+
+	// Initialize token revocation store
+	var revocations auth.RevocationStore
+	switch cfg.JWT.RevocationStore {
+	case "redis":
+		redisClient, err := repository.NewRedisClient(context.Background(), cfg.Redis)
+		if err != nil {
+			logger.Fatal().Err(err).Msg("Failed to connect to redis")
+		}
+		defer redisClient.Close()
+		revocations = repository.NewRedisRevocationStore(redisClient)
+	default:
+		revocations = repository.NewDBRevocationStore(db)
+	}
+
+	// Initialize token manager
+	tokenManager := auth.NewTokenManager(cfg.JWT)
+
+	// Initialize reminder notification channels; a channel is only offered when configured
+	notifiers := map[models.ReminderChannel]notify.Notifier{}
+	if cfg.Reminder.SMTP.Host != "" {
+		notifiers[models.ReminderChannelEmail] = notify.NewEmailNotifier(cfg.Reminder.SMTP)
+	}
+	if cfg.Reminder.Webhook.URL != "" {
+		notifiers[models.ReminderChannelWebhook] = notify.NewWebhookNotifier(cfg.Reminder.Webhook)
+	}
+
+	// Initialize attachment blob store
+	var blobStore storage.BlobStore
+	switch cfg.Attachment.Store {
+	case "s3":
+		blobStore, err = storage.NewS3Store(cfg.Attachment.S3)
+	default:
+		blobStore, err = storage.NewLocalStore(cfg.Attachment.Dir)
+	}
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to initialize attachment store")
+	}
 
 	// Initialize services
-	todoService := services.NewTodoService(todoRepo)
+	todoService := services.NewTodoService(todoRepo, listRepo, dependencyRepo, reminderRepo, commentRepo, timeEntryRepo, revisionRepo, viewRepo, transactor, cfg.Todo)
+	reminderService := services.NewReminderService(reminderRepo, todoRepo, notifiers)
+	commentService := services.NewCommentService(commentRepo, todoRepo)
+	attachmentService := services.NewAttachmentService(attachmentRepo, todoRepo, blobStore, cfg.Attachment)
+	timeEntryService := services.NewTimeEntryService(timeEntryRepo, todoRepo)
+	listService := services.NewListService(listRepo)
+	tagService := services.NewTagService(tagRepo)
+	viewService := services.NewViewService(viewRepo)
+	authService := services.NewAuthService(userRepo, sessionRepo, revocations, tokenManager, transactor)
+	apiKeyService := services.NewAPIKeyService(apiKeyRepo)
+	userService := services.NewUserService(userRepo)
+	workspaceService := services.NewWorkspaceService(workspaceRepo, userRepo, transactor)
 
 	// Initialize handlers
-	todoHandler := handlers.NewTodoHandler(todoService)
+	todoHandler := handlers.NewTodoHandler(todoService, cfg.Todo)
+	listHandler := handlers.NewListHandler(listService)
+	tagHandler := handlers.NewTagHandler(tagService)
+	viewHandler := handlers.NewViewHandler(viewService)
+	reminderHandler := handlers.NewReminderHandler(reminderService)
+	commentHandler := handlers.NewCommentHandler(commentService)
+	attachmentHandler := handlers.NewAttachmentHandler(attachmentService, cfg.Attachment)
+	timeEntryHandler := handlers.NewTimeEntryHandler(timeEntryService)
+	authHandler := handlers.NewAuthHandler(authService)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyService)
+	userHandler := handlers.NewUserHandler(userService)
+	workspaceHandler := handlers.NewWorkspaceHandler(workspaceService)
 
 	// Create router
 	router := gin.New()
@@ -86,7 +170,7 @@ func main() {
 	router.Use(middleware.Logger(logger))
 	router.Use(middleware.Recovery(logger))
 	router.Use(middleware.CORS())
-	router.Use(middleware.Timeout(30 * time.Second))
+	router.Use(middleware.Timeout(30*time.Second, "/api/v1/todos/:id/attachments/:attachment_id"))
 
 	// Health check endpoint
 	router.GET("/health", handlers.HealthCheck)
@@ -97,19 +181,143 @@ func main() {
 	// API routes
 	api := router.Group("/api/v1")
 	{
+		// Auth routes
+		requireAuth := middleware.Auth(tokenManager, revocations, apiKeyService, userService)
+		requireSession := middleware.RequireSession()
+		requireWorkspace := middleware.Workspace(workspaceService)
+		canRead := middleware.RequirePermission(auth.PermissionTodosRead)
+		canWrite := middleware.RequirePermission(auth.PermissionTodosWrite)
+
+		authRoutes := api.Group("/auth")
+		{
+			authRoutes.POST("/register", authHandler.Register)
+			authRoutes.POST("/login", authHandler.Login)
+			authRoutes.POST("/refresh", authHandler.Refresh)
+			authRoutes.POST("/logout", requireAuth, requireSession, authHandler.Logout)
+			authRoutes.GET("/sessions", requireAuth, requireSession, authHandler.ListSessions)
+			authRoutes.DELETE("/sessions/:id", requireAuth, requireSession, authHandler.RevokeSession)
+		}
+
+		// API key routes
+		apiKeys := api.Group("/api-keys")
+		apiKeys.Use(requireAuth, requireSession)
+		{
+			apiKeys.GET("", apiKeyHandler.List)
+			apiKeys.POST("", apiKeyHandler.Create)
+			apiKeys.DELETE("/:id", apiKeyHandler.Revoke)
+		}
+
+		// Workspace routes
+		workspaces := api.Group("/workspaces")
+		workspaces.Use(requireAuth)
+		{
+			workspaces.GET("", workspaceHandler.List)
+			workspaces.POST("", requireSession, workspaceHandler.Create)
+			workspaces.GET("/:id/members", requireSession, workspaceHandler.ListMembers)
+			workspaces.POST("/:id/members", requireSession, workspaceHandler.AddMember)
+			workspaces.DELETE("/:id/members/:user_id", requireSession, workspaceHandler.RemoveMember)
+		}
+
 		// Todo routes
 		todos := api.Group("/todos")
+		todos.Use(requireAuth, requireWorkspace)
 		{
-			todos.GET("", todoHandler.GetAll)
-			todos.GET("/:id", todoHandler.GetByID)
-			todos.POST("", todoHandler.Create)
-			todos.PUT("/:id", todoHandler.Update)
-			todos.DELETE("/:id", todoHandler.Delete)
-			todos.PATCH("/:id/toggle", todoHandler.Toggle)
+			todos.GET("", canRead, todoHandler.GetAll)
+			todos.GET("/trash", canRead, todoHandler.Trash)
+			todos.GET("/search", canRead, todoHandler.Search)
+			todos.GET("/:id", canRead, todoHandler.GetByID)
+			todos.POST("", canWrite, todoHandler.Create)
+			todos.POST("/batch", canWrite, todoHandler.Batch)
+			todos.PUT("/:id", canWrite, todoHandler.Update)
+			todos.DELETE("/:id", canWrite, todoHandler.Delete)
+			todos.POST("/:id/restore", canWrite, todoHandler.Restore)
+			todos.PATCH("/:id/toggle", canWrite, todoHandler.Toggle)
+			todos.PATCH("/:id/status", canWrite, todoHandler.SetStatus)
+			todos.PATCH("/:id/list", canWrite, todoHandler.MoveToList)
+			todos.POST("/:id/move", canWrite, todoHandler.Reorder)
+			todos.GET("/:id/children", canRead, todoHandler.GetChildren)
+			todos.GET("/:id/tree", canRead, todoHandler.GetTree)
+			todos.PATCH("/:id/parent", canWrite, todoHandler.SetParent)
+			todos.POST("/:id/dependencies", canWrite, todoHandler.AddDependency)
+			todos.DELETE("/:id/dependencies/:blocked_by_id", canWrite, todoHandler.RemoveDependency)
+			todos.GET("/:id/recurrence", canRead, todoHandler.PreviewRecurrence)
+			todos.DELETE("/:id/recurrence", canWrite, todoHandler.StopRecurrence)
+			todos.GET("/:id/revisions", canRead, todoHandler.ListRevisions)
+			todos.GET("/:id/revisions/:rev", canRead, todoHandler.GetRevision)
+			todos.POST("/:id/revisions/:rev/restore", canWrite, todoHandler.RestoreRevision)
+			todos.GET("/:id/reminders", canRead, reminderHandler.List)
+			todos.POST("/:id/reminders", canWrite, reminderHandler.Create)
+			todos.DELETE("/:id/reminders/:reminder_id", canWrite, reminderHandler.Delete)
+			todos.GET("/:id/comments", canRead, commentHandler.List)
+			todos.POST("/:id/comments", canWrite, commentHandler.Create)
+			todos.PUT("/:id/comments/:comment_id", canWrite, commentHandler.Update)
+			todos.DELETE("/:id/comments/:comment_id", canWrite, commentHandler.Delete)
+			todos.GET("/:id/attachments", canRead, attachmentHandler.List)
+			todos.POST("/:id/attachments", canWrite, attachmentHandler.Upload)
+			todos.GET("/:id/attachments/:attachment_id", canRead, attachmentHandler.Download)
+			todos.DELETE("/:id/attachments/:attachment_id", canWrite, attachmentHandler.Delete)
+			todos.POST("/:id/timer/start", canWrite, timeEntryHandler.Start)
+			todos.POST("/:id/timer/stop", canWrite, timeEntryHandler.Stop)
+			todos.GET("/:id/time-entries", canRead, timeEntryHandler.List)
+			todos.POST("/:id/time-entries", canWrite, timeEntryHandler.Create)
+			todos.PUT("/:id/time-entries/:entry_id", canWrite, timeEntryHandler.Update)
+			todos.DELETE("/:id/time-entries/:entry_id", canWrite, timeEntryHandler.Delete)
+		}
+
+		// Time tracking routes
+		timeEntries := api.Group("/time-entries")
+		timeEntries.Use(requireAuth, requireWorkspace)
+		{
+			timeEntries.GET("/report", canRead, timeEntryHandler.Report)
+		}
+
+		// List routes
+		lists := api.Group("/lists")
+		lists.Use(requireAuth, requireWorkspace)
+		{
+			lists.GET("", canRead, listHandler.GetAll)
+			lists.GET("/:id", canRead, listHandler.GetByID)
+			lists.POST("", canWrite, listHandler.Create)
+			lists.PUT("/:id", canWrite, listHandler.Update)
+			lists.DELETE("/:id", canWrite, listHandler.Delete)
+			lists.GET("/:id/workflow", canRead, listHandler.GetWorkflow)
+			lists.PUT("/:id/workflow", canWrite, listHandler.UpdateWorkflow)
+			lists.GET("/:id/todos", canRead, todoHandler.GetByList)
+			lists.POST("/:id/todos", canWrite, todoHandler.CreateInList)
+		}
+
+		// Tag routes
+		tags := api.Group("/tags")
+		tags.Use(requireAuth, requireWorkspace)
+		{
+			tags.GET("", canRead, tagHandler.GetAll)
+			tags.PATCH("/:id", canWrite, tagHandler.Rename)
+			tags.POST("/:id/merge", canWrite, tagHandler.Merge)
+			tags.DELETE("/:id", canWrite, tagHandler.Delete)
+		}
+
+		// Saved view routes
+		views := api.Group("/views")
+		views.Use(requireAuth, requireWorkspace)
+		{
+			views.GET("", canRead, viewHandler.GetAll)
+			views.GET("/:id", canRead, viewHandler.GetByID)
+			views.POST("", canWrite, viewHandler.Create)
+			views.PUT("/:id", canWrite, viewHandler.Update)
+			views.DELETE("/:id", canWrite, viewHandler.Delete)
+			views.GET("/:id/todos", canRead, todoHandler.GetByView)
+		}
+
+		// Admin routes
+		admin := api.Group("/admin")
+		admin.Use(requireAuth, middleware.RequirePermission(auth.PermissionUsersManage))
+		{
+			admin.GET("/users", userHandler.GetAll)
+			admin.PATCH("/users/:id/role", userHandler.UpdateRole)
 		}
 
 		// Metrics endpoint
-		api.GET("/metrics", handlers.Metrics)
+		api.GET("/metrics", requireAuth, middleware.RequirePermission(auth.PermissionMetricsRead), handlers.Metrics)
 	}
 
 	// Create HTTP server
@@ -121,6 +329,27 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
+	// Start the reminder scheduler
+	reminderScheduler := scheduler.NewReminderScheduler(reminderRepo, notifiers, cfg.Reminder, logger)
+	if err := reminderScheduler.Start(context.Background()); err != nil {
+		logger.Fatal().Err(err).Msg("Failed to start reminder scheduler")
+	}
+
+	// Start removing the attachments of purged todos
+	attachmentCleanup := scheduler.NewPeriodic("attachment-cleanup", cfg.Attachment.CleanupInterval,
+		scheduler.CleanupAttachments(attachmentRepo, blobStore, logger), logger)
+	attachmentCleanup.Start()
+
+	// Start rebalancing lists whose position keys have grown too long
+	positionRebalance := scheduler.NewPeriodic("position-rebalance", cfg.Todo.RebalanceInterval,
+		scheduler.RebalancePositions(todoRepo, cfg.Todo.PositionMaxLength, logger), logger)
+	positionRebalance.Start()
+
+	// Start purging todos that have been in the trash for longer than the retention
+	trashPurge := scheduler.NewPeriodic("trash-purge", cfg.Todo.PurgeInterval,
+		scheduler.PurgeTrash(todoRepo, cfg.Todo.TrashRetention, logger), logger)
+	trashPurge.Start()
+
 	// Start server in a goroutine
 	go func() {
 		logger.Info().Str("port", cfg.Server.Port).Msg("Starting server")
@@ -140,9 +369,23 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	// Shutdown server gracefully
+	// Shutdown server gracefully; the schedulers are stopped even when it fails
 	if err := server.Shutdown(ctx); err != nil {
-		logger.Fatal().Err(err).Msg("Server forced to shutdown")
+		logger.Error().Err(err).Msg("Server forced to shutdown")
+	}
+
+	// Let the reminder scheduler finish the delivery in progress
+	if err := reminderScheduler.Stop(ctx); err != nil {
+		logger.Error().Err(err).Msg("Reminder scheduler did not stop in time")
+	}
+	if err := attachmentCleanup.Stop(ctx); err != nil {
+		logger.Error().Err(err).Msg("Attachment cleanup did not stop in time")
+	}
+	if err := positionRebalance.Stop(ctx); err != nil {
+		logger.Error().Err(err).Msg("Position rebalancing did not stop in time")
+	}
+	if err := trashPurge.Stop(ctx); err != nil {
+		logger.Error().Err(err).Msg("Trash purge did not stop in time")
 	}
 
 	logger.Info().Msg("Server exited")
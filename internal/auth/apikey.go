@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// APIKeyPrefix marks a string as an API key issued by this service
+const APIKeyPrefix = "tdk"
+
+var (
+	// ErrMalformedAPIKey is returned when a string does not have the API key shape
+	ErrMalformedAPIKey = errors.New("malformed api key")
+	// ErrInvalidAPIKey is returned when an API key is unknown, expired or revoked
+	ErrInvalidAPIKey = errors.New("invalid api key")
+)
+
+// NewAPIKey returns a new API key in the form tdk_<prefix>_<secret> along with
+// its public prefix. The prefix identifies the key without revealing the secret.
+func NewAPIKey() (key string, prefix string, err error) {
+	idBytes := make([]byte, 4)
+	if _, err := rand.Read(idBytes); err != nil {
+		return "", "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+
+	prefix = APIKeyPrefix + "_" + hex.EncodeToString(idBytes)
+	key = prefix + "_" + base64.RawURLEncoding.EncodeToString(secret)
+	return key, prefix, nil
+}
+
+// ParseAPIKeyPrefix returns the public prefix of an API key
+func ParseAPIKeyPrefix(key string) (string, error) {
+	parts := strings.SplitN(key, "_", 3)
+	if len(parts) != 3 || parts[0] != APIKeyPrefix || len(parts[1]) != 8 || parts[2] == "" {
+		return "", ErrMalformedAPIKey
+	}
+	return parts[0] + "_" + parts[1], nil
+}
+
+// MatchToken reports whether token hashes to the stored hash in constant time
+func MatchToken(token, hash string) bool {
+	return subtle.ConstantTimeCompare([]byte(HashToken(token)), []byte(hash)) == 1
+}
@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/google/uuid"
+)
+
+type contextKey string
+
+const principalKey contextKey = "principal"
+
+// Principal describes who is making a request and how they authenticated
+type Principal struct {
+	UserID      uuid.UUID
+	Role        models.Role
+	SessionID   uuid.UUID    // set when authenticated with an access token
+	APIKeyID    uuid.UUID    // set when authenticated with an API key
+	Scopes      []Permission // nil means unrestricted
+	WorkspaceID uuid.UUID    // set once the request is bound to a workspace
+}
+
+// IsAPIKey reports whether the principal authenticated with an API key
+func (p *Principal) IsAPIKey() bool {
+	return p.APIKeyID != uuid.Nil
+}
+
+// HasScope reports whether the principal's API key scopes include the permission
+func (p *Principal) HasScope(permission Permission) bool {
+	if p.Scopes == nil {
+		return true
+	}
+	for _, s := range p.Scopes {
+		if s == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Can reports whether the principal's role and scopes both grant the permission
+func (p *Principal) Can(permission Permission) bool {
+	return RoleAllows(p.Role, permission) && p.HasScope(permission)
+}
+
+// WithPrincipal returns a copy of ctx carrying the authenticated principal
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey, principal)
+}
+
+// PrincipalFromContext returns the authenticated principal stored in ctx
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey).(*Principal)
+	if !ok || principal == nil || principal.UserID == uuid.Nil {
+		return nil, false
+	}
+	return principal, true
+}
+
+// UserIDFromContext returns the authenticated user ID stored in ctx
+func UserIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return uuid.Nil, false
+	}
+	return principal.UserID, true
+}
+
+// SessionIDFromContext returns the current session ID stored in ctx
+func SessionIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok || principal.SessionID == uuid.Nil {
+		return uuid.Nil, false
+	}
+	return principal.SessionID, true
+}
+
+// WorkspaceIDFromContext returns the workspace the request is bound to
+func WorkspaceIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok || principal.WorkspaceID == uuid.Nil {
+		return uuid.Nil, false
+	}
+	return principal.WorkspaceID, true
+}
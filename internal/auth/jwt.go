@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/1cbyc/go-todo-api/internal/config"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// ErrInvalidToken is returned when a token cannot be verified
+var ErrInvalidToken = errors.New("invalid token")
+
+// Claims represents the JWT claims issued by the API
+type Claims struct {
+	SessionID string `json:"sid"`
+	jwt.RegisteredClaims
+}
+
+// UserID returns the user ID carried in the subject claim
+func (c *Claims) UserID() (uuid.UUID, error) {
+	return uuid.Parse(c.Subject)
+}
+
+// SessionUUID returns the session ID carried in the sid claim
+func (c *Claims) SessionUUID() (uuid.UUID, error) {
+	return uuid.Parse(c.SessionID)
+}
+
+// TokenManager issues and verifies signed access tokens
+type TokenManager struct {
+	secret            []byte
+	expiration        time.Duration
+	refreshExpiration time.Duration
+}
+
+// NewTokenManager creates a new token manager
+func NewTokenManager(cfg config.JWTConfig) *TokenManager {
+	return &TokenManager{
+		secret:            []byte(cfg.Secret),
+		expiration:        cfg.Expiration,
+		refreshExpiration: cfg.RefreshExpiration,
+	}
+}
+
+// Expiration returns the lifetime of access tokens
+func (m *TokenManager) Expiration() time.Duration {
+	return m.expiration
+}
+
+// RefreshExpiration returns the lifetime of refresh tokens
+func (m *TokenManager) RefreshExpiration() time.Duration {
+	return m.refreshExpiration
+}
+
+// Generate issues a signed access token for the given user and session
+func (m *TokenManager) Generate(userID, sessionID uuid.UUID) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(m.expiration)
+
+	claims := Claims{
+		SessionID: sessionID.String(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Subject:   userID.String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(m.secret)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign token: %w", err)
+	}
+
+	return token, expiresAt, nil
+}
+
+// Parse verifies a signed access token and returns its claims
+func (m *TokenManager) Parse(tokenString string) (*Claims, error) {
+	var claims Claims
+	_, err := jwt.ParseWithClaims(tokenString, &claims, func(token *jwt.Token) (interface{}, error) {
+		return m.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	return &claims, nil
+}
@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"context"
+	"errors"
+
+	"github.com/1cbyc/go-todo-api/internal/models"
+)
+
+var (
+	// ErrForbidden is returned when the principal lacks a required permission
+	ErrForbidden = errors.New("forbidden")
+	// ErrUnknownUser is returned when a credential refers to a user that no longer exists
+	ErrUnknownUser = errors.New("unknown user")
+	// ErrWorkspaceAccess is returned when a user is not a member of the requested workspace
+	ErrWorkspaceAccess = errors.New("workspace not found")
+)
+
+// Permission names an action a principal may perform
+type Permission string
+
+const (
+	PermissionTodosRead   Permission = "todos:read"
+	PermissionTodosWrite  Permission = "todos:write"
+	PermissionMetricsRead Permission = "metrics:read"
+	PermissionUsersManage Permission = "users:manage"
+)
+
+// APIKeyScopes lists the permissions that can be granted to an API key
+var APIKeyScopes = []Permission{PermissionTodosRead, PermissionTodosWrite, PermissionMetricsRead}
+
+// rolePermissions maps each role to the permissions it grants
+var rolePermissions = map[models.Role][]Permission{
+	models.RoleAdmin:  {PermissionTodosRead, PermissionTodosWrite, PermissionMetricsRead, PermissionUsersManage},
+	models.RoleMember: {PermissionTodosRead, PermissionTodosWrite},
+	models.RoleViewer: {PermissionTodosRead},
+}
+
+// RoleAllows reports whether role grants the permission
+func RoleAllows(role models.Role, permission Permission) bool {
+	for _, p := range rolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Authorize returns ErrForbidden unless the principal in ctx holds the permission
+func Authorize(ctx context.Context, permission Permission) error {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok || !principal.Can(permission) {
+		return ErrForbidden
+	}
+	return nil
+}
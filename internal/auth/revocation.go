@@ -0,0 +1,15 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RevocationStore records sessions whose access tokens must no longer be accepted.
+// Entries only need to live until the last access token issued for the session expires.
+type RevocationStore interface {
+	Revoke(ctx context.Context, sessionID uuid.UUID, until time.Time) error
+	IsRevoked(ctx context.Context, sessionID uuid.UUID) (bool, error)
+}
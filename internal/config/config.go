@@ -4,15 +4,19 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
 // Config holds all configuration for the application
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	JWT      JWTConfig
+	Server     ServerConfig
+	Database   DatabaseConfig
+	Redis      RedisConfig
+	JWT        JWTConfig
+	Todo       TodoConfig
+	Reminder   ReminderConfig
+	Attachment AttachmentConfig
 }
 
 // ServerConfig holds server configuration
@@ -46,8 +50,90 @@ type RedisConfig struct {
 
 // JWTConfig holds JWT configuration
 type JWTConfig struct {
-	Secret     string
-	Expiration time.Duration
+	Secret            string
+	Expiration        time.Duration
+	RefreshExpiration time.Duration
+	RevocationStore   string
+}
+
+// TodoConfig holds todo behaviour configuration
+type TodoConfig struct {
+	// AutoCompleteParent completes a parent todo once all of its subtasks are completed
+	AutoCompleteParent bool
+	// PositionMaxLength is the position key length above which a list's order is rebalanced
+	PositionMaxLength int
+	// RebalanceInterval is how often lists with overly long position keys are rebalanced
+	RebalanceInterval time.Duration
+	// TrashRetention is how long deleted todos stay in the trash before they are purged
+	TrashRetention time.Duration
+	// PurgeInterval is how often todos past the trash retention are purged
+	PurgeInterval time.Duration
+	// CursorSecret signs pagination cursors
+	CursorSecret string
+	// BatchMaxSize is the most operations a batch request may hold
+	BatchMaxSize int
+}
+
+// ReminderConfig holds reminder scheduling and delivery configuration
+type ReminderConfig struct {
+	// PollInterval is how often the scheduler looks for due reminders
+	PollInterval time.Duration
+	// MaxAttempts is how many times a reminder is tried before it is marked as failed
+	MaxAttempts int
+	// RetryDelay is the wait after the first failed attempt; it doubles with each attempt
+	RetryDelay time.Duration
+	SMTP       SMTPConfig
+	Webhook    WebhookConfig
+}
+
+// SMTPConfig holds the mail server used for email reminders. Email is disabled when Host is empty.
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// WebhookConfig holds the endpoint used for webhook reminders. Webhooks are disabled when URL is empty.
+type WebhookConfig struct {
+	URL     string
+	Secret  string
+	Timeout time.Duration
+}
+
+// AttachmentConfig holds file attachment limits and storage configuration
+type AttachmentConfig struct {
+	// MaxSize is the largest accepted upload in bytes
+	MaxSize int64
+	// AllowedTypes lists the accepted MIME types, detected from the file content
+	AllowedTypes []string
+	// Store selects the blob store: local or s3
+	Store string
+	// Dir is the root directory of the local store
+	Dir string
+	// CleanupInterval is how often blobs of purged todos are removed
+	CleanupInterval time.Duration
+	S3              S3Config
+}
+
+// S3Config holds the S3-compatible bucket used when the attachment store is s3
+type S3Config struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+}
+
+// defaultSecret is the placeholder secret used when none is configured
+const defaultSecret = "your-secret-key"
+
+// placeholderSecrets are the secrets shipped as the default and in env.example.
+// They are public, so release mode refuses them.
+var placeholderSecrets = map[string]bool{
+	defaultSecret:                          true,
+	"your-secret-key-change-in-production": true,
 }
 
 // Load loads configuration from environment variables
@@ -76,14 +162,100 @@ func Load() (*Config, error) {
 			DB:       getIntEnv("REDIS_DB", 0),
 		},
 		JWT: JWTConfig{
-			Secret:     getEnv("JWT_SECRET", "your-secret-key"),
-			Expiration: getDurationEnv("JWT_EXPIRATION", 24*time.Hour),
+			Secret:            getEnv("JWT_SECRET", defaultSecret),
+			Expiration:        getDurationEnv("JWT_EXPIRATION", 15*time.Minute),
+			RefreshExpiration: getDurationEnv("JWT_REFRESH_EXPIRATION", 30*24*time.Hour),
+			RevocationStore:   getEnv("JWT_REVOCATION_STORE", "database"),
+		},
+		Todo: TodoConfig{
+			AutoCompleteParent: getBoolEnv("TODO_AUTO_COMPLETE_PARENT", false),
+			PositionMaxLength:  getIntEnv("TODO_POSITION_MAX_LENGTH", 24),
+			RebalanceInterval:  getDurationEnv("TODO_REBALANCE_INTERVAL", time.Hour),
+			TrashRetention:     getDurationEnv("TODO_TRASH_RETENTION", 30*24*time.Hour),
+			PurgeInterval:      getDurationEnv("TODO_PURGE_INTERVAL", time.Hour),
+			CursorSecret:       getEnv("TODO_CURSOR_SECRET", getEnv("JWT_SECRET", defaultSecret)),
+			BatchMaxSize:       getIntEnv("TODO_BATCH_MAX_SIZE", 100),
+		},
+		Reminder: ReminderConfig{
+			PollInterval: getDurationEnv("REMINDER_POLL_INTERVAL", 30*time.Second),
+			MaxAttempts:  getIntEnv("REMINDER_MAX_ATTEMPTS", 5),
+			RetryDelay:   getDurationEnv("REMINDER_RETRY_DELAY", time.Minute),
+			SMTP: SMTPConfig{
+				Host:     getEnv("SMTP_HOST", ""),
+				Port:     getEnv("SMTP_PORT", "587"),
+				Username: getEnv("SMTP_USERNAME", ""),
+				Password: getEnv("SMTP_PASSWORD", ""),
+				From:     getEnv("SMTP_FROM", "todo-api@localhost"),
+			},
+			Webhook: WebhookConfig{
+				URL:     getEnv("REMINDER_WEBHOOK_URL", ""),
+				Secret:  getEnv("REMINDER_WEBHOOK_SECRET", ""),
+				Timeout: getDurationEnv("REMINDER_WEBHOOK_TIMEOUT", 10*time.Second),
+			},
+		},
+		Attachment: AttachmentConfig{
+			MaxSize:         int64(getIntEnv("ATTACHMENT_MAX_SIZE", 10<<20)),
+			AllowedTypes:    getListEnv("ATTACHMENT_ALLOWED_TYPES", []string{"image/png", "image/jpeg", "image/gif", "image/webp", "application/pdf", "text/plain"}),
+			Store:           getEnv("ATTACHMENT_STORE", "local"),
+			Dir:             getEnv("ATTACHMENT_DIR", "data/attachments"),
+			CleanupInterval: getDurationEnv("ATTACHMENT_CLEANUP_INTERVAL", time.Hour),
+			S3: S3Config{
+				Endpoint:  getEnv("S3_ENDPOINT", "https://s3.amazonaws.com"),
+				Region:    getEnv("S3_REGION", "us-east-1"),
+				Bucket:    getEnv("S3_BUCKET", ""),
+				AccessKey: getEnv("S3_ACCESS_KEY", ""),
+				SecretKey: getEnv("S3_SECRET_KEY", ""),
+			},
 		},
 	}
 
 	// Build database DSN
 	cfg.Database.DSN = buildDSN(cfg.Database)
 
+	if cfg.Server.Mode == "release" && placeholderSecrets[cfg.JWT.Secret] {
+		return nil, fmt.Errorf("JWT_SECRET must be set to a secret of your own in release mode")
+	}
+	if cfg.Server.Mode == "release" && placeholderSecrets[cfg.Todo.CursorSecret] {
+		return nil, fmt.Errorf("TODO_CURSOR_SECRET must be set to a secret of your own in release mode")
+	}
+
+	switch cfg.JWT.RevocationStore {
+	case "database", "redis":
+	default:
+		return nil, fmt.Errorf("unsupported revocation store: %s", cfg.JWT.RevocationStore)
+	}
+
+	switch cfg.Attachment.Store {
+	case "local", "s3":
+	default:
+		return nil, fmt.Errorf("unsupported attachment store: %s", cfg.Attachment.Store)
+	}
+	if cfg.Attachment.MaxSize <= 0 || cfg.Attachment.CleanupInterval <= 0 {
+		return nil, fmt.Errorf("invalid attachment configuration")
+	}
+
+	if cfg.Reminder.PollInterval <= 0 {
+		return nil, fmt.Errorf("invalid reminder poll interval: %s", cfg.Reminder.PollInterval)
+	}
+	if cfg.Reminder.MaxAttempts < 1 {
+		return nil, fmt.Errorf("invalid reminder max attempts: %d", cfg.Reminder.MaxAttempts)
+	}
+	if cfg.Todo.PositionMaxLength < 4 || cfg.Todo.PositionMaxLength > 200 {
+		return nil, fmt.Errorf("invalid todo position max length: %d", cfg.Todo.PositionMaxLength)
+	}
+	if cfg.Todo.RebalanceInterval <= 0 {
+		return nil, fmt.Errorf("invalid todo rebalance interval: %s", cfg.Todo.RebalanceInterval)
+	}
+	if cfg.Todo.TrashRetention <= 0 {
+		return nil, fmt.Errorf("invalid todo trash retention: %s", cfg.Todo.TrashRetention)
+	}
+	if cfg.Todo.PurgeInterval <= 0 {
+		return nil, fmt.Errorf("invalid todo purge interval: %s", cfg.Todo.PurgeInterval)
+	}
+	if cfg.Todo.BatchMaxSize <= 0 {
+		return nil, fmt.Errorf("invalid todo batch max size: %d", cfg.Todo.BatchMaxSize)
+	}
+
 	return cfg, nil
 }
 
@@ -105,6 +277,31 @@ func getIntEnv(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// getBoolEnv gets a boolean environment variable or returns a default value
+func getBoolEnv(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
+// getListEnv gets a comma-separated environment variable or returns a default value
+func getListEnv(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
 // getDurationEnv gets a duration environment variable or returns a default value
 func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
@@ -115,6 +312,16 @@ func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	return defaultValue
 }
 
+// Addr returns the Redis address in host:port form
+func (c RedisConfig) Addr() string {
+	return fmt.Sprintf("%s:%s", c.Host, c.Port)
+}
+
+// Addr returns the SMTP server address in host:port form
+func (c SMTPConfig) Addr() string {
+	return fmt.Sprintf("%s:%s", c.Host, c.Port)
+}
+
 // buildDSN builds the database connection string
 func buildDSN(cfg DatabaseConfig) string {
 	switch cfg.Driver {
@@ -122,8 +329,10 @@ func buildDSN(cfg DatabaseConfig) string {
 		return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
 			cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName, cfg.SSLMode)
 	case "sqlite":
-		return fmt.Sprintf("%s.db", cfg.DBName)
+		// Transactions take the write lock up front, so concurrent writers wait
+		// for each other instead of failing when they go from reading to writing
+		return fmt.Sprintf("%s.db?_txlock=immediate", cfg.DBName)
 	default:
 		return fmt.Sprintf("%s.db", cfg.DBName)
 	}
-} 
\ No newline at end of file
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/1cbyc/go-todo-api/internal/services"
+	"github.com/1cbyc/go-todo-api/pkg/response"
+	"github.com/1cbyc/go-todo-api/pkg/validator"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// APIKeyHandler handles HTTP requests for API key management
+type APIKeyHandler struct {
+	service services.APIKeyService
+	logger  zerolog.Logger
+}
+
+// NewAPIKeyHandler creates a new API key handler
+func NewAPIKeyHandler(service services.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		service: service,
+		logger:  zerolog.Logger{},
+	}
+}
+
+// Create handles POST /api/v1/api-keys
+// @Summary Create an API key
+// @Description Create a scoped API key for machine clients. The key is only returned once.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param key body models.CreateAPIKeyRequest true "API key to create"
+// @Success 201 {object} response.SuccessResponse{data=models.CreatedAPIKeyResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /api-keys [post]
+func (h *APIKeyHandler) Create(c *gin.Context) {
+	var req models.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate.Struct(req); err != nil {
+		response.BadRequest(c, "Validation failed", err)
+		return
+	}
+
+	key, err := h.service.Create(c.Request.Context(), &req)
+	if err != nil {
+		if errors.Is(err, services.ErrAPIKeyExpiryInPast) {
+			response.BadRequest(c, "Expiry must be in the future", nil)
+			return
+		}
+		h.logger.Error().Err(err).Msg("Failed to create API key")
+		response.InternalServerError(c, "Failed to create API key", err)
+		return
+	}
+
+	response.Created(c, "API key created successfully", key)
+}
+
+// List handles GET /api/v1/api-keys
+// @Summary List API keys
+// @Description List the API keys of the authenticated user without their secrets
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.SuccessResponse{data=[]models.APIKeyResponse}
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /api-keys [get]
+func (h *APIKeyHandler) List(c *gin.Context) {
+	keys, err := h.service.List(c.Request.Context())
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to list API keys")
+		response.InternalServerError(c, "Failed to list API keys", err)
+		return
+	}
+
+	response.OK(c, "API keys retrieved successfully", keys)
+}
+
+// Revoke handles DELETE /api/v1/api-keys/:id
+// @Summary Revoke an API key
+// @Description Revoke an API key so it can no longer be used
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "API key ID" format(uuid)
+// @Success 200 {object} response.SuccessResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /api-keys/{id} [delete]
+func (h *APIKeyHandler) Revoke(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid API key ID", err)
+		return
+	}
+
+	if err := h.service.Revoke(c.Request.Context(), id); err != nil {
+		if errors.Is(err, services.ErrAPIKeyNotFound) {
+			response.NotFound(c, "API key not found", nil)
+			return
+		}
+		h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to revoke API key")
+		response.InternalServerError(c, "Failed to revoke API key", err)
+		return
+	}
+
+	response.OK(c, "API key revoked successfully", nil)
+}
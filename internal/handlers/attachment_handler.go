@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+
+	"github.com/1cbyc/go-todo-api/internal/auth"
+	"github.com/1cbyc/go-todo-api/internal/config"
+	"github.com/1cbyc/go-todo-api/internal/services"
+	"github.com/1cbyc/go-todo-api/pkg/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// multipartOverhead is the room allowed for multipart headers on top of the file size limit
+const multipartOverhead = 1 << 20
+
+// AttachmentHandler handles HTTP requests for attachment operations
+type AttachmentHandler struct {
+	service services.AttachmentService
+	cfg     config.AttachmentConfig
+	logger  zerolog.Logger
+}
+
+// NewAttachmentHandler creates a new attachment handler
+func NewAttachmentHandler(service services.AttachmentService, cfg config.AttachmentConfig) *AttachmentHandler {
+	return &AttachmentHandler{
+		service: service,
+		cfg:     cfg,
+		logger:  zerolog.Logger{},
+	}
+}
+
+// Upload handles POST /api/v1/todos/:id/attachments
+// @Summary Attach a file to a todo
+// @Description Upload a file as multipart form data in the "file" field. The type is detected from the content and must be one of the allowed types; the size is limited by ATTACHMENT_MAX_SIZE.
+// @Tags attachments
+// @Accept mpfd
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "Todo ID" format(uuid)
+// @Param file formData file true "File to attach"
+// @Success 201 {object} response.SuccessResponse{data=models.Attachment}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 413 {object} response.ErrorResponse
+// @Failure 415 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /todos/{id}/attachments [post]
+func (h *AttachmentHandler) Upload(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid todo ID", err)
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.cfg.MaxSize+multipartOverhead)
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			response.PayloadTooLarge(c, "Attachment is too large", nil)
+			return
+		}
+		response.BadRequest(c, "Missing file", err.Error())
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		response.BadRequest(c, "Invalid file", err.Error())
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.service.Upload(c.Request.Context(), id, header.Filename, header.Size, file)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to update todos", nil)
+		case errors.Is(err, services.ErrAttachmentEmpty):
+			response.BadRequest(c, "Attachment is empty", nil)
+		case errors.Is(err, services.ErrAttachmentTooLarge):
+			response.PayloadTooLarge(c, "Attachment is too large", nil)
+		case errors.Is(err, services.ErrUnsupportedType):
+			response.UnsupportedMediaType(c, "Attachment type is not allowed", err.Error())
+		case errors.Is(err, services.ErrTodoNotFound):
+			response.NotFound(c, "Todo not found", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to upload attachment")
+			response.InternalServerError(c, "Failed to upload attachment", err)
+		}
+		return
+	}
+
+	response.Created(c, "Attachment uploaded successfully", attachment)
+}
+
+// List handles GET /api/v1/todos/:id/attachments
+// @Summary Get attachments of a todo
+// @Description Get the metadata of the files attached to a todo, oldest first
+// @Tags attachments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "Todo ID" format(uuid)
+// @Success 200 {object} response.SuccessResponse{data=[]models.Attachment}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /todos/{id}/attachments [get]
+func (h *AttachmentHandler) List(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid todo ID", err)
+		return
+	}
+
+	attachments, err := h.service.List(c.Request.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to read todos", nil)
+		case errors.Is(err, services.ErrTodoNotFound):
+			response.NotFound(c, "Todo not found", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to get attachments")
+			response.InternalServerError(c, "Failed to get attachments", err)
+		}
+		return
+	}
+
+	response.OK(c, "Attachments retrieved successfully", attachments)
+}
+
+// Download handles GET /api/v1/todos/:id/attachments/:attachment_id
+// @Summary Download an attachment
+// @Description Stream the content of an attachment. Supports Range requests and conditional requests on the ETag.
+// @Tags attachments
+// @Produce octet-stream
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "Todo ID" format(uuid)
+// @Param attachment_id path string true "Attachment ID" format(uuid)
+// @Param Range header string false "Byte range, e.g. bytes=0-1023"
+// @Success 200 {file} file
+// @Success 206 {file} file
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 416 {string} string
+// @Failure 500 {object} response.ErrorResponse
+// @Router /todos/{id}/attachments/{attachment_id} [get]
+func (h *AttachmentHandler) Download(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid todo ID", err)
+		return
+	}
+	attachmentID, err := uuid.Parse(c.Param("attachment_id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid attachment ID", err)
+		return
+	}
+
+	attachment, content, err := h.service.Open(c.Request.Context(), id, attachmentID)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to read todos", nil)
+		case errors.Is(err, services.ErrAttachmentNotFound):
+			response.NotFound(c, "Attachment not found", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to open attachment")
+			response.InternalServerError(c, "Failed to open attachment", err)
+		}
+		return
+	}
+	defer content.Close()
+
+	header := c.Writer.Header()
+	header.Set("Content-Type", attachment.ContentType)
+	header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}))
+	header.Set("X-Content-Type-Options", "nosniff")
+	if attachment.Checksum != "" {
+		header.Set("ETag", `"`+attachment.Checksum+`"`)
+	}
+	http.ServeContent(c.Writer, c.Request, "", attachment.CreatedAt, content)
+}
+
+// Delete handles DELETE /api/v1/todos/:id/attachments/:attachment_id
+// @Summary Delete an attachment
+// @Description Delete an attachment and its stored content
+// @Tags attachments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "Todo ID" format(uuid)
+// @Param attachment_id path string true "Attachment ID" format(uuid)
+// @Success 200 {object} response.SuccessResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /todos/{id}/attachments/{attachment_id} [delete]
+func (h *AttachmentHandler) Delete(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid todo ID", err)
+		return
+	}
+	attachmentID, err := uuid.Parse(c.Param("attachment_id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid attachment ID", err)
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), id, attachmentID); err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to update todos", nil)
+		case errors.Is(err, services.ErrAttachmentNotFound):
+			response.NotFound(c, "Attachment not found", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to delete attachment")
+			response.InternalServerError(c, "Failed to delete attachment", err)
+		}
+		return
+	}
+
+	response.OK(c, "Attachment deleted successfully", nil)
+}
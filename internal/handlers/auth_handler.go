@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/1cbyc/go-todo-api/internal/services"
+	"github.com/1cbyc/go-todo-api/pkg/response"
+	"github.com/1cbyc/go-todo-api/pkg/validator"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// AuthHandler handles HTTP requests for authentication
+type AuthHandler struct {
+	service services.AuthService
+	logger  zerolog.Logger
+}
+
+// NewAuthHandler creates a new auth handler
+func NewAuthHandler(service services.AuthService) *AuthHandler {
+	return &AuthHandler{
+		service: service,
+		logger:  zerolog.Logger{},
+	}
+}
+
+// Register handles POST /api/v1/auth/register
+// @Summary Register a new user
+// @Description Create a user account and receive an access and refresh token pair
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param user body models.RegisterRequest true "Account to create"
+// @Success 201 {object} response.SuccessResponse{data=models.AuthResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /auth/register [post]
+func (h *AuthHandler) Register(c *gin.Context) {
+	var req models.RegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate.Struct(req); err != nil {
+		response.BadRequest(c, "Validation failed", err)
+		return
+	}
+
+	result, err := h.service.Register(c.Request.Context(), &req, clientInfo(c))
+	if err != nil {
+		if errors.Is(err, services.ErrEmailTaken) {
+			response.Conflict(c, "Email already registered", nil)
+			return
+		}
+		h.logger.Error().Err(err).Msg("Failed to register user")
+		response.InternalServerError(c, "Failed to register user", err)
+		return
+	}
+
+	response.Created(c, "User registered successfully", result)
+}
+
+// Login handles POST /api/v1/auth/login
+// @Summary Log in
+// @Description Exchange email and password for an access and refresh token pair
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param credentials body models.LoginRequest true "Login credentials"
+// @Success 200 {object} response.SuccessResponse{data=models.AuthResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /auth/login [post]
+func (h *AuthHandler) Login(c *gin.Context) {
+	var req models.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate.Struct(req); err != nil {
+		response.BadRequest(c, "Validation failed", err)
+		return
+	}
+
+	result, err := h.service.Login(c.Request.Context(), &req, clientInfo(c))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCredentials) {
+			response.Unauthorized(c, "Invalid email or password", nil)
+			return
+		}
+		h.logger.Error().Err(err).Msg("Failed to log in")
+		response.InternalServerError(c, "Failed to log in", err)
+		return
+	}
+
+	response.OK(c, "Logged in successfully", result)
+}
+
+// Refresh handles POST /api/v1/auth/refresh
+// @Summary Refresh an access token
+// @Description Rotate a refresh token and receive a new token pair. Reusing a rotated refresh token revokes the session.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param token body models.RefreshRequest true "Refresh token"
+// @Success 200 {object} response.SuccessResponse{data=models.AuthResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /auth/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req models.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate.Struct(req); err != nil {
+		response.BadRequest(c, "Validation failed", err)
+		return
+	}
+
+	result, err := h.service.Refresh(c.Request.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrRefreshTokenReused):
+			h.logger.Warn().Msg("Refresh token reuse detected, session revoked")
+			response.Unauthorized(c, "Refresh token reuse detected, session revoked", nil)
+		case errors.Is(err, services.ErrInvalidRefreshToken):
+			response.Unauthorized(c, "Invalid or expired refresh token", nil)
+		default:
+			h.logger.Error().Err(err).Msg("Failed to refresh token")
+			response.InternalServerError(c, "Failed to refresh token", err)
+		}
+		return
+	}
+
+	response.OK(c, "Token refreshed successfully", result)
+}
+
+// Logout handles POST /api/v1/auth/logout
+// @Summary Log out
+// @Description Revoke the current session and its tokens
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.SuccessResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	if err := h.service.Logout(c.Request.Context()); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to log out")
+		response.InternalServerError(c, "Failed to log out", err)
+		return
+	}
+
+	response.OK(c, "Logged out successfully", nil)
+}
+
+// ListSessions handles GET /api/v1/auth/sessions
+// @Summary List sessions
+// @Description List the active sessions of the authenticated user
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.SuccessResponse{data=[]models.SessionResponse}
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /auth/sessions [get]
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	sessions, err := h.service.ListSessions(c.Request.Context())
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to list sessions")
+		response.InternalServerError(c, "Failed to list sessions", err)
+		return
+	}
+
+	response.OK(c, "Sessions retrieved successfully", sessions)
+}
+
+// RevokeSession handles DELETE /api/v1/auth/sessions/:id
+// @Summary Revoke a session
+// @Description Revoke one of the authenticated user's sessions, signing that device out
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Session ID" format(uuid)
+// @Success 200 {object} response.SuccessResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /auth/sessions/{id} [delete]
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid session ID", err)
+		return
+	}
+
+	if err := h.service.RevokeSession(c.Request.Context(), id); err != nil {
+		if errors.Is(err, services.ErrSessionNotFound) {
+			response.NotFound(c, "Session not found", nil)
+			return
+		}
+		h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to revoke session")
+		response.InternalServerError(c, "Failed to revoke session", err)
+		return
+	}
+
+	response.OK(c, "Session revoked successfully", nil)
+}
+
+// clientInfo describes the device making the request
+func clientInfo(c *gin.Context) models.ClientInfo {
+	return models.ClientInfo{
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	}
+}
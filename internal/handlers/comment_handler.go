@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/1cbyc/go-todo-api/internal/auth"
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/1cbyc/go-todo-api/internal/services"
+	"github.com/1cbyc/go-todo-api/pkg/response"
+	"github.com/1cbyc/go-todo-api/pkg/validator"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// CommentHandler handles HTTP requests for comment operations
+type CommentHandler struct {
+	service services.CommentService
+	logger  zerolog.Logger
+}
+
+// NewCommentHandler creates a new comment handler
+func NewCommentHandler(service services.CommentService) *CommentHandler {
+	return &CommentHandler{
+		service: service,
+		logger:  zerolog.Logger{},
+	}
+}
+
+// List handles GET /api/v1/todos/:id/comments
+// @Summary Get comments on a todo
+// @Description Get the comment thread of a todo with pagination, oldest first
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "Todo ID" format(uuid)
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Items per page" default(20)
+// @Success 200 {object} response.SuccessResponse{data=models.CommentListResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /todos/{id}/comments [get]
+func (h *CommentHandler) List(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid todo ID", err)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+
+	comments, err := h.service.List(c.Request.Context(), id, page, perPage)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to read todos", nil)
+		case errors.Is(err, services.ErrTodoNotFound):
+			response.NotFound(c, "Todo not found", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to get comments")
+			response.InternalServerError(c, "Failed to get comments", err)
+		}
+		return
+	}
+
+	response.OK(c, "Comments retrieved successfully", comments)
+}
+
+// Create handles POST /api/v1/todos/:id/comments
+// @Summary Comment on a todo
+// @Description Add a comment to the thread of a todo
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "Todo ID" format(uuid)
+// @Param comment body models.CreateCommentRequest true "Comment"
+// @Success 201 {object} response.SuccessResponse{data=models.CommentResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /todos/{id}/comments [post]
+func (h *CommentHandler) Create(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid todo ID", err)
+		return
+	}
+
+	var req models.CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate.Struct(req); err != nil {
+		response.BadRequest(c, "Validation failed", err)
+		return
+	}
+
+	comment, err := h.service.Create(c.Request.Context(), id, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to comment on todos", nil)
+		case errors.Is(err, services.ErrBlankComment):
+			response.BadRequest(c, "Comment must not be blank", nil)
+		case errors.Is(err, services.ErrTodoNotFound):
+			response.NotFound(c, "Todo not found", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to create comment")
+			response.InternalServerError(c, "Failed to create comment", err)
+		}
+		return
+	}
+
+	response.Created(c, "Comment created successfully", comment)
+}
+
+// Update handles PUT /api/v1/todos/:id/comments/:comment_id
+// @Summary Edit a comment
+// @Description Edit the body of a comment. Only its author can edit it.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "Todo ID" format(uuid)
+// @Param comment_id path string true "Comment ID" format(uuid)
+// @Param comment body models.UpdateCommentRequest true "New body"
+// @Success 200 {object} response.SuccessResponse{data=models.CommentResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /todos/{id}/comments/{comment_id} [put]
+func (h *CommentHandler) Update(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid todo ID", err)
+		return
+	}
+	commentID, err := uuid.Parse(c.Param("comment_id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid comment ID", err)
+		return
+	}
+
+	var req models.UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate.Struct(req); err != nil {
+		response.BadRequest(c, "Validation failed", err)
+		return
+	}
+
+	comment, err := h.service.Update(c.Request.Context(), id, commentID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to comment on todos", nil)
+		case errors.Is(err, services.ErrNotCommentAuthor):
+			response.Forbidden(c, "Only the author can edit a comment", nil)
+		case errors.Is(err, services.ErrBlankComment):
+			response.BadRequest(c, "Comment must not be blank", nil)
+		case errors.Is(err, services.ErrCommentNotFound):
+			response.NotFound(c, "Comment not found", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to update comment")
+			response.InternalServerError(c, "Failed to update comment", err)
+		}
+		return
+	}
+
+	response.OK(c, "Comment updated successfully", comment)
+}
+
+// Delete handles DELETE /api/v1/todos/:id/comments/:comment_id
+// @Summary Delete a comment
+// @Description Delete a comment. Only its author can delete it.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "Todo ID" format(uuid)
+// @Param comment_id path string true "Comment ID" format(uuid)
+// @Success 200 {object} response.SuccessResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /todos/{id}/comments/{comment_id} [delete]
+func (h *CommentHandler) Delete(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid todo ID", err)
+		return
+	}
+	commentID, err := uuid.Parse(c.Param("comment_id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid comment ID", err)
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), id, commentID); err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to comment on todos", nil)
+		case errors.Is(err, services.ErrNotCommentAuthor):
+			response.Forbidden(c, "Only the author can delete a comment", nil)
+		case errors.Is(err, services.ErrCommentNotFound):
+			response.NotFound(c, "Comment not found", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to delete comment")
+			response.InternalServerError(c, "Failed to delete comment", err)
+		}
+		return
+	}
+
+	response.OK(c, "Comment deleted successfully", nil)
+}
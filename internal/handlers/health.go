@@ -39,11 +39,15 @@ func HealthCheck(c *gin.Context) {
 
 // Metrics handles GET /api/v1/metrics
 // @Summary Prometheus metrics
-// @Description Get Prometheus metrics
+// @Description Get Prometheus metrics (admin only)
 // @Tags metrics
 // @Accept json
 // @Produce text/plain
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Success 200 {string} string
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
 // @Router /metrics [get]
 func Metrics(c *gin.Context) {
 	promhttp.Handler().ServeHTTP(c.Writer, c.Request)
@@ -0,0 +1,340 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/1cbyc/go-todo-api/internal/auth"
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/1cbyc/go-todo-api/internal/services"
+	"github.com/1cbyc/go-todo-api/pkg/response"
+	"github.com/1cbyc/go-todo-api/pkg/validator"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// ListHandler handles HTTP requests for list operations
+type ListHandler struct {
+	service services.ListService
+	logger  zerolog.Logger
+}
+
+// NewListHandler creates a new list handler
+func NewListHandler(service services.ListService) *ListHandler {
+	return &ListHandler{
+		service: service,
+		logger:  zerolog.Logger{},
+	}
+}
+
+// Create handles POST /api/v1/lists
+// @Summary Create a list
+// @Description Create a list to group todos in the current workspace
+// @Tags lists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param list body models.CreateListRequest true "List to create"
+// @Success 201 {object} response.SuccessResponse{data=models.ListResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /lists [post]
+func (h *ListHandler) Create(c *gin.Context) {
+	var req models.CreateListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate.Struct(req); err != nil {
+		response.BadRequest(c, "Validation failed", err)
+		return
+	}
+
+	list, err := h.service.Create(c.Request.Context(), &req)
+	if err != nil {
+		if errors.Is(err, auth.ErrForbidden) {
+			response.Forbidden(c, "You do not have permission to create lists", nil)
+			return
+		}
+		h.logger.Error().Err(err).Msg("Failed to create list")
+		response.InternalServerError(c, "Failed to create list", err)
+		return
+	}
+
+	response.Created(c, "List created successfully", list)
+}
+
+// GetByID handles GET /api/v1/lists/:id
+// @Summary Get a list by ID
+// @Description Get a list with its open, completed and overdue todo counts
+// @Tags lists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "List ID" format(uuid)
+// @Success 200 {object} response.SuccessResponse{data=models.ListResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /lists/{id} [get]
+func (h *ListHandler) GetByID(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid list ID", err)
+		return
+	}
+
+	list, err := h.service.GetByID(c.Request.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to read lists", nil)
+		case errors.Is(err, services.ErrListNotFound):
+			response.NotFound(c, "List not found", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to get list")
+			response.InternalServerError(c, "Failed to get list", err)
+		}
+		return
+	}
+
+	response.OK(c, "List retrieved successfully", list)
+}
+
+// GetAll handles GET /api/v1/lists
+// @Summary Get all lists
+// @Description Get all lists of the current workspace with their todo counts
+// @Tags lists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Success 200 {object} response.SuccessResponse{data=[]models.ListResponse}
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /lists [get]
+func (h *ListHandler) GetAll(c *gin.Context) {
+	lists, err := h.service.GetAll(c.Request.Context())
+	if err != nil {
+		if errors.Is(err, auth.ErrForbidden) {
+			response.Forbidden(c, "You do not have permission to read lists", nil)
+			return
+		}
+		h.logger.Error().Err(err).Msg("Failed to get lists")
+		response.InternalServerError(c, "Failed to get lists", err)
+		return
+	}
+
+	response.OK(c, "Lists retrieved successfully", lists)
+}
+
+// Update handles PUT /api/v1/lists/:id
+// @Summary Update a list
+// @Description Rename a list or change its description
+// @Tags lists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "List ID" format(uuid)
+// @Param list body models.UpdateListRequest true "List updates"
+// @Success 200 {object} response.SuccessResponse{data=models.ListResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /lists/{id} [put]
+func (h *ListHandler) Update(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid list ID", err)
+		return
+	}
+
+	var req models.UpdateListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate.Struct(req); err != nil {
+		response.BadRequest(c, "Validation failed", err)
+		return
+	}
+
+	list, err := h.service.Update(c.Request.Context(), id, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to update lists", nil)
+		case errors.Is(err, services.ErrListNotFound):
+			response.NotFound(c, "List not found", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to update list")
+			response.InternalServerError(c, "Failed to update list", err)
+		}
+		return
+	}
+
+	response.OK(c, "List updated successfully", list)
+}
+
+// GetWorkflow handles GET /api/v1/lists/:id/workflow
+// @Summary Get the workflow of a list
+// @Description Get the statuses and allowed transitions of a list's todos; lists without a workflow of their own use the default one
+// @Tags lists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "List ID" format(uuid)
+// @Success 200 {object} response.SuccessResponse{data=models.Workflow}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /lists/{id}/workflow [get]
+func (h *ListHandler) GetWorkflow(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid list ID", err)
+		return
+	}
+
+	workflow, err := h.service.GetWorkflow(c.Request.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to read lists", nil)
+		case errors.Is(err, services.ErrListNotFound):
+			response.NotFound(c, "List not found", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to get workflow")
+			response.InternalServerError(c, "Failed to get workflow", err)
+		}
+		return
+	}
+
+	response.OK(c, "Workflow retrieved successfully", workflow)
+}
+
+// UpdateWorkflow handles PUT /api/v1/lists/:id/workflow
+// @Summary Replace the workflow of a list
+// @Description Set the statuses and allowed transitions of a list's todos. Omitting transitions allows every move. Statuses that todos of the list are still in cannot be removed.
+// @Tags lists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "List ID" format(uuid)
+// @Param workflow body models.Workflow true "Workflow"
+// @Success 200 {object} response.SuccessResponse{data=models.Workflow}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /lists/{id}/workflow [put]
+func (h *ListHandler) UpdateWorkflow(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid list ID", err)
+		return
+	}
+
+	var req models.Workflow
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate.Struct(req); err != nil {
+		response.BadRequest(c, "Validation failed", err)
+		return
+	}
+
+	workflow, err := h.service.UpdateWorkflow(c.Request.Context(), id, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to update lists", nil)
+		case errors.Is(err, services.ErrListNotFound):
+			response.NotFound(c, "List not found", nil)
+		case errors.Is(err, services.ErrInvalidWorkflow):
+			response.BadRequest(c, "Invalid workflow", err.Error())
+		case errors.Is(err, services.ErrStatusInUse):
+			response.Conflict(c, "Status is still in use", err.Error())
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to update workflow")
+			response.InternalServerError(c, "Failed to update workflow", err)
+		}
+		return
+	}
+
+	response.OK(c, "Workflow updated successfully", workflow)
+}
+
+// Delete handles DELETE /api/v1/lists/:id
+// @Summary Delete a list
+// @Description Delete a list; its todos stay in the workspace without a list
+// @Tags lists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "List ID" format(uuid)
+// @Success 200 {object} response.SuccessResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /lists/{id} [delete]
+func (h *ListHandler) Delete(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid list ID", err)
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), id); err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to delete lists", nil)
+		case errors.Is(err, services.ErrListNotFound):
+			response.NotFound(c, "List not found", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to delete list")
+			response.InternalServerError(c, "Failed to delete list", err)
+		}
+		return
+	}
+
+	response.OK(c, "List deleted successfully", nil)
+}
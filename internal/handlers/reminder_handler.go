@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/1cbyc/go-todo-api/internal/auth"
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/1cbyc/go-todo-api/internal/services"
+	"github.com/1cbyc/go-todo-api/pkg/response"
+	"github.com/1cbyc/go-todo-api/pkg/validator"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// ReminderHandler handles HTTP requests for reminder operations
+type ReminderHandler struct {
+	service services.ReminderService
+	logger  zerolog.Logger
+}
+
+// NewReminderHandler creates a new reminder handler
+func NewReminderHandler(service services.ReminderService) *ReminderHandler {
+	return &ReminderHandler{
+		service: service,
+		logger:  zerolog.Logger{},
+	}
+}
+
+// List handles GET /api/v1/todos/:id/reminders
+// @Summary Get reminders of a todo
+// @Description Get the reminders you have set on a todo, earliest first
+// @Tags reminders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "Todo ID" format(uuid)
+// @Success 200 {object} response.SuccessResponse{data=[]models.Reminder}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /todos/{id}/reminders [get]
+func (h *ReminderHandler) List(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid todo ID", err)
+		return
+	}
+
+	reminders, err := h.service.List(c.Request.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to read todos", nil)
+		case errors.Is(err, services.ErrTodoNotFound):
+			response.NotFound(c, "Todo not found", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to get reminders")
+			response.InternalServerError(c, "Failed to get reminders", err)
+		}
+		return
+	}
+
+	response.OK(c, "Reminders retrieved successfully", reminders)
+}
+
+// Create handles POST /api/v1/todos/:id/reminders
+// @Summary Add a reminder to a todo
+// @Description Remind yourself about a todo at a fixed time (remind_at) or a number of minutes before it is due (offset_minutes). Offset reminders follow the due date when it changes. Email reminders go to your account's address.
+// @Tags reminders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "Todo ID" format(uuid)
+// @Param reminder body models.CreateReminderRequest true "Reminder"
+// @Success 201 {object} response.SuccessResponse{data=models.Reminder}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /todos/{id}/reminders [post]
+func (h *ReminderHandler) Create(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid todo ID", err)
+		return
+	}
+
+	var req models.CreateReminderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate.Struct(req); err != nil {
+		response.BadRequest(c, "Validation failed", err)
+		return
+	}
+
+	reminder, err := h.service.Create(c.Request.Context(), id, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to update todos", nil)
+		case errors.Is(err, services.ErrInvalidReminder),
+			errors.Is(err, services.ErrReminderInPast),
+			errors.Is(err, services.ErrChannelUnavailable):
+			response.BadRequest(c, "Invalid reminder", err.Error())
+		case errors.Is(err, services.ErrTodoNotFound):
+			response.NotFound(c, "Todo not found", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to create reminder")
+			response.InternalServerError(c, "Failed to create reminder", err)
+		}
+		return
+	}
+
+	response.Created(c, "Reminder created successfully", reminder)
+}
+
+// Delete handles DELETE /api/v1/todos/:id/reminders/:reminder_id
+// @Summary Delete a reminder
+// @Description Delete one of your reminders on a todo
+// @Tags reminders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "Todo ID" format(uuid)
+// @Param reminder_id path string true "Reminder ID" format(uuid)
+// @Success 200 {object} response.SuccessResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /todos/{id}/reminders/{reminder_id} [delete]
+func (h *ReminderHandler) Delete(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid todo ID", err)
+		return
+	}
+	reminderID, err := uuid.Parse(c.Param("reminder_id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid reminder ID", err)
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), id, reminderID); err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to update todos", nil)
+		case errors.Is(err, services.ErrReminderNotFound):
+			response.NotFound(c, "Reminder not found", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to delete reminder")
+			response.InternalServerError(c, "Failed to delete reminder", err)
+		}
+		return
+	}
+
+	response.OK(c, "Reminder deleted successfully", nil)
+}
@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/1cbyc/go-todo-api/internal/auth"
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/1cbyc/go-todo-api/internal/services"
+	"github.com/1cbyc/go-todo-api/pkg/response"
+	"github.com/1cbyc/go-todo-api/pkg/validator"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// TagHandler handles HTTP requests for tag operations
+type TagHandler struct {
+	service services.TagService
+	logger  zerolog.Logger
+}
+
+// NewTagHandler creates a new tag handler
+func NewTagHandler(service services.TagService) *TagHandler {
+	return &TagHandler{
+		service: service,
+		logger:  zerolog.Logger{},
+	}
+}
+
+// GetAll handles GET /api/v1/tags
+// @Summary Get all tags
+// @Description Get all tags of the current workspace with the number of todos carrying each
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Success 200 {object} response.SuccessResponse{data=[]models.TagResponse}
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /tags [get]
+func (h *TagHandler) GetAll(c *gin.Context) {
+	tags, err := h.service.GetAll(c.Request.Context())
+	if err != nil {
+		if errors.Is(err, auth.ErrForbidden) {
+			response.Forbidden(c, "You do not have permission to read tags", nil)
+			return
+		}
+		h.logger.Error().Err(err).Msg("Failed to get tags")
+		response.InternalServerError(c, "Failed to get tags", err)
+		return
+	}
+
+	response.OK(c, "Tags retrieved successfully", tags)
+}
+
+// Rename handles PATCH /api/v1/tags/:id
+// @Summary Rename a tag
+// @Description Rename a tag on every todo carrying it. Renaming onto an existing tag is refused; merge the tags instead.
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "Tag ID" format(uuid)
+// @Param tag body models.RenameTagRequest true "New name"
+// @Success 200 {object} response.SuccessResponse{data=models.TagResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /tags/{id} [patch]
+func (h *TagHandler) Rename(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid tag ID", err)
+		return
+	}
+
+	var req models.RenameTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate.Struct(req); err != nil {
+		response.BadRequest(c, "Validation failed", err)
+		return
+	}
+
+	tag, err := h.service.Rename(c.Request.Context(), id, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to rename tags", nil)
+		case errors.Is(err, services.ErrBlankTagName):
+			response.BadRequest(c, "Tag name must not be blank", nil)
+		case errors.Is(err, services.ErrTagNotFound):
+			response.NotFound(c, "Tag not found", nil)
+		case errors.Is(err, services.ErrTagExists):
+			response.Conflict(c, "A tag with this name already exists; merge the tags instead", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to rename tag")
+			response.InternalServerError(c, "Failed to rename tag", err)
+		}
+		return
+	}
+
+	response.OK(c, "Tag renamed successfully", tag)
+}
+
+// Merge handles POST /api/v1/tags/:id/merge
+// @Summary Merge a tag into another
+// @Description Move every todo carrying the tag to the target tag and delete the tag, in one transaction
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "Tag ID to merge away" format(uuid)
+// @Param merge body models.MergeTagRequest true "Tag to merge into"
+// @Success 200 {object} response.SuccessResponse{data=models.TagResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /tags/{id}/merge [post]
+func (h *TagHandler) Merge(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid tag ID", err)
+		return
+	}
+
+	var req models.MergeTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate.Struct(req); err != nil {
+		response.BadRequest(c, "Validation failed", err)
+		return
+	}
+
+	tag, err := h.service.Merge(c.Request.Context(), id, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to merge tags", nil)
+		case errors.Is(err, services.ErrMergeIntoSelf):
+			response.BadRequest(c, "Cannot merge a tag into itself", nil)
+		case errors.Is(err, services.ErrTagNotFound):
+			response.NotFound(c, "Tag not found", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to merge tags")
+			response.InternalServerError(c, "Failed to merge tags", err)
+		}
+		return
+	}
+
+	response.OK(c, "Tags merged successfully", tag)
+}
+
+// Delete handles DELETE /api/v1/tags/:id
+// @Summary Delete a tag
+// @Description Delete a tag and remove it from every todo
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "Tag ID" format(uuid)
+// @Success 200 {object} response.SuccessResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /tags/{id} [delete]
+func (h *TagHandler) Delete(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid tag ID", err)
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), id); err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to delete tags", nil)
+		case errors.Is(err, services.ErrTagNotFound):
+			response.NotFound(c, "Tag not found", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to delete tag")
+			response.InternalServerError(c, "Failed to delete tag", err)
+		}
+		return
+	}
+
+	response.OK(c, "Tag deleted successfully", nil)
+}
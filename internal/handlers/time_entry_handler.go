@@ -0,0 +1,482 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/1cbyc/go-todo-api/internal/auth"
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/1cbyc/go-todo-api/internal/services"
+	"github.com/1cbyc/go-todo-api/pkg/response"
+	"github.com/1cbyc/go-todo-api/pkg/validator"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// defaultReportDays is the number of days a time report covers when no range is given
+const defaultReportDays = 30
+
+// TimeEntryHandler handles HTTP requests for time tracking operations
+type TimeEntryHandler struct {
+	service services.TimeEntryService
+	logger  zerolog.Logger
+}
+
+// NewTimeEntryHandler creates a new time entry handler
+func NewTimeEntryHandler(service services.TimeEntryService) *TimeEntryHandler {
+	return &TimeEntryHandler{
+		service: service,
+		logger:  zerolog.Logger{},
+	}
+}
+
+// Start handles POST /api/v1/todos/:id/timer/start
+// @Summary Start a timer
+// @Description Start tracking time on a todo. A timer you have running on any todo is stopped first.
+// @Tags time tracking
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "Todo ID" format(uuid)
+// @Param timer body models.StartTimerRequest false "Timer note"
+// @Success 201 {object} response.SuccessResponse{data=models.TimeEntryResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /todos/{id}/timer/start [post]
+func (h *TimeEntryHandler) Start(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid todo ID", err)
+		return
+	}
+
+	// The body is optional
+	var req models.StartTimerRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate.Struct(req); err != nil {
+		response.BadRequest(c, "Validation failed", err)
+		return
+	}
+
+	entry, err := h.service.Start(c.Request.Context(), id, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to track time", nil)
+		case errors.Is(err, services.ErrTodoNotFound):
+			response.NotFound(c, "Todo not found", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to start timer")
+			response.InternalServerError(c, "Failed to start timer", err)
+		}
+		return
+	}
+
+	response.Created(c, "Timer started successfully", entry)
+}
+
+// Stop handles POST /api/v1/todos/:id/timer/stop
+// @Summary Stop a timer
+// @Description Stop your running timer on a todo and record the time entry
+// @Tags time tracking
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "Todo ID" format(uuid)
+// @Success 200 {object} response.SuccessResponse{data=models.TimeEntryResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /todos/{id}/timer/stop [post]
+func (h *TimeEntryHandler) Stop(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid todo ID", err)
+		return
+	}
+
+	entry, err := h.service.Stop(c.Request.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to track time", nil)
+		case errors.Is(err, services.ErrNoRunningTimer):
+			response.Conflict(c, "No timer is running on this todo", nil)
+		case errors.Is(err, services.ErrTodoNotFound):
+			response.NotFound(c, "Todo not found", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to stop timer")
+			response.InternalServerError(c, "Failed to stop timer", err)
+		}
+		return
+	}
+
+	response.OK(c, "Timer stopped successfully", entry)
+}
+
+// List handles GET /api/v1/todos/:id/time-entries
+// @Summary Get time entries on a todo
+// @Description Get the time tracked on a todo with pagination, latest first. Running timers report the time elapsed so far.
+// @Tags time tracking
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "Todo ID" format(uuid)
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Items per page" default(20)
+// @Success 200 {object} response.SuccessResponse{data=models.TimeEntryListResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /todos/{id}/time-entries [get]
+func (h *TimeEntryHandler) List(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid todo ID", err)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+
+	entries, err := h.service.List(c.Request.Context(), id, page, perPage)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to read todos", nil)
+		case errors.Is(err, services.ErrTodoNotFound):
+			response.NotFound(c, "Todo not found", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to get time entries")
+			response.InternalServerError(c, "Failed to get time entries", err)
+		}
+		return
+	}
+
+	response.OK(c, "Time entries retrieved successfully", entries)
+}
+
+// Create handles POST /api/v1/todos/:id/time-entries
+// @Summary Log time on a todo
+// @Description Record time you spent on a todo without running a timer
+// @Tags time tracking
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "Todo ID" format(uuid)
+// @Param entry body models.CreateTimeEntryRequest true "Time entry"
+// @Success 201 {object} response.SuccessResponse{data=models.TimeEntryResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /todos/{id}/time-entries [post]
+func (h *TimeEntryHandler) Create(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid todo ID", err)
+		return
+	}
+
+	var req models.CreateTimeEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate.Struct(req); err != nil {
+		response.BadRequest(c, "Validation failed", err)
+		return
+	}
+
+	entry, err := h.service.Create(c.Request.Context(), id, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to track time", nil)
+		case errors.Is(err, services.ErrInvalidTimeEntry):
+			response.BadRequest(c, "Invalid time entry", err.Error())
+		case errors.Is(err, services.ErrTodoNotFound):
+			response.NotFound(c, "Todo not found", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to create time entry")
+			response.InternalServerError(c, "Failed to create time entry", err)
+		}
+		return
+	}
+
+	response.Created(c, "Time entry created successfully", entry)
+}
+
+// Update handles PUT /api/v1/todos/:id/time-entries/:entry_id
+// @Summary Correct a time entry
+// @Description Change the times or note of a time entry. Only the user who tracked it can change it; setting ended_at on a running timer stops it.
+// @Tags time tracking
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "Todo ID" format(uuid)
+// @Param entry_id path string true "Time entry ID" format(uuid)
+// @Param entry body models.UpdateTimeEntryRequest true "Fields to change"
+// @Success 200 {object} response.SuccessResponse{data=models.TimeEntryResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /todos/{id}/time-entries/{entry_id} [put]
+func (h *TimeEntryHandler) Update(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid todo ID", err)
+		return
+	}
+	entryID, err := uuid.Parse(c.Param("entry_id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid time entry ID", err)
+		return
+	}
+
+	var req models.UpdateTimeEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate.Struct(req); err != nil {
+		response.BadRequest(c, "Validation failed", err)
+		return
+	}
+
+	entry, err := h.service.Update(c.Request.Context(), id, entryID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to track time", nil)
+		case errors.Is(err, services.ErrNotTimeEntryOwner):
+			response.Forbidden(c, "Only the user who tracked the time can change it", nil)
+		case errors.Is(err, services.ErrInvalidTimeEntry):
+			response.BadRequest(c, "Invalid time entry", err.Error())
+		case errors.Is(err, services.ErrTimeEntryNotFound):
+			response.NotFound(c, "Time entry not found", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to update time entry")
+			response.InternalServerError(c, "Failed to update time entry", err)
+		}
+		return
+	}
+
+	response.OK(c, "Time entry updated successfully", entry)
+}
+
+// Delete handles DELETE /api/v1/todos/:id/time-entries/:entry_id
+// @Summary Delete a time entry
+// @Description Delete a time entry. Only the user who tracked it can delete it.
+// @Tags time tracking
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "Todo ID" format(uuid)
+// @Param entry_id path string true "Time entry ID" format(uuid)
+// @Success 200 {object} response.SuccessResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /todos/{id}/time-entries/{entry_id} [delete]
+func (h *TimeEntryHandler) Delete(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid todo ID", err)
+		return
+	}
+	entryID, err := uuid.Parse(c.Param("entry_id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid time entry ID", err)
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), id, entryID); err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to track time", nil)
+		case errors.Is(err, services.ErrNotTimeEntryOwner):
+			response.Forbidden(c, "Only the user who tracked the time can delete it", nil)
+		case errors.Is(err, services.ErrTimeEntryNotFound):
+			response.NotFound(c, "Time entry not found", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to delete time entry")
+			response.InternalServerError(c, "Failed to delete time entry", err)
+		}
+		return
+	}
+
+	response.OK(c, "Time entry deleted successfully", nil)
+}
+
+// Report handles GET /api/v1/time-entries/report
+// @Summary Get a time report
+// @Description Sum the finished time entries of the workspace that started within a range of UTC days, grouped by day, list or tag. An entry counts toward each tag of its todo, so tag rows can add up to more than the total.
+// @Tags time tracking
+// @Accept json
+// @Produce json,text/csv
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param from query string false "First day, YYYY-MM-DD (defaults to 29 days before to)"
+// @Param to query string false "Last day, YYYY-MM-DD (defaults to today)"
+// @Param group_by query string false "Grouping" Enums(day, list, tag) default(day)
+// @Param user_id query string false "Only count time tracked by this user" format(uuid)
+// @Param format query string false "Response format" Enums(json, csv) default(json)
+// @Success 200 {object} response.SuccessResponse{data=models.TimeReport}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /time-entries/report [get]
+func (h *TimeEntryHandler) Report(c *gin.Context) {
+	filter, err := parseTimeReportFilter(c)
+	if err != nil {
+		response.BadRequest(c, "Invalid report", err.Error())
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		response.BadRequest(c, "Invalid report", `format must be "json" or "csv"`)
+		return
+	}
+
+	report, err := h.service.Report(c.Request.Context(), filter)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to read todos", nil)
+		case errors.Is(err, services.ErrInvalidReport):
+			response.BadRequest(c, "Invalid report", err.Error())
+		default:
+			h.logger.Error().Err(err).Msg("Failed to get time report")
+			response.InternalServerError(c, "Failed to get time report", err)
+		}
+		return
+	}
+
+	if format == "csv" {
+		writeTimeReportCSV(c, report)
+		return
+	}
+
+	response.OK(c, "Time report retrieved successfully", report)
+}
+
+// parseTimeReportFilter reads the report query parameters
+func parseTimeReportFilter(c *gin.Context) (models.TimeReportFilter, error) {
+	var filter models.TimeReportFilter
+
+	filter.To = time.Now().UTC().Truncate(24 * time.Hour)
+	if to := c.Query("to"); to != "" {
+		day, err := time.Parse(time.DateOnly, to)
+		if err != nil {
+			return filter, fmt.Errorf("to must be a date like 2024-01-31")
+		}
+		filter.To = day
+	}
+
+	filter.From = filter.To.AddDate(0, 0, 1-defaultReportDays)
+	if from := c.Query("from"); from != "" {
+		day, err := time.Parse(time.DateOnly, from)
+		if err != nil {
+			return filter, fmt.Errorf("from must be a date like 2024-01-01")
+		}
+		filter.From = day
+	}
+
+	switch group := models.TimeReportGroup(c.DefaultQuery("group_by", string(models.TimeReportByDay))); group {
+	case models.TimeReportByDay, models.TimeReportByList, models.TimeReportByTag:
+		filter.GroupBy = group
+	default:
+		return filter, fmt.Errorf("group_by must be %q, %q or %q", models.TimeReportByDay, models.TimeReportByList, models.TimeReportByTag)
+	}
+
+	if userID := c.Query("user_id"); userID != "" {
+		id, err := uuid.Parse(userID)
+		if err != nil {
+			return filter, fmt.Errorf("user_id must be a UUID")
+		}
+		filter.UserID = &id
+	}
+
+	return filter, nil
+}
+
+// writeTimeReportCSV writes a time report as a CSV download with a closing total row
+func writeTimeReportCSV(c *gin.Context, report *models.TimeReport) {
+	filename := fmt.Sprintf("time-report-%s-%s.csv", report.From, report.To)
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write([]string{"key", "label", "seconds", "hours", "entries"})
+	for _, row := range report.Rows {
+		_ = w.Write([]string{
+			row.Key,
+			row.Label,
+			strconv.FormatInt(row.Seconds, 10),
+			strconv.FormatFloat(row.Hours, 'f', 2, 64),
+			strconv.Itoa(row.Entries),
+		})
+	}
+	_ = w.Write([]string{
+		"",
+		"Total",
+		strconv.FormatInt(report.TotalSeconds, 10),
+		strconv.FormatFloat(report.TotalHours, 'f', 2, 64),
+		strconv.Itoa(report.TotalEntries),
+	})
+	w.Flush()
+	if err := w.Error(); err != nil {
+		c.Error(err)
+	}
+}
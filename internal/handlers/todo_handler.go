@@ -1,9 +1,18 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/1cbyc/go-todo-api/internal/auth"
+	"github.com/1cbyc/go-todo-api/internal/config"
 	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/1cbyc/go-todo-api/internal/query"
 	"github.com/1cbyc/go-todo-api/internal/services"
 	"github.com/1cbyc/go-todo-api/pkg/response"
 	"github.com/1cbyc/go-todo-api/pkg/validator"
@@ -15,26 +24,36 @@ import (
 // TodoHandler handles HTTP requests for todo operations
 type TodoHandler struct {
 	service services.TodoService
+	cfg     config.TodoConfig
 	logger  zerolog.Logger
 }
 
+// batchOperationMaxSize is the room allowed for each operation of a batch request body
+const batchOperationMaxSize = 16 << 10
+
 // NewTodoHandler creates a new todo handler
-func NewTodoHandler(service services.TodoService) *TodoHandler {
+func NewTodoHandler(service services.TodoService, cfg config.TodoConfig) *TodoHandler {
 	return &TodoHandler{
 		service: service,
+		cfg:     cfg,
 		logger:  zerolog.Logger{},
 	}
 }
 
 // Create handles POST /api/v1/todos
 // @Summary Create a new todo
-// @Description Create a new todo item
+// @Description Create a new todo item. A recurrence RRULE makes it the first occurrence of a series.
 // @Tags todos
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
 // @Param todo body models.CreateTodoRequest true "Todo to create"
 // @Success 201 {object} response.SuccessResponse{data=models.TodoResponse}
 // @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
 // @Failure 500 {object} response.ErrorResponse
 // @Router /todos [post]
 func (h *TodoHandler) Create(c *gin.Context) {
@@ -52,23 +71,127 @@ func (h *TodoHandler) Create(c *gin.Context) {
 
 	todo, err := h.service.Create(c.Request.Context(), &req)
 	if err != nil {
-		h.logger.Error().Err(err).Msg("Failed to create todo")
-		response.InternalServerError(c, "Failed to create todo", err)
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to create todos", nil)
+		case errors.Is(err, services.ErrListNotFound):
+			response.BadRequest(c, "List not found", nil)
+		case errors.Is(err, services.ErrParentNotFound):
+			response.BadRequest(c, "Parent todo not found", nil)
+		case errors.Is(err, services.ErrInvalidRecurrence):
+			response.BadRequest(c, "Invalid recurrence", err.Error())
+		case errors.Is(err, services.ErrUnknownStatus):
+			response.BadRequest(c, "Unknown status", err.Error())
+		default:
+			h.logger.Error().Err(err).Msg("Failed to create todo")
+			response.InternalServerError(c, "Failed to create todo", err)
+		}
 		return
 	}
 
 	response.Created(c, "Todo created successfully", todo)
 }
 
+// Batch handles POST /api/v1/todos/batch
+// @Summary Apply several todo operations at once
+// @Description Create, update, delete and toggle todos in one request, in order. Each operation gets the same checks as its single-todo endpoint. In atomic mode, the default, nothing is applied unless every operation succeeds, and a failure answers 422 with the operation that failed. In best_effort mode every operation is applied on its own and the response reports the status of each. Batches with malformed operations are rejected as a whole.
+// @Tags todos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param batch body models.BatchRequest true "Operations to apply"
+// @Success 200 {object} response.SuccessResponse{data=models.BatchResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 413 {object} response.ErrorResponse
+// @Failure 422 {object} response.ErrorResponse{error=models.BatchResponse}
+// @Failure 500 {object} response.ErrorResponse
+// @Router /todos/batch [post]
+func (h *TodoHandler) Batch(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, int64(h.cfg.BatchMaxSize)*batchOperationMaxSize)
+	var req models.BatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			response.PayloadTooLarge(c, "Batch is too large", nil)
+			return
+		}
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	// Refuse oversized batches before decoding their operations
+	if len(req.Operations) > h.cfg.BatchMaxSize {
+		response.PayloadTooLarge(c, "Batch is too large",
+			fmt.Sprintf("a batch holds at most %d operations, got %d", h.cfg.BatchMaxSize, len(req.Operations)))
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate.Struct(req); err != nil {
+		response.BadRequest(c, "Validation failed", err)
+		return
+	}
+	var invalid []batchError
+	for i := range req.Operations {
+		if err := decodeBatchOperation(&req.Operations[i]); err != nil {
+			invalid = append(invalid, batchError{Index: i, Error: err.Error()})
+		}
+	}
+	if len(invalid) > 0 {
+		response.BadRequest(c, "Validation failed", invalid)
+		return
+	}
+
+	batch, err := h.service.Batch(c.Request.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to update todos", nil)
+		case errors.Is(err, services.ErrBatchTooLarge):
+			response.PayloadTooLarge(c, "Batch is too large", err.Error())
+		default:
+			h.logger.Error().Err(err).Msg("Failed to apply batch")
+			response.InternalServerError(c, "Failed to apply batch", err)
+		}
+		return
+	}
+
+	for i := range batch.Results {
+		result := &batch.Results[i]
+		result.Status, result.Error = h.batchStatus(result)
+	}
+
+	// A failed atomic batch reports only the operation that failed
+	if batch.Mode == models.BatchModeAtomic && batch.Failed > 0 {
+		if batch.Results[0].Status == http.StatusInternalServerError {
+			response.InternalServerError(c, "Failed to apply batch", batch)
+		} else {
+			response.UnprocessableEntity(c, "Batch was rolled back", batch)
+		}
+		return
+	}
+
+	response.OK(c, "Batch applied successfully", batch)
+}
+
 // GetByID handles GET /api/v1/todos/:id
 // @Summary Get a todo by ID
 // @Description Get a specific todo item by its ID
 // @Tags todos
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
 // @Param id path string true "Todo ID" format(uuid)
 // @Success 200 {object} response.SuccessResponse{data=models.TodoResponse}
 // @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
 // @Failure 404 {object} response.ErrorResponse
 // @Failure 500 {object} response.ErrorResponse
 // @Router /todos/{id} [get]
@@ -82,6 +205,10 @@ func (h *TodoHandler) GetByID(c *gin.Context) {
 
 	todo, err := h.service.GetByID(c.Request.Context(), id)
 	if err != nil {
+		if errors.Is(err, auth.ErrForbidden) {
+			response.Forbidden(c, "You do not have permission to read todos", nil)
+			return
+		}
 		h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to get todo")
 		response.NotFound(c, "Todo not found", err)
 		return
@@ -92,24 +219,117 @@ func (h *TodoHandler) GetByID(c *gin.Context) {
 
 // GetAll handles GET /api/v1/todos
 // @Summary Get all todos
-// @Description Get all todo items with pagination
+// @Description Get all todo items with pagination, optionally filtered by tags, completion, priority and dates. Invalid filter values are rejected. Pages are numbered by default; with cursor or limit the listing is paged by signed cursors, which neither skip nor repeat todos when todos change between requests.
 // @Tags todos
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
 // @Param page query int false "Page number" default(1)
 // @Param per_page query int false "Items per page" default(20)
+// @Param q query string false "Query such as priority:high,urgent due<2026-11-01 -completed tag:backend \"invoice\""
+// @Param cursor query string false "Cursor from next_cursor or prev_cursor of a previous page; switches to cursor pagination"
+// @Param limit query int false "Items per page in cursor pagination; switches to cursor pagination" default(20)
+// @Param tags query string false "Comma-separated tag names to filter by"
+// @Param tag_mode query string false "Match todos with any or all of the tags" Enums(any, all) default(any)
+// @Param completed query bool false "Only completed or only open todos"
+// @Param priority query []string false "Priorities to include, repeated or comma-separated" collectionFormat(csv) Enums(low, medium, high, urgent)
+// @Param due_before query string false "Todos due before this date or timestamp (exclusive)"
+// @Param due_after query string false "Todos due at or after this date or timestamp"
+// @Param created_after query string false "Todos created at or after this date or timestamp"
+// @Param overdue query bool false "Only open todos whose due date has passed"
+// @Param has_due_date query bool false "Only todos with or without a due date"
+// @Param sort query string false "Comma-separated sort keys, descending with a leading -, e.g. -priority,due_date. Fields: created_at, updated_at, due_date, priority, title, status, completed, position" default(-created_at)
 // @Success 200 {object} response.SuccessResponse{data=models.TodoListResponse}
 // @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
 // @Failure 500 {object} response.ErrorResponse
 // @Router /todos [get]
 func (h *TodoHandler) GetAll(c *gin.Context) {
+	filter, err := parseTodoFilter(c)
+	if err != nil {
+		response.BadRequest(c, "Invalid filter", err.Error())
+		return
+	}
+	if err := applyTodoQuery(c, &filter); err != nil {
+		response.BadRequest(c, "Invalid query", err)
+		return
+	}
+
+	todos, err := h.getTodos(c, filter)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to read todos", nil)
+		case errors.Is(err, services.ErrInvalidCursor), errors.Is(err, errMixedPagination):
+			response.BadRequest(c, "Invalid pagination", err.Error())
+		default:
+			h.logger.Error().Err(err).Msg("Failed to get todos")
+			response.InternalServerError(c, "Failed to get todos", err)
+		}
+		return
+	}
+
+	response.OK(c, "Todos retrieved successfully", todos)
+}
+
+// Search handles GET /api/v1/todos/search
+// @Summary Search todos
+// @Description Find todos whose title or description contain words starting with every word of q, best matches first. Each result carries its rank and the title and an excerpt of the description as escaped HTML with the matching words wrapped in <mark> tags. Takes the same filters as the todo listing, except sort; q holds the words to search for, not a listing query.
+// @Tags todos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param q query string true "Words to search for"
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Items per page" default(20)
+// @Param tags query string false "Comma-separated tag names to filter by"
+// @Param tag_mode query string false "Match todos with any or all of the tags" Enums(any, all) default(any)
+// @Param completed query bool false "Only completed or only open todos"
+// @Param priority query []string false "Priorities to include, repeated or comma-separated" collectionFormat(csv) Enums(low, medium, high, urgent)
+// @Param due_before query string false "Todos due before this date or timestamp (exclusive)"
+// @Param due_after query string false "Todos due at or after this date or timestamp"
+// @Param created_after query string false "Todos created at or after this date or timestamp"
+// @Param overdue query bool false "Only open todos whose due date has passed"
+// @Param has_due_date query bool false "Only todos with or without a due date"
+// @Success 200 {object} response.SuccessResponse{data=models.TodoSearchResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Failure 503 {object} response.ErrorResponse
+// @Router /todos/search [get]
+func (h *TodoHandler) Search(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
 
-	todos, err := h.service.GetAll(c.Request.Context(), page, perPage)
+	filter, err := parseTodoFilter(c)
+	if err != nil {
+		response.BadRequest(c, "Invalid filter", err.Error())
+		return
+	}
+	if filter.Sort != nil {
+		response.BadRequest(c, "Invalid filter", "search results are ordered by relevance and cannot be sorted")
+		return
+	}
+
+	todos, err := h.service.Search(c.Request.Context(), c.Query("q"), filter, page, perPage)
 	if err != nil {
-		h.logger.Error().Err(err).Msg("Failed to get todos")
-		response.InternalServerError(c, "Failed to get todos", err)
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to read todos", nil)
+		case errors.Is(err, services.ErrInvalidSearch):
+			response.BadRequest(c, "Invalid search query", err.Error())
+		case errors.Is(err, services.ErrSearchUnavailable):
+			response.ServiceUnavailable(c, "Search is not available", err.Error())
+		default:
+			h.logger.Error().Err(err).Msg("Failed to search todos")
+			response.InternalServerError(c, "Failed to search todos", err)
+		}
 		return
 	}
 
@@ -122,11 +342,17 @@ func (h *TodoHandler) GetAll(c *gin.Context) {
 // @Tags todos
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
 // @Param id path string true "Todo ID" format(uuid)
 // @Param todo body models.UpdateTodoRequest true "Todo updates"
 // @Success 200 {object} response.SuccessResponse{data=models.TodoResponse}
 // @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
 // @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
 // @Failure 500 {object} response.ErrorResponse
 // @Router /todos/{id} [put]
 func (h *TodoHandler) Update(c *gin.Context) {
@@ -151,8 +377,21 @@ func (h *TodoHandler) Update(c *gin.Context) {
 
 	todo, err := h.service.Update(c.Request.Context(), id, &req)
 	if err != nil {
-		h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to update todo")
-		response.NotFound(c, "Todo not found", err)
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to update todos", nil)
+		case errors.Is(err, services.ErrTodoBlocked):
+			response.Conflict(c, "Todo is blocked by open todos", nil)
+		case errors.Is(err, services.ErrInvalidRecurrence):
+			response.BadRequest(c, "Invalid recurrence", err.Error())
+		case errors.Is(err, services.ErrNotRecurring):
+			response.BadRequest(c, "Todo is not recurring", nil)
+		case errors.Is(err, services.ErrTodoNotFound):
+			response.NotFound(c, "Todo not found", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to update todo")
+			response.InternalServerError(c, "Failed to update todo", err)
+		}
 		return
 	}
 
@@ -161,13 +400,20 @@ func (h *TodoHandler) Update(c *gin.Context) {
 
 // Delete handles DELETE /api/v1/todos/:id
 // @Summary Delete a todo
-// @Description Delete a todo item by its ID
+// @Description Move a todo item to the trash by its ID. Its subtasks become top-level todos, or are deleted too with subtasks=cascade. With permanent=true the todo, also one already in the trash, is deleted for good along with its deleted subtasks, history, comments, reminders and tracked time.
 // @Tags todos
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
 // @Param id path string true "Todo ID" format(uuid)
+// @Param subtasks query string false "What happens to subtasks" Enums(orphan, cascade) default(orphan)
+// @Param permanent query bool false "Delete for good instead of moving to the trash" default(false)
 // @Success 200 {object} response.SuccessResponse
 // @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
 // @Failure 404 {object} response.ErrorResponse
 // @Failure 500 {object} response.ErrorResponse
 // @Router /todos/{id} [delete]
@@ -179,25 +425,131 @@ func (h *TodoHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.Delete(c.Request.Context(), id); err != nil {
-		h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to delete todo")
-		response.NotFound(c, "Todo not found", err)
+	mode := models.SubtaskDeleteMode(c.DefaultQuery("subtasks", string(models.SubtaskDeleteOrphan)))
+	if mode != models.SubtaskDeleteOrphan && mode != models.SubtaskDeleteCascade {
+		response.BadRequest(c, "Invalid subtasks mode", fmt.Sprintf("subtasks must be %q or %q", models.SubtaskDeleteOrphan, models.SubtaskDeleteCascade))
+		return
+	}
+
+	permanent, err := strconv.ParseBool(c.DefaultQuery("permanent", "false"))
+	if err != nil {
+		response.BadRequest(c, "Invalid permanent flag", "permanent must be true or false")
+		return
+	}
+
+	if permanent {
+		err = h.service.Purge(c.Request.Context(), id, mode)
+	} else {
+		err = h.service.Delete(c.Request.Context(), id, mode)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to delete todos", nil)
+		case errors.Is(err, services.ErrTodoNotFound):
+			response.NotFound(c, "Todo not found", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to delete todo")
+			response.InternalServerError(c, "Failed to delete todo", err)
+		}
 		return
 	}
 
 	response.OK(c, "Todo deleted successfully", nil)
 }
 
+// Trash handles GET /api/v1/todos/trash
+// @Summary Get deleted todos
+// @Description Get the todos in the trash with pagination, most recently deleted first. Deleted todos are purged for good once they have been in the trash for the configured retention.
+// @Tags todos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Items per page" default(20)
+// @Success 200 {object} response.SuccessResponse{data=models.TodoListResponse}
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /todos/trash [get]
+func (h *TodoHandler) Trash(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+
+	todos, err := h.service.Trash(c.Request.Context(), page, perPage)
+	if err != nil {
+		if errors.Is(err, auth.ErrForbidden) {
+			response.Forbidden(c, "You do not have permission to read todos", nil)
+			return
+		}
+		h.logger.Error().Err(err).Msg("Failed to get deleted todos")
+		response.InternalServerError(c, "Failed to get deleted todos", err)
+		return
+	}
+
+	response.OK(c, "Deleted todos retrieved successfully", todos)
+}
+
+// Restore handles POST /api/v1/todos/:id/restore
+// @Summary Restore a deleted todo
+// @Description Take a todo out of the trash, together with the subtasks and comments that were deleted with it. A todo whose parent or list no longer exists comes back as a top-level todo or outside any list.
+// @Tags todos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "Todo ID" format(uuid)
+// @Success 200 {object} response.SuccessResponse{data=models.TodoResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /todos/{id}/restore [post]
+func (h *TodoHandler) Restore(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid todo ID", err)
+		return
+	}
+
+	todo, err := h.service.Restore(c.Request.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to update todos", nil)
+		case errors.Is(err, services.ErrTodoNotFound):
+			response.NotFound(c, "Todo not found in trash", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to restore todo")
+			response.InternalServerError(c, "Failed to restore todo", err)
+		}
+		return
+	}
+
+	response.OK(c, "Todo restored successfully", todo)
+}
+
 // Toggle handles PATCH /api/v1/todos/:id/toggle
 // @Summary Toggle todo completion status
-// @Description Toggle the completed status of a todo item
+// @Description Toggle the completed status of a todo item. Completing moves it to the first done status of its workflow and reopening to the first open status, regardless of the allowed transitions. A todo with open blockers cannot be completed.
 // @Tags todos
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
 // @Param id path string true "Todo ID" format(uuid)
 // @Success 200 {object} response.SuccessResponse{data=models.TodoResponse}
 // @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
 // @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
 // @Failure 500 {object} response.ErrorResponse
 // @Router /todos/{id}/toggle [patch]
 func (h *TodoHandler) Toggle(c *gin.Context) {
@@ -210,10 +562,1043 @@ func (h *TodoHandler) Toggle(c *gin.Context) {
 
 	todo, err := h.service.Toggle(c.Request.Context(), id)
 	if err != nil {
-		h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to toggle todo")
-		response.NotFound(c, "Todo not found", err)
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to update todos", nil)
+		case errors.Is(err, services.ErrTodoBlocked):
+			response.Conflict(c, "Todo is blocked by open todos", nil)
+		case errors.Is(err, services.ErrTodoNotFound):
+			response.NotFound(c, "Todo not found", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to toggle todo")
+			response.InternalServerError(c, "Failed to toggle todo", err)
+		}
 		return
 	}
 
 	response.OK(c, "Todo toggled successfully", todo)
 }
+
+// SetStatus handles PATCH /api/v1/todos/:id/status
+// @Summary Change the status of a todo
+// @Description Move a todo to another status of its list's workflow. Only the transitions the workflow allows are accepted; moving into a done status completes the todo.
+// @Tags todos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "Todo ID" format(uuid)
+// @Param status body models.SetStatusRequest true "Target status"
+// @Success 200 {object} response.SuccessResponse{data=models.TodoResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /todos/{id}/status [patch]
+func (h *TodoHandler) SetStatus(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid todo ID", err)
+		return
+	}
+
+	var req models.SetStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate.Struct(req); err != nil {
+		response.BadRequest(c, "Validation failed", err)
+		return
+	}
+
+	todo, err := h.service.SetStatus(c.Request.Context(), id, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to update todos", nil)
+		case errors.Is(err, services.ErrUnknownStatus):
+			response.BadRequest(c, "Unknown status", err.Error())
+		case errors.Is(err, services.ErrInvalidTransition):
+			response.Conflict(c, "Status transition not allowed", err.Error())
+		case errors.Is(err, services.ErrTodoBlocked):
+			response.Conflict(c, "Todo is blocked by open todos", nil)
+		case errors.Is(err, services.ErrTodoNotFound):
+			response.NotFound(c, "Todo not found", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to change todo status")
+			response.InternalServerError(c, "Failed to change todo status", err)
+		}
+		return
+	}
+
+	response.OK(c, "Todo status changed successfully", todo)
+}
+
+// GetByList handles GET /api/v1/lists/:id/todos
+// @Summary Get the todos of a list
+// @Description Get the todo items of a list with pagination
+// @Tags lists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "List ID" format(uuid)
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Items per page" default(20)
+// @Param q query string false "Query such as priority:high,urgent due<2026-11-01 -completed tag:backend \"invoice\""
+// @Param cursor query string false "Cursor from next_cursor or prev_cursor of a previous page; switches to cursor pagination"
+// @Param limit query int false "Items per page in cursor pagination; switches to cursor pagination" default(20)
+// @Param tags query string false "Comma-separated tag names to filter by"
+// @Param tag_mode query string false "Match todos with any or all of the tags" Enums(any, all) default(any)
+// @Param completed query bool false "Only completed or only open todos"
+// @Param priority query []string false "Priorities to include, repeated or comma-separated" collectionFormat(csv) Enums(low, medium, high, urgent)
+// @Param due_before query string false "Todos due before this date or timestamp (exclusive)"
+// @Param due_after query string false "Todos due at or after this date or timestamp"
+// @Param created_after query string false "Todos created at or after this date or timestamp"
+// @Param overdue query bool false "Only open todos whose due date has passed"
+// @Param has_due_date query bool false "Only todos with or without a due date"
+// @Param sort query string false "Comma-separated sort keys, descending with a leading -, e.g. -priority,due_date. Fields: created_at, updated_at, due_date, priority, title, status, completed, position" default(-created_at)
+// @Success 200 {object} response.SuccessResponse{data=models.TodoListResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /lists/{id}/todos [get]
+func (h *TodoHandler) GetByList(c *gin.Context) {
+	idStr := c.Param("id")
+	listID, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid list ID", err)
+		return
+	}
+
+	filter, err := parseTodoFilter(c)
+	if err != nil {
+		response.BadRequest(c, "Invalid filter", err.Error())
+		return
+	}
+	if err := applyTodoQuery(c, &filter); err != nil {
+		response.BadRequest(c, "Invalid query", err)
+		return
+	}
+	filter.ListID = &listID
+
+	todos, err := h.getTodos(c, filter)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to read todos", nil)
+		case errors.Is(err, services.ErrInvalidCursor), errors.Is(err, errMixedPagination):
+			response.BadRequest(c, "Invalid pagination", err.Error())
+		case errors.Is(err, services.ErrListNotFound):
+			response.NotFound(c, "List not found", nil)
+		default:
+			h.logger.Error().Err(err).Str("list_id", idStr).Msg("Failed to get todos")
+			response.InternalServerError(c, "Failed to get todos", err)
+		}
+		return
+	}
+
+	response.OK(c, "Todos retrieved successfully", todos)
+}
+
+// GetByView handles GET /api/v1/views/:id/todos
+// @Summary Get the todos of a view
+// @Description Evaluate a view and get the todo items it currently matches, in the view's sort order. Relative dates in the view are resolved at the time of the request.
+// @Tags views
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "View ID, or today, upcoming, overdue or no-due-date"
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Items per page" default(20)
+// @Param cursor query string false "Cursor from next_cursor or prev_cursor of a previous page; switches to cursor pagination"
+// @Param limit query int false "Items per page in cursor pagination; switches to cursor pagination" default(20)
+// @Success 200 {object} response.SuccessResponse{data=models.TodoListResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /views/{id}/todos [get]
+func (h *TodoHandler) GetByView(c *gin.Context) {
+	id := c.Param("id")
+
+	var todos *models.TodoListResponse
+	filter, err := h.service.ViewFilter(c.Request.Context(), id)
+	if err == nil {
+		todos, err = h.getTodos(c, filter)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to read todos", nil)
+		case errors.Is(err, services.ErrViewNotFound):
+			response.NotFound(c, "View not found", nil)
+		case errors.Is(err, services.ErrInvalidCursor), errors.Is(err, errMixedPagination):
+			response.BadRequest(c, "Invalid pagination", err.Error())
+		default:
+			h.logger.Error().Err(err).Str("view_id", id).Msg("Failed to get todos")
+			response.InternalServerError(c, "Failed to get todos", err)
+		}
+		return
+	}
+
+	response.OK(c, "Todos retrieved successfully", todos)
+}
+
+// CreateInList handles POST /api/v1/lists/:id/todos
+// @Summary Create a todo in a list
+// @Description Create a new todo item in the given list
+// @Tags lists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "List ID" format(uuid)
+// @Param todo body models.CreateTodoRequest true "Todo to create"
+// @Success 201 {object} response.SuccessResponse{data=models.TodoResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /lists/{id}/todos [post]
+func (h *TodoHandler) CreateInList(c *gin.Context) {
+	idStr := c.Param("id")
+	listID, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid list ID", err)
+		return
+	}
+
+	var req models.CreateTodoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate.Struct(req); err != nil {
+		response.BadRequest(c, "Validation failed", err)
+		return
+	}
+
+	// The list in the path always wins over one in the body
+	req.ListID = &listID
+
+	todo, err := h.service.Create(c.Request.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to create todos", nil)
+		case errors.Is(err, services.ErrListNotFound):
+			response.NotFound(c, "List not found", nil)
+		case errors.Is(err, services.ErrParentNotFound):
+			response.BadRequest(c, "Parent todo not found", nil)
+		case errors.Is(err, services.ErrInvalidRecurrence):
+			response.BadRequest(c, "Invalid recurrence", err.Error())
+		case errors.Is(err, services.ErrUnknownStatus):
+			response.BadRequest(c, "Unknown status", err.Error())
+		default:
+			h.logger.Error().Err(err).Str("list_id", idStr).Msg("Failed to create todo")
+			response.InternalServerError(c, "Failed to create todo", err)
+		}
+		return
+	}
+
+	response.Created(c, "Todo created successfully", todo)
+}
+
+// MoveToList handles PATCH /api/v1/todos/:id/list
+// @Summary Move a todo to another list
+// @Description Move a todo item to another list, or out of its list with a null list_id
+// @Tags todos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "Todo ID" format(uuid)
+// @Param move body models.MoveTodoRequest true "Target list"
+// @Success 200 {object} response.SuccessResponse{data=models.TodoResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /todos/{id}/list [patch]
+func (h *TodoHandler) MoveToList(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid todo ID", err)
+		return
+	}
+
+	var req models.MoveTodoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	todo, err := h.service.Move(c.Request.Context(), id, req.ListID)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to update todos", nil)
+		case errors.Is(err, services.ErrListNotFound):
+			response.BadRequest(c, "List not found", nil)
+		case errors.Is(err, services.ErrTodoNotFound):
+			response.NotFound(c, "Todo not found", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to move todo")
+			response.InternalServerError(c, "Failed to move todo", err)
+		}
+		return
+	}
+
+	response.OK(c, "Todo moved successfully", todo)
+}
+
+// Reorder handles POST /api/v1/todos/:id/move
+// @Summary Reorder a todo within its list
+// @Description Place a todo right after the todo `after`, right before the todo `before`, or between the two. Both must be in the todo's list. List todos with sort=position to get the manual order.
+// @Tags todos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "Todo ID" format(uuid)
+// @Param move body models.ReorderTodoRequest true "Neighbouring todos"
+// @Success 200 {object} response.SuccessResponse{data=models.TodoResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /todos/{id}/move [post]
+func (h *TodoHandler) Reorder(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid todo ID", err)
+		return
+	}
+
+	var req models.ReorderTodoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	todo, err := h.service.Reorder(c.Request.Context(), id, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to update todos", nil)
+		case errors.Is(err, services.ErrInvalidReorder):
+			response.BadRequest(c, "Invalid move", err.Error())
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to reorder todo")
+			response.NotFound(c, "Todo not found", err)
+		}
+		return
+	}
+
+	response.OK(c, "Todo moved successfully", todo)
+}
+
+// GetChildren handles GET /api/v1/todos/:id/children
+// @Summary Get the subtasks of a todo
+// @Description Get the direct subtasks of a todo with pagination
+// @Tags todos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "Todo ID" format(uuid)
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Items per page" default(20)
+// @Success 200 {object} response.SuccessResponse{data=models.TodoListResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /todos/{id}/children [get]
+func (h *TodoHandler) GetChildren(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid todo ID", err)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+
+	todos, err := h.service.GetAll(c.Request.Context(), models.TodoFilter{ParentID: &id}, page, perPage)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to read todos", nil)
+		case errors.Is(err, services.ErrParentNotFound):
+			response.NotFound(c, "Todo not found", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to get subtasks")
+			response.InternalServerError(c, "Failed to get subtasks", err)
+		}
+		return
+	}
+
+	response.OK(c, "Subtasks retrieved successfully", todos)
+}
+
+// GetTree handles GET /api/v1/todos/:id/tree
+// @Summary Get a todo with all of its subtasks
+// @Description Get a todo and its whole subtask hierarchy as a nested tree
+// @Tags todos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "Todo ID" format(uuid)
+// @Success 200 {object} response.SuccessResponse{data=models.TodoTreeResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /todos/{id}/tree [get]
+func (h *TodoHandler) GetTree(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid todo ID", err)
+		return
+	}
+
+	tree, err := h.service.GetTree(c.Request.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to read todos", nil)
+		case errors.Is(err, services.ErrTodoNotFound):
+			response.NotFound(c, "Todo not found", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to get todo tree")
+			response.InternalServerError(c, "Failed to get todo tree", err)
+		}
+		return
+	}
+
+	response.OK(c, "Todo tree retrieved successfully", tree)
+}
+
+// SetParent handles PATCH /api/v1/todos/:id/parent
+// @Summary Move a todo under another todo
+// @Description Make a todo a subtask of another todo, or a top-level todo with a null parent_id
+// @Tags todos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "Todo ID" format(uuid)
+// @Param parent body models.SetParentRequest true "New parent"
+// @Success 200 {object} response.SuccessResponse{data=models.TodoResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /todos/{id}/parent [patch]
+func (h *TodoHandler) SetParent(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid todo ID", err)
+		return
+	}
+
+	var req models.SetParentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	todo, err := h.service.SetParent(c.Request.Context(), id, req.ParentID)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to update todos", nil)
+		case errors.Is(err, services.ErrParentNotFound):
+			response.BadRequest(c, "Parent todo not found", nil)
+		case errors.Is(err, services.ErrSubtaskCycle):
+			response.Conflict(c, "A todo cannot be a subtask of itself or of its own subtasks", nil)
+		case errors.Is(err, services.ErrTodoNotFound):
+			response.NotFound(c, "Todo not found", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to set parent")
+			response.InternalServerError(c, "Failed to set parent", err)
+		}
+		return
+	}
+
+	response.OK(c, "Todo parent updated successfully", todo)
+}
+
+// AddDependency handles POST /api/v1/todos/:id/dependencies
+// @Summary Mark a todo as blocked by another
+// @Description Record that a todo is blocked by another todo. Dependencies that would create a cycle are rejected.
+// @Tags todos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "Blocked todo ID" format(uuid)
+// @Param dependency body models.AddDependencyRequest true "Blocking todo"
+// @Success 200 {object} response.SuccessResponse{data=models.TodoResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /todos/{id}/dependencies [post]
+func (h *TodoHandler) AddDependency(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid todo ID", err)
+		return
+	}
+
+	var req models.AddDependencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate.Struct(req); err != nil {
+		response.BadRequest(c, "Validation failed", err)
+		return
+	}
+
+	todo, err := h.service.AddDependency(c.Request.Context(), id, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to update todos", nil)
+		case errors.Is(err, services.ErrBlockerNotFound):
+			response.BadRequest(c, "Blocking todo not found", nil)
+		case errors.Is(err, services.ErrDependencyCycle):
+			response.Conflict(c, "Dependency would create a cycle", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to add dependency")
+			response.NotFound(c, "Todo not found", err)
+		}
+		return
+	}
+
+	response.OK(c, "Dependency added successfully", todo)
+}
+
+// RemoveDependency handles DELETE /api/v1/todos/:id/dependencies/:blocked_by_id
+// @Summary Remove a blocker from a todo
+// @Description Remove the dependency of a todo on a blocking todo
+// @Tags todos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "Blocked todo ID" format(uuid)
+// @Param blocked_by_id path string true "Blocking todo ID" format(uuid)
+// @Success 200 {object} response.SuccessResponse{data=models.TodoResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /todos/{id}/dependencies/{blocked_by_id} [delete]
+func (h *TodoHandler) RemoveDependency(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid todo ID", err)
+		return
+	}
+
+	blockedByID, err := uuid.Parse(c.Param("blocked_by_id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid blocking todo ID", err)
+		return
+	}
+
+	todo, err := h.service.RemoveDependency(c.Request.Context(), id, blockedByID)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to update todos", nil)
+		case errors.Is(err, services.ErrDependencyNotFound):
+			response.NotFound(c, "Dependency not found", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to remove dependency")
+			response.NotFound(c, "Todo not found", err)
+		}
+		return
+	}
+
+	response.OK(c, "Dependency removed successfully", todo)
+}
+
+// PreviewRecurrence handles GET /api/v1/todos/:id/recurrence
+// @Summary Preview the next occurrences of a recurring todo
+// @Description List the next due dates the todo's series will produce
+// @Tags todos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "Todo ID" format(uuid)
+// @Param count query int false "Number of occurrences (max 50)" default(5)
+// @Success 200 {object} response.SuccessResponse{data=models.RecurrencePreviewResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /todos/{id}/recurrence [get]
+func (h *TodoHandler) PreviewRecurrence(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid todo ID", err)
+		return
+	}
+
+	count, _ := strconv.Atoi(c.DefaultQuery("count", "5"))
+
+	preview, err := h.service.PreviewRecurrence(c.Request.Context(), id, count)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to read todos", nil)
+		case errors.Is(err, services.ErrNotRecurring):
+			response.BadRequest(c, "Todo is not recurring", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to preview recurrence")
+			response.NotFound(c, "Todo not found", err)
+		}
+		return
+	}
+
+	response.OK(c, "Recurrence preview retrieved successfully", preview)
+}
+
+// StopRecurrence handles DELETE /api/v1/todos/:id/recurrence
+// @Summary Stop a recurring series
+// @Description Remove the recurrence from every occurrence of the todo's series so no further occurrences are created
+// @Tags todos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "Todo ID" format(uuid)
+// @Success 200 {object} response.SuccessResponse{data=models.TodoResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /todos/{id}/recurrence [delete]
+func (h *TodoHandler) StopRecurrence(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid todo ID", err)
+		return
+	}
+
+	todo, err := h.service.StopRecurrence(c.Request.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to update todos", nil)
+		case errors.Is(err, services.ErrNotRecurring):
+			response.BadRequest(c, "Todo is not recurring", nil)
+		case errors.Is(err, services.ErrTodoNotFound):
+			response.NotFound(c, "Todo not found", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to stop recurrence")
+			response.InternalServerError(c, "Failed to stop recurrence", err)
+		}
+		return
+	}
+
+	response.OK(c, "Recurrence stopped successfully", todo)
+}
+
+// ListRevisions handles GET /api/v1/todos/:id/revisions
+// @Summary Get the revision history of a todo
+// @Description List the recorded changes of a todo with pagination, latest first. Each revision holds the todo's state after the change, the fields that changed and who changed them. The history of a deleted todo stays readable.
+// @Tags todos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "Todo ID" format(uuid)
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Items per page" default(20)
+// @Success 200 {object} response.SuccessResponse{data=models.TodoRevisionListResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /todos/{id}/revisions [get]
+func (h *TodoHandler) ListRevisions(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid todo ID", err)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+
+	revisions, err := h.service.ListRevisions(c.Request.Context(), id, page, perPage)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to read todos", nil)
+		case errors.Is(err, services.ErrTodoNotFound):
+			response.NotFound(c, "Todo not found", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to get revisions")
+			response.InternalServerError(c, "Failed to get revisions", err)
+		}
+		return
+	}
+
+	response.OK(c, "Revisions retrieved successfully", revisions)
+}
+
+// GetRevision handles GET /api/v1/todos/:id/revisions/:rev
+// @Summary Get a revision of a todo
+// @Description Get one recorded change of a todo by its revision number
+// @Tags todos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "Todo ID" format(uuid)
+// @Param rev path int true "Revision number"
+// @Success 200 {object} response.SuccessResponse{data=models.TodoRevisionResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /todos/{id}/revisions/{rev} [get]
+func (h *TodoHandler) GetRevision(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid todo ID", err)
+		return
+	}
+	number, err := strconv.Atoi(c.Param("rev"))
+	if err != nil || number < 1 {
+		response.BadRequest(c, "Invalid revision number", nil)
+		return
+	}
+
+	revision, err := h.service.GetRevision(c.Request.Context(), id, number)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to read todos", nil)
+		case errors.Is(err, services.ErrRevisionNotFound):
+			response.NotFound(c, "Revision not found", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to get revision")
+			response.InternalServerError(c, "Failed to get revision", err)
+		}
+		return
+	}
+
+	response.OK(c, "Revision retrieved successfully", revision)
+}
+
+// RestoreRevision handles POST /api/v1/todos/:id/revisions/:rev/restore
+// @Summary Restore a revision of a todo
+// @Description Put the title, description, priority, due date, tags and status of a todo back to how they were in a revision. The todo keeps its list and parent. The restore is recorded as a new revision; a todo with open blockers cannot be restored to a completed revision.
+// @Tags todos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "Todo ID" format(uuid)
+// @Param rev path int true "Revision number"
+// @Success 200 {object} response.SuccessResponse{data=models.TodoResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /todos/{id}/revisions/{rev}/restore [post]
+func (h *TodoHandler) RestoreRevision(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid todo ID", err)
+		return
+	}
+	number, err := strconv.Atoi(c.Param("rev"))
+	if err != nil || number < 1 {
+		response.BadRequest(c, "Invalid revision number", nil)
+		return
+	}
+
+	todo, err := h.service.RestoreRevision(c.Request.Context(), id, number)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to update todos", nil)
+		case errors.Is(err, services.ErrRevisionNotFound):
+			response.NotFound(c, "Revision not found", nil)
+		case errors.Is(err, services.ErrTodoBlocked):
+			response.Conflict(c, "Todo is blocked by open todos", nil)
+		case errors.Is(err, services.ErrTodoNotFound):
+			response.NotFound(c, "Todo not found", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to restore revision")
+			response.InternalServerError(c, "Failed to restore revision", err)
+		}
+		return
+	}
+
+	response.OK(c, "Revision restored successfully", todo)
+}
+
+// batchError points at a malformed operation of a batch
+type batchError struct {
+	Index int    `json:"index"`
+	Error string `json:"error"`
+}
+
+// decodeBatchOperation validates a batch operation and decodes its todo into the
+// request of the matching single-todo endpoint, which is validated the same way
+func decodeBatchOperation(op *models.BatchOperation) error {
+	if err := validator.Validate.Struct(op); err != nil {
+		return err
+	}
+
+	var target interface{}
+	switch op.Op {
+	case models.BatchOpCreate:
+		op.Create = &models.CreateTodoRequest{}
+		target = op.Create
+	case models.BatchOpUpdate:
+		op.Update = &models.UpdateTodoRequest{}
+		target = op.Update
+	default:
+		if len(op.Todo) > 0 {
+			return fmt.Errorf("%s operations take no todo", op.Op)
+		}
+		return nil
+	}
+
+	if len(op.Todo) == 0 || string(op.Todo) == "null" {
+		return fmt.Errorf("%s operations need a todo", op.Op)
+	}
+	if err := json.Unmarshal(op.Todo, target); err != nil {
+		return fmt.Errorf("invalid todo: %w", err)
+	}
+	return validator.Validate.Struct(target)
+}
+
+// batchStatus gives the HTTP status and error message the single-todo
+// endpoint would have answered a batch operation with
+func (h *TodoHandler) batchStatus(result *models.BatchResult) (int, string) {
+	err := result.Err
+	switch {
+	case err == nil && result.Op == models.BatchOpCreate:
+		return http.StatusCreated, ""
+	case err == nil:
+		return http.StatusOK, ""
+	case errors.Is(err, auth.ErrForbidden):
+		return http.StatusForbidden, err.Error()
+	case errors.Is(err, services.ErrTodoNotFound):
+		return http.StatusNotFound, err.Error()
+	case errors.Is(err, services.ErrTodoBlocked):
+		return http.StatusConflict, err.Error()
+	case errors.Is(err, services.ErrListNotFound),
+		errors.Is(err, services.ErrParentNotFound),
+		errors.Is(err, services.ErrInvalidRecurrence),
+		errors.Is(err, services.ErrNotRecurring),
+		errors.Is(err, services.ErrUnknownStatus):
+		return http.StatusBadRequest, err.Error()
+	default:
+		h.logger.Error().Err(err).Int("index", result.Index).Msg("Failed to apply batch operation")
+		return http.StatusInternalServerError, "internal error"
+	}
+}
+
+// errMixedPagination is returned for requests that ask for both a page number and a cursor
+var errMixedPagination = errors.New("page and per_page cannot be combined with cursor and limit")
+
+// getTodos reads a page of a todo listing, by cursor when the request has a
+// cursor or limit and by page number otherwise
+func (h *TodoHandler) getTodos(c *gin.Context, filter models.TodoFilter) (*models.TodoListResponse, error) {
+	after, byCursor := c.GetQuery("cursor")
+	_, byLimit := c.GetQuery("limit")
+	_, byPage := c.GetQuery("page")
+	_, byPerPage := c.GetQuery("per_page")
+
+	if byCursor || byLimit {
+		if byPage || byPerPage {
+			return nil, errMixedPagination
+		}
+		limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+		return h.service.GetPage(c.Request.Context(), filter, after, limit)
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+	return h.service.GetAll(c.Request.Context(), filter, page, perPage)
+}
+
+// applyTodoQuery narrows a listing filter by the query in the q parameter. Errors
+// are *query.Error values that point at the offending part of the query.
+func applyTodoQuery(c *gin.Context, filter *models.TodoFilter) error {
+	if q := c.Query("q"); q != "" {
+		return query.Apply(q, filter, time.Now())
+	}
+	return nil
+}
+
+// parseTodoFilter reads the listing filters shared by the todo listing endpoints
+func parseTodoFilter(c *gin.Context) (models.TodoFilter, error) {
+	var filter models.TodoFilter
+
+	if tags := c.Query("tags"); tags != "" {
+		filter.Tags = strings.Split(tags, ",")
+	}
+
+	switch mode := models.TagMode(c.DefaultQuery("tag_mode", string(models.TagModeAny))); mode {
+	case models.TagModeAny, models.TagModeAll:
+		filter.TagMode = mode
+	default:
+		return filter, fmt.Errorf("tag_mode must be %q or %q", models.TagModeAny, models.TagModeAll)
+	}
+
+	var err error
+	if filter.Completed, err = parseBoolQuery(c, "completed"); err != nil {
+		return filter, err
+	}
+	if filter.HasDueDate, err = parseBoolQuery(c, "has_due_date"); err != nil {
+		return filter, err
+	}
+	overdue, err := parseBoolQuery(c, "overdue")
+	if err != nil {
+		return filter, err
+	}
+	filter.Overdue = overdue != nil && *overdue
+	if filter.Overdue && filter.HasDueDate != nil && !*filter.HasDueDate {
+		return filter, fmt.Errorf("overdue todos always have a due date, so overdue=true cannot be combined with has_due_date=false")
+	}
+
+	for _, values := range c.QueryArray("priority") {
+		for _, value := range strings.Split(values, ",") {
+			switch priority := models.Priority(strings.TrimSpace(value)); priority {
+			case models.PriorityLow, models.PriorityMedium, models.PriorityHigh, models.PriorityUrgent:
+				filter.Priorities = append(filter.Priorities, priority)
+			default:
+				return filter, fmt.Errorf("priority must be one or more of %q, %q, %q and %q, got %q",
+					models.PriorityLow, models.PriorityMedium, models.PriorityHigh, models.PriorityUrgent, value)
+			}
+		}
+	}
+
+	if filter.DueBefore, err = parseTimeQuery(c, "due_before"); err != nil {
+		return filter, err
+	}
+	if filter.DueAfter, err = parseTimeQuery(c, "due_after"); err != nil {
+		return filter, err
+	}
+	if filter.DueBefore != nil && filter.DueAfter != nil && !filter.DueAfter.Before(*filter.DueBefore) {
+		return filter, fmt.Errorf("due_after must be before due_before")
+	}
+	if filter.CreatedAfter, err = parseTimeQuery(c, "created_after"); err != nil {
+		return filter, err
+	}
+
+	if sort := c.Query("sort"); sort != "" {
+		if filter.Sort, err = models.ParseTodoSort(sort); err != nil {
+			return filter, err
+		}
+	}
+
+	return filter, nil
+}
+
+// parseBoolQuery reads an optional true/false query parameter
+func parseBoolQuery(c *gin.Context, name string) (*bool, error) {
+	value, ok := c.GetQuery(name)
+	if !ok {
+		return nil, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be true or false", name)
+	}
+	return &b, nil
+}
+
+// parseTimeQuery reads an optional query parameter holding an RFC 3339 timestamp
+// or a date, which stands for midnight UTC at the start of that day. Timestamps
+// are converted to UTC, the zone due dates are stored in, so that they compare
+// correctly on databases that compare timestamps as text.
+func parseTimeQuery(c *gin.Context, name string) (*time.Time, error) {
+	value, ok := c.GetQuery(name)
+	if !ok {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		if t, err = time.Parse(time.DateOnly, value); err != nil {
+			return nil, fmt.Errorf("%s must be a date like 2024-01-31 or a timestamp like 2024-01-31T09:00:00Z", name)
+		}
+	}
+	t = t.UTC()
+	return &t, nil
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/1cbyc/go-todo-api/internal/auth"
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/1cbyc/go-todo-api/internal/services"
+	"github.com/1cbyc/go-todo-api/pkg/response"
+	"github.com/1cbyc/go-todo-api/pkg/validator"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// UserHandler handles HTTP requests for user administration
+type UserHandler struct {
+	service services.UserService
+	logger  zerolog.Logger
+}
+
+// NewUserHandler creates a new user handler
+func NewUserHandler(service services.UserService) *UserHandler {
+	return &UserHandler{
+		service: service,
+		logger:  zerolog.Logger{},
+	}
+}
+
+// GetAll handles GET /api/v1/admin/users
+// @Summary List users
+// @Description List all users with their roles (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Items per page" default(20)
+// @Success 200 {object} response.SuccessResponse{data=models.UserListResponse}
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /admin/users [get]
+func (h *UserHandler) GetAll(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+
+	users, err := h.service.GetAll(c.Request.Context(), page, perPage)
+	if err != nil {
+		if errors.Is(err, auth.ErrForbidden) {
+			response.Forbidden(c, "You do not have permission to manage users", nil)
+			return
+		}
+		h.logger.Error().Err(err).Msg("Failed to get users")
+		response.InternalServerError(c, "Failed to get users", err)
+		return
+	}
+
+	response.OK(c, "Users retrieved successfully", users)
+}
+
+// UpdateRole handles PATCH /api/v1/admin/users/:id/role
+// @Summary Change a user's role
+// @Description Assign the admin, member or viewer role to a user (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID" format(uuid)
+// @Param role body models.UpdateRoleRequest true "New role"
+// @Success 200 {object} response.SuccessResponse{data=models.UserResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /admin/users/{id}/role [patch]
+func (h *UserHandler) UpdateRole(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err)
+		return
+	}
+
+	var req models.UpdateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate.Struct(req); err != nil {
+		response.BadRequest(c, "Validation failed", err)
+		return
+	}
+
+	user, err := h.service.UpdateRole(c.Request.Context(), id, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to manage users", nil)
+		case errors.Is(err, services.ErrUserNotFound):
+			response.NotFound(c, "User not found", nil)
+		case errors.Is(err, services.ErrLastAdmin):
+			response.Conflict(c, "Cannot remove the last admin", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to update user role")
+			response.InternalServerError(c, "Failed to update user role", err)
+		}
+		return
+	}
+
+	response.OK(c, "User role updated successfully", user)
+}
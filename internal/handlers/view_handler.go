@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/1cbyc/go-todo-api/internal/auth"
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/1cbyc/go-todo-api/internal/services"
+	"github.com/1cbyc/go-todo-api/pkg/response"
+	"github.com/1cbyc/go-todo-api/pkg/validator"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+// ViewHandler handles HTTP requests for saved view operations
+type ViewHandler struct {
+	service services.ViewService
+	logger  zerolog.Logger
+}
+
+// NewViewHandler creates a new saved view handler
+func NewViewHandler(service services.ViewService) *ViewHandler {
+	return &ViewHandler{
+		service: service,
+		logger:  zerolog.Logger{},
+	}
+}
+
+// Create handles POST /api/v1/views
+// @Summary Save a view
+// @Description Save a named todo listing with filters and a sort order. Dates may be relative, like today or today+7d, and are resolved whenever the view is opened. Shared views are visible to everyone in the workspace but only their owner can change them.
+// @Tags views
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param view body models.CreateViewRequest true "View to save"
+// @Success 201 {object} response.SuccessResponse{data=models.ViewResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /views [post]
+func (h *ViewHandler) Create(c *gin.Context) {
+	var req models.CreateViewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate.Struct(req); err != nil {
+		response.BadRequest(c, "Validation failed", err)
+		return
+	}
+
+	view, err := h.service.Create(c.Request.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to save views", nil)
+		case errors.Is(err, services.ErrInvalidView):
+			response.BadRequest(c, "Invalid view definition", err.Error())
+		default:
+			h.logger.Error().Err(err).Msg("Failed to create view")
+			response.InternalServerError(c, "Failed to create view", err)
+		}
+		return
+	}
+
+	response.Created(c, "View created successfully", view)
+}
+
+// GetByID handles GET /api/v1/views/:id
+// @Summary Get a view
+// @Description Get a built-in view by key, or one of your views or a shared view by ID
+// @Tags views
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "View ID, or today, upcoming, overdue or no-due-date"
+// @Success 200 {object} response.SuccessResponse{data=models.ViewResponse}
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /views/{id} [get]
+func (h *ViewHandler) GetByID(c *gin.Context) {
+	id := c.Param("id")
+
+	view, err := h.service.GetByID(c.Request.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to read views", nil)
+		case errors.Is(err, services.ErrViewNotFound):
+			response.NotFound(c, "View not found", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", id).Msg("Failed to get view")
+			response.InternalServerError(c, "Failed to get view", err)
+		}
+		return
+	}
+
+	response.OK(c, "View retrieved successfully", view)
+}
+
+// GetAll handles GET /api/v1/views
+// @Summary Get all views
+// @Description Get the built-in views followed by your views and the views shared in the current workspace
+// @Tags views
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Success 200 {object} response.SuccessResponse{data=[]models.ViewResponse}
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /views [get]
+func (h *ViewHandler) GetAll(c *gin.Context) {
+	views, err := h.service.GetAll(c.Request.Context())
+	if err != nil {
+		if errors.Is(err, auth.ErrForbidden) {
+			response.Forbidden(c, "You do not have permission to read views", nil)
+			return
+		}
+		h.logger.Error().Err(err).Msg("Failed to get views")
+		response.InternalServerError(c, "Failed to get views", err)
+		return
+	}
+
+	response.OK(c, "Views retrieved successfully", views)
+}
+
+// Update handles PUT /api/v1/views/:id
+// @Summary Update a view
+// @Description Rename, share or unshare one of your views, or replace its definition
+// @Tags views
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "View ID" format(uuid)
+// @Param view body models.UpdateViewRequest true "View updates"
+// @Success 200 {object} response.SuccessResponse{data=models.ViewResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /views/{id} [put]
+func (h *ViewHandler) Update(c *gin.Context) {
+	id := c.Param("id")
+
+	var req models.UpdateViewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate.Struct(req); err != nil {
+		response.BadRequest(c, "Validation failed", err)
+		return
+	}
+
+	view, err := h.service.Update(c.Request.Context(), id, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to update views", nil)
+		case errors.Is(err, services.ErrSystemView):
+			response.Forbidden(c, "Built-in views cannot be changed", nil)
+		case errors.Is(err, services.ErrNotViewOwner):
+			response.Forbidden(c, "Only the owner can update a view", nil)
+		case errors.Is(err, services.ErrViewNotFound):
+			response.NotFound(c, "View not found", nil)
+		case errors.Is(err, services.ErrInvalidView):
+			response.BadRequest(c, "Invalid view definition", err.Error())
+		default:
+			h.logger.Error().Err(err).Str("id", id).Msg("Failed to update view")
+			response.InternalServerError(c, "Failed to update view", err)
+		}
+		return
+	}
+
+	response.OK(c, "View updated successfully", view)
+}
+
+// Delete handles DELETE /api/v1/views/:id
+// @Summary Delete a view
+// @Description Delete one of your views; built-in views cannot be deleted
+// @Tags views
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param X-Workspace-ID header string false "Workspace ID (defaults to your first workspace)" format(uuid)
+// @Param id path string true "View ID" format(uuid)
+// @Success 200 {object} response.SuccessResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /views/{id} [delete]
+func (h *ViewHandler) Delete(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := h.service.Delete(c.Request.Context(), id); err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			response.Forbidden(c, "You do not have permission to delete views", nil)
+		case errors.Is(err, services.ErrSystemView):
+			response.Forbidden(c, "Built-in views cannot be deleted", nil)
+		case errors.Is(err, services.ErrNotViewOwner):
+			response.Forbidden(c, "Only the owner can delete a view", nil)
+		case errors.Is(err, services.ErrViewNotFound):
+			response.NotFound(c, "View not found", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", id).Msg("Failed to delete view")
+			response.InternalServerError(c, "Failed to delete view", err)
+		}
+		return
+	}
+
+	response.OK(c, "View deleted successfully", nil)
+}
@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/1cbyc/go-todo-api/internal/services"
+	"github.com/1cbyc/go-todo-api/pkg/response"
+	"github.com/1cbyc/go-todo-api/pkg/validator"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// WorkspaceHandler handles HTTP requests for workspaces and their members
+type WorkspaceHandler struct {
+	service services.WorkspaceService
+	logger  zerolog.Logger
+}
+
+// NewWorkspaceHandler creates a new workspace handler
+func NewWorkspaceHandler(service services.WorkspaceService) *WorkspaceHandler {
+	return &WorkspaceHandler{
+		service: service,
+		logger:  zerolog.Logger{},
+	}
+}
+
+// List handles GET /api/v1/workspaces
+// @Summary List workspaces
+// @Description List the workspaces the authenticated user belongs to
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Success 200 {object} response.SuccessResponse{data=[]models.WorkspaceResponse}
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /workspaces [get]
+func (h *WorkspaceHandler) List(c *gin.Context) {
+	workspaces, err := h.service.List(c.Request.Context())
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to list workspaces")
+		response.InternalServerError(c, "Failed to list workspaces", err)
+		return
+	}
+
+	response.OK(c, "Workspaces retrieved successfully", workspaces)
+}
+
+// Create handles POST /api/v1/workspaces
+// @Summary Create a workspace
+// @Description Create a workspace owned by the authenticated user
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param workspace body models.CreateWorkspaceRequest true "Workspace to create"
+// @Success 201 {object} response.SuccessResponse{data=models.WorkspaceResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /workspaces [post]
+func (h *WorkspaceHandler) Create(c *gin.Context) {
+	var req models.CreateWorkspaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate.Struct(req); err != nil {
+		response.BadRequest(c, "Validation failed", err)
+		return
+	}
+
+	workspace, err := h.service.Create(c.Request.Context(), &req)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to create workspace")
+		response.InternalServerError(c, "Failed to create workspace", err)
+		return
+	}
+
+	response.Created(c, "Workspace created successfully", workspace)
+}
+
+// ListMembers handles GET /api/v1/workspaces/:id/members
+// @Summary List workspace members
+// @Description List the members of a workspace
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Workspace ID" format(uuid)
+// @Success 200 {object} response.SuccessResponse{data=[]models.WorkspaceMemberResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /workspaces/{id}/members [get]
+func (h *WorkspaceHandler) ListMembers(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid workspace ID", err)
+		return
+	}
+
+	members, err := h.service.ListMembers(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, services.ErrWorkspaceNotFound) {
+			response.NotFound(c, "Workspace not found", nil)
+			return
+		}
+		h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to list members")
+		response.InternalServerError(c, "Failed to list members", err)
+		return
+	}
+
+	response.OK(c, "Members retrieved successfully", members)
+}
+
+// AddMember handles POST /api/v1/workspaces/:id/members
+// @Summary Add a workspace member
+// @Description Add a registered user to a workspace (owners only)
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Workspace ID" format(uuid)
+// @Param member body models.AddMemberRequest true "Member to add"
+// @Success 201 {object} response.SuccessResponse{data=models.WorkspaceMemberResponse}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /workspaces/{id}/members [post]
+func (h *WorkspaceHandler) AddMember(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid workspace ID", err)
+		return
+	}
+
+	var req models.AddMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate.Struct(req); err != nil {
+		response.BadRequest(c, "Validation failed", err)
+		return
+	}
+
+	member, err := h.service.AddMember(c.Request.Context(), id, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrWorkspaceNotFound):
+			response.NotFound(c, "Workspace not found", nil)
+		case errors.Is(err, services.ErrNotWorkspaceOwner):
+			response.Forbidden(c, "Only workspace owners can add members", nil)
+		case errors.Is(err, services.ErrUserNotFound):
+			response.NotFound(c, "User not found", nil)
+		case errors.Is(err, services.ErrAlreadyMember):
+			response.Conflict(c, "User is already a member", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to add member")
+			response.InternalServerError(c, "Failed to add member", err)
+		}
+		return
+	}
+
+	response.Created(c, "Member added successfully", member)
+}
+
+// RemoveMember handles DELETE /api/v1/workspaces/:id/members/:user_id
+// @Summary Remove a workspace member
+// @Description Remove a member from a workspace. Owners can remove anyone; members can remove themselves.
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Workspace ID" format(uuid)
+// @Param user_id path string true "User ID" format(uuid)
+// @Success 200 {object} response.SuccessResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /workspaces/{id}/members/{user_id} [delete]
+func (h *WorkspaceHandler) RemoveMember(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid workspace ID", err)
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err)
+		return
+	}
+
+	if err := h.service.RemoveMember(c.Request.Context(), id, userID); err != nil {
+		switch {
+		case errors.Is(err, services.ErrWorkspaceNotFound):
+			response.NotFound(c, "Workspace not found", nil)
+		case errors.Is(err, services.ErrNotWorkspaceOwner):
+			response.Forbidden(c, "Only workspace owners can remove other members", nil)
+		case errors.Is(err, services.ErrMemberNotFound):
+			response.NotFound(c, "Member not found", nil)
+		case errors.Is(err, services.ErrLastOwner):
+			response.Conflict(c, "Cannot remove the last owner", nil)
+		default:
+			h.logger.Error().Err(err).Str("id", idStr).Msg("Failed to remove member")
+			response.InternalServerError(c, "Failed to remove member", err)
+		}
+		return
+	}
+
+	response.OK(c, "Member removed successfully", nil)
+}
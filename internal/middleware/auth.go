@@ -0,0 +1,157 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/1cbyc/go-todo-api/internal/auth"
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/1cbyc/go-todo-api/pkg/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// APIKeyVerifier resolves an API key to the principal it acts for
+type APIKeyVerifier interface {
+	VerifyAPIKey(ctx context.Context, key string) (*auth.Principal, error)
+}
+
+// RoleResolver looks up the current role of a user
+type RoleResolver interface {
+	ResolveRole(ctx context.Context, userID uuid.UUID) (models.Role, error)
+}
+
+// Auth middleware authenticates the request and stores the principal in the request context.
+// It accepts "Authorization: Bearer <access token>" and "Authorization: ApiKey <key>".
+// Access tokens belonging to a revoked session are rejected. The user's role is looked up
+// on every request so that role changes take effect immediately.
+func Auth(tokens *auth.TokenManager, revocations auth.RevocationStore, apiKeys APIKeyVerifier, roles RoleResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		scheme, credential, found := strings.Cut(header, " ")
+		credential = strings.TrimSpace(credential)
+		if !found || credential == "" {
+			response.Unauthorized(c, "Missing or malformed authorization header", nil)
+			c.Abort()
+			return
+		}
+
+		var principal *auth.Principal
+		switch {
+		case strings.EqualFold(scheme, "Bearer"):
+			principal = verifyBearer(c, tokens, revocations, credential)
+		case strings.EqualFold(scheme, "ApiKey"):
+			principal = verifyAPIKey(c, apiKeys, credential)
+		default:
+			response.Unauthorized(c, "Unsupported authorization scheme", nil)
+		}
+		if principal == nil {
+			c.Abort()
+			return
+		}
+
+		role, err := roles.ResolveRole(c.Request.Context(), principal.UserID)
+		if err != nil {
+			if errors.Is(err, auth.ErrUnknownUser) {
+				response.Unauthorized(c, "Account no longer exists", nil)
+			} else {
+				response.InternalServerError(c, "Failed to resolve role", err)
+			}
+			c.Abort()
+			return
+		}
+		principal.Role = role
+
+		c.Set("user_id", principal.UserID)
+		c.Set("role", role)
+		c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
+		c.Next()
+	}
+}
+
+// verifyBearer validates an access token and writes an error response on failure
+func verifyBearer(c *gin.Context, tokens *auth.TokenManager, revocations auth.RevocationStore, token string) *auth.Principal {
+	claims, err := tokens.Parse(token)
+	if err != nil {
+		response.Unauthorized(c, "Invalid or expired token", nil)
+		return nil
+	}
+
+	userID, err := claims.UserID()
+	if err != nil {
+		response.Unauthorized(c, "Invalid or expired token", nil)
+		return nil
+	}
+
+	sessionID, err := claims.SessionUUID()
+	if err != nil {
+		response.Unauthorized(c, "Invalid or expired token", nil)
+		return nil
+	}
+
+	revoked, err := revocations.IsRevoked(c.Request.Context(), sessionID)
+	if err != nil {
+		response.InternalServerError(c, "Failed to verify session", err)
+		return nil
+	}
+	if revoked {
+		response.Unauthorized(c, "Session has been revoked", nil)
+		return nil
+	}
+
+	c.Set("session_id", sessionID)
+	return &auth.Principal{UserID: userID, SessionID: sessionID}
+}
+
+// verifyAPIKey validates an API key and writes an error response on failure
+func verifyAPIKey(c *gin.Context, apiKeys APIKeyVerifier, key string) *auth.Principal {
+	principal, err := apiKeys.VerifyAPIKey(c.Request.Context(), key)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidAPIKey) {
+			response.Unauthorized(c, "Invalid or expired API key", nil)
+			return nil
+		}
+		response.InternalServerError(c, "Failed to verify API key", err)
+		return nil
+	}
+
+	c.Set("api_key_id", principal.APIKeyID)
+	return principal
+}
+
+// RequirePermission middleware rejects principals whose role or API key scopes lack the permission
+func RequirePermission(permission auth.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFromContext(c.Request.Context())
+		if !ok {
+			response.Unauthorized(c, "Authentication required", nil)
+			c.Abort()
+			return
+		}
+		if !auth.RoleAllows(principal.Role, permission) {
+			response.Forbidden(c, "Your role does not grant the required permission: "+string(permission), nil)
+			c.Abort()
+			return
+		}
+		if !principal.HasScope(permission) {
+			response.Forbidden(c, "API key is missing the required scope: "+string(permission), nil)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireSession middleware rejects principals that did not log in with a password,
+// so that API keys cannot manage sessions or other API keys
+func RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := auth.SessionIDFromContext(c.Request.Context()); !ok {
+			response.Forbidden(c, "This endpoint requires a user session", nil)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
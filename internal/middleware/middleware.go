@@ -44,7 +44,7 @@ func CORS() gin.HandlerFunc {
 	config := cors.DefaultConfig()
 	config.AllowAllOrigins = true
 	config.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
-	config.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization", "X-Request-ID"}
+	config.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization", "X-Request-ID", "X-Workspace-ID"}
 	config.ExposeHeaders = []string{"Content-Length", "X-Request-ID"}
 	config.AllowCredentials = true
 	config.MaxAge = 12 * time.Hour
@@ -57,9 +57,10 @@ func RequestID() gin.HandlerFunc {
 	return requestid.New()
 }
 
-// Timeout middleware
-func Timeout(timeout time.Duration) gin.HandlerFunc {
-	return gintimeout.New(
+// Timeout middleware. The timeout handler buffers the whole response, so routes
+// that stream their response are listed in skip and run without it.
+func Timeout(timeout time.Duration, skip ...string) gin.HandlerFunc {
+	handler := gintimeout.New(
 		gintimeout.WithTimeout(timeout),
 		gintimeout.WithHandler(func(c *gin.Context) {
 			c.Next()
@@ -71,4 +72,14 @@ func Timeout(timeout time.Duration) gin.HandlerFunc {
 			})
 		}),
 	)
+
+	return func(c *gin.Context) {
+		for _, path := range skip {
+			if c.FullPath() == path {
+				c.Next()
+				return
+			}
+		}
+		handler(c)
+	}
 }
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+
+	"github.com/1cbyc/go-todo-api/internal/auth"
+	"github.com/1cbyc/go-todo-api/pkg/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// WorkspaceHeader selects the workspace a request operates on
+const WorkspaceHeader = "X-Workspace-ID"
+
+// WorkspaceResolver picks the workspace a request is bound to
+type WorkspaceResolver interface {
+	ResolveWorkspace(ctx context.Context, userID uuid.UUID, requested uuid.UUID) (uuid.UUID, error)
+}
+
+// Workspace middleware binds the request to the workspace named in the X-Workspace-ID header,
+// or to the user's default workspace when the header is absent. It must run after Auth.
+func Workspace(resolver WorkspaceResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFromContext(c.Request.Context())
+		if !ok {
+			response.Unauthorized(c, "Authentication required", nil)
+			c.Abort()
+			return
+		}
+
+		requested := uuid.Nil
+		if header := c.GetHeader(WorkspaceHeader); header != "" {
+			id, err := uuid.Parse(header)
+			if err != nil {
+				response.BadRequest(c, "Invalid workspace ID", err)
+				c.Abort()
+				return
+			}
+			requested = id
+		}
+
+		workspaceID, err := resolver.ResolveWorkspace(c.Request.Context(), principal.UserID, requested)
+		if err != nil {
+			if errors.Is(err, auth.ErrWorkspaceAccess) {
+				response.NotFound(c, "Workspace not found", nil)
+			} else {
+				response.InternalServerError(c, "Failed to resolve workspace", err)
+			}
+			c.Abort()
+			return
+		}
+
+		bound := *principal
+		bound.WorkspaceID = workspaceID
+
+		c.Set("workspace_id", workspaceID)
+		c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), &bound))
+		c.Next()
+	}
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Attachment represents a file uploaded to a todo. The file content lives in
+// the blob store under StorageKey.
+type Attachment struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+	WorkspaceID uuid.UUID `json:"-" gorm:"type:uuid;not null;index"`
+	TodoID      uuid.UUID `json:"todo_id" gorm:"type:uuid;not null;index"`
+	UserID      uuid.UUID `json:"uploaded_by" gorm:"type:uuid;not null"`
+	Filename    string    `json:"filename" gorm:"not null;size:255"`
+	ContentType string    `json:"content_type" gorm:"not null;size:100"`
+	Size        int64     `json:"size" gorm:"not null"`
+	Checksum    string    `json:"checksum" gorm:"size:64"` // hex SHA-256 of the content
+	StorageKey  string    `json:"-" gorm:"not null;size:255"`
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName specifies the table name for Attachment
+func (Attachment) TableName() string {
+	return "attachments"
+}
+
+// BeforeCreate is called before creating a new attachment
+func (a *Attachment) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
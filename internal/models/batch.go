@@ -0,0 +1,70 @@
+package models
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+// BatchMode controls what happens to a batch when one of its operations fails
+type BatchMode string
+
+const (
+	// BatchModeAtomic applies every operation of a batch or, if one fails, none of them
+	BatchModeAtomic BatchMode = "atomic"
+	// BatchModeBestEffort applies each operation on its own and reports how each one went
+	BatchModeBestEffort BatchMode = "best_effort"
+)
+
+// BatchOp names what a batch operation does
+type BatchOp string
+
+const (
+	BatchOpCreate BatchOp = "create"
+	BatchOpUpdate BatchOp = "update"
+	BatchOpDelete BatchOp = "delete"
+	BatchOpToggle BatchOp = "toggle"
+)
+
+// BatchRequest represents the request body for applying several todo operations at once
+type BatchRequest struct {
+	Mode       BatchMode        `json:"mode" validate:"omitempty,oneof=atomic best_effort"`
+	Operations []BatchOperation `json:"operations" validate:"required,min=1"`
+}
+
+// BatchOperation is one operation of a batch. Create and update take the todo
+// fields of the single-todo endpoints in todo; update, delete and toggle name
+// the todo by id.
+type BatchOperation struct {
+	Op       BatchOp           `json:"op" validate:"required,oneof=create update delete toggle"`
+	ID       *uuid.UUID        `json:"id,omitempty" validate:"required_unless=Op create"`
+	Todo     json.RawMessage   `json:"todo,omitempty" swaggertype:"object"`
+	Subtasks SubtaskDeleteMode `json:"subtasks,omitempty" validate:"omitempty,oneof=orphan cascade"`
+
+	// Create and Update hold the decoded todo of create and update operations
+	Create *CreateTodoRequest `json:"-"`
+	Update *UpdateTodoRequest `json:"-"`
+}
+
+// BatchResult is the outcome of one operation of a batch. Status is the HTTP
+// status the operation would have had on its own.
+type BatchResult struct {
+	Index  int           `json:"index"`
+	Op     BatchOp       `json:"op"`
+	ID     *uuid.UUID    `json:"id,omitempty"`
+	Status int           `json:"status"`
+	Todo   *TodoResponse `json:"todo,omitempty"`
+	Error  string        `json:"error,omitempty"`
+
+	// Err is why the operation failed, translated into Status and Error for the response
+	Err error `json:"-"`
+}
+
+// BatchResponse represents the response body for a batch. A failed atomic
+// batch only reports the operation that failed, since nothing was applied.
+type BatchResponse struct {
+	Mode      BatchMode     `json:"mode"`
+	Succeeded int           `json:"succeeded"`
+	Failed    int           `json:"failed"`
+	Results   []BatchResult `json:"results"`
+}
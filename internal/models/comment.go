@@ -0,0 +1,88 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Comment represents a message in the discussion thread of a todo
+type Comment struct {
+	ID          uuid.UUID      `json:"id" gorm:"type:uuid;primary_key"`
+	WorkspaceID uuid.UUID      `json:"-" gorm:"type:uuid;not null;index"`
+	TodoID      uuid.UUID      `json:"todo_id" gorm:"type:uuid;not null;index"`
+	UserID      uuid.UUID      `json:"-" gorm:"type:uuid;not null;index"`
+	Body        string         `json:"body" gorm:"not null;size:10000"`
+	EditedAt    *time.Time     `json:"edited_at,omitempty"`
+	CreatedAt   time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+	User        *User          `json:"-" gorm:"foreignKey:UserID"`
+}
+
+// TableName specifies the table name for Comment
+func (Comment) TableName() string {
+	return "comments"
+}
+
+// BeforeCreate is called before creating a new comment
+func (c *Comment) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
+
+// CreateCommentRequest represents the request body for adding a comment
+type CreateCommentRequest struct {
+	Body string `json:"body" validate:"required,max=10000"`
+}
+
+// UpdateCommentRequest represents the request body for editing a comment
+type UpdateCommentRequest struct {
+	Body string `json:"body" validate:"required,max=10000"`
+}
+
+// CommentAuthor identifies the user who wrote a comment
+type CommentAuthor struct {
+	ID    uuid.UUID `json:"id"`
+	Name  string    `json:"name"`
+	Email string    `json:"email"`
+}
+
+// CommentResponse represents the response body for comment operations
+type CommentResponse struct {
+	ID        uuid.UUID     `json:"id"`
+	TodoID    uuid.UUID     `json:"todo_id"`
+	Author    CommentAuthor `json:"author"`
+	Body      string        `json:"body"`
+	EditedAt  *time.Time    `json:"edited_at,omitempty"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// ToResponse converts a Comment to CommentResponse. The author is only filled
+// in when the user was loaded with the comment.
+func (c *Comment) ToResponse() CommentResponse {
+	author := CommentAuthor{ID: c.UserID}
+	if c.User != nil {
+		author.Name = c.User.Name
+		author.Email = c.User.Email
+	}
+	return CommentResponse{
+		ID:        c.ID,
+		TodoID:    c.TodoID,
+		Author:    author,
+		Body:      c.Body,
+		EditedAt:  c.EditedAt,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+}
+
+// CommentListResponse represents the response for listing comments
+type CommentListResponse struct {
+	Data []CommentResponse `json:"data"`
+	Meta Meta              `json:"meta"`
+}
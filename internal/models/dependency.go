@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TodoDependency records that a todo is blocked by another todo of the same workspace
+type TodoDependency struct {
+	TodoID      uuid.UUID `json:"todo_id" gorm:"type:uuid;primary_key"`
+	BlockedByID uuid.UUID `json:"blocked_by_id" gorm:"type:uuid;primary_key;index"`
+	WorkspaceID uuid.UUID `json:"workspace_id" gorm:"type:uuid;not null;index"`
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName specifies the table name for TodoDependency
+func (TodoDependency) TableName() string {
+	return "todo_dependencies"
+}
+
+// AddDependencyRequest represents the request body for marking a todo as blocked by another
+type AddDependencyRequest struct {
+	BlockedByID uuid.UUID `json:"blocked_by_id" validate:"required"`
+}
+
+// DependencyRef is a short description of a todo on the other end of a dependency
+type DependencyRef struct {
+	ID        uuid.UUID `json:"id"`
+	Title     string    `json:"title"`
+	Completed bool      `json:"completed"`
+}
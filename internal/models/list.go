@@ -0,0 +1,92 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// List represents a project that groups todos within a workspace
+type List struct {
+	ID          uuid.UUID      `json:"id" gorm:"type:uuid;primary_key"`
+	WorkspaceID uuid.UUID      `json:"workspace_id" gorm:"type:uuid;not null;index"`
+	UserID      uuid.UUID      `json:"-" gorm:"type:uuid;index"`
+	Name        string         `json:"name" gorm:"not null;size:255"`
+	Description string         `json:"description" gorm:"size:1000"`
+	Workflow    *Workflow      `json:"-" gorm:"type:text;serializer:json"` // nil uses DefaultWorkflow
+	CreatedAt   time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// TableName specifies the table name for List
+func (List) TableName() string {
+	return "lists"
+}
+
+// BeforeCreate is called before creating a new list
+func (l *List) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return nil
+}
+
+// CreateListRequest represents the request body for creating a list
+type CreateListRequest struct {
+	Name        string `json:"name" validate:"required,min=1,max=255"`
+	Description string `json:"description" validate:"max=1000"`
+}
+
+// UpdateListRequest represents the request body for updating a list
+type UpdateListRequest struct {
+	Name        *string `json:"name,omitempty" validate:"omitempty,min=1,max=255"`
+	Description *string `json:"description,omitempty" validate:"omitempty,max=1000"`
+}
+
+// EffectiveWorkflow returns the list's workflow, or the default one when it has none
+func (l *List) EffectiveWorkflow() *Workflow {
+	if l.Workflow == nil {
+		return DefaultWorkflow()
+	}
+	return l.Workflow
+}
+
+// ListCounts summarizes the todos in a list
+type ListCounts struct {
+	Total     int64 `json:"total"`
+	Open      int64 `json:"open"`
+	Completed int64 `json:"completed"`
+	Overdue   int64 `json:"overdue"`
+}
+
+// ListResponse represents the response body for list operations
+type ListResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	WorkspaceID uuid.UUID  `json:"workspace_id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Counts      ListCounts `json:"counts"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// ToResponse converts a List to ListResponse
+func (l *List) ToResponse(counts ListCounts) ListResponse {
+	return ListResponse{
+		ID:          l.ID,
+		WorkspaceID: l.WorkspaceID,
+		Name:        l.Name,
+		Description: l.Description,
+		Counts:      counts,
+		CreatedAt:   l.CreatedAt,
+		UpdatedAt:   l.UpdatedAt,
+	}
+}
+
+// MoveTodoRequest represents the request body for moving a todo to another list.
+// A null list_id removes the todo from its list.
+type MoveTodoRequest struct {
+	ListID *uuid.UUID `json:"list_id"`
+}
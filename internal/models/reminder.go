@@ -0,0 +1,89 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ReminderChannel represents how a reminder is delivered
+type ReminderChannel string
+
+const (
+	// ReminderChannelEmail delivers the reminder by email to the user who created it
+	ReminderChannelEmail ReminderChannel = "email"
+	// ReminderChannelWebhook delivers the reminder to the configured webhook
+	ReminderChannelWebhook ReminderChannel = "webhook"
+)
+
+// ReminderStatus represents the delivery state of a reminder
+type ReminderStatus string
+
+const (
+	// ReminderPending reminders are waiting to be delivered
+	ReminderPending ReminderStatus = "pending"
+	// ReminderSending reminders have been claimed by the scheduler
+	ReminderSending ReminderStatus = "sending"
+	// ReminderSent reminders were delivered
+	ReminderSent ReminderStatus = "sent"
+	// ReminderFailed reminders ran out of delivery attempts
+	ReminderFailed ReminderStatus = "failed"
+	// ReminderCancelled reminders were dropped because their todo was completed or deleted
+	ReminderCancelled ReminderStatus = "cancelled"
+)
+
+// Reminder represents a notification about a todo, either at a fixed time or
+// a number of minutes before the todo is due. Offset reminders have no
+// RemindAt while their todo has no due date.
+type Reminder struct {
+	ID            uuid.UUID       `json:"id" gorm:"type:uuid;primary_key"`
+	WorkspaceID   uuid.UUID       `json:"-" gorm:"type:uuid;not null;index"`
+	TodoID        uuid.UUID       `json:"todo_id" gorm:"type:uuid;not null;index"`
+	UserID        uuid.UUID       `json:"-" gorm:"type:uuid;not null;index"`
+	Channel       ReminderChannel `json:"channel" gorm:"size:20;not null"`
+	OffsetMinutes *int            `json:"offset_minutes,omitempty"`
+	RemindAt      *time.Time      `json:"remind_at" gorm:"index:idx_reminders_due,priority:2"`
+	Status        ReminderStatus  `json:"status" gorm:"size:20;not null;default:pending;index:idx_reminders_due,priority:1"`
+	Attempts      int             `json:"attempts" gorm:"not null;default:0"`
+	LastError     string          `json:"last_error,omitempty" gorm:"size:1000"`
+	SentAt        *time.Time      `json:"sent_at,omitempty"`
+	CreatedAt     time.Time       `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time       `json:"updated_at" gorm:"autoUpdateTime"`
+	Todo          *Todo           `json:"-" gorm:"foreignKey:TodoID"`
+	User          *User           `json:"-" gorm:"foreignKey:UserID"`
+}
+
+// TableName specifies the table name for Reminder
+func (Reminder) TableName() string {
+	return "reminders"
+}
+
+// BeforeCreate is called before creating a new reminder
+func (r *Reminder) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+// Schedule sets RemindAt from the todo's due date for offset reminders
+func (r *Reminder) Schedule(dueDate *time.Time) {
+	if r.OffsetMinutes == nil {
+		return
+	}
+	if dueDate == nil {
+		r.RemindAt = nil
+		return
+	}
+	remindAt := dueDate.Add(-time.Duration(*r.OffsetMinutes) * time.Minute).UTC()
+	r.RemindAt = &remindAt
+}
+
+// CreateReminderRequest represents the request body for creating a reminder.
+// Exactly one of remind_at and offset_minutes must be set.
+type CreateReminderRequest struct {
+	RemindAt      *time.Time      `json:"remind_at"`
+	OffsetMinutes *int            `json:"offset_minutes" validate:"omitempty,min=0,max=525600"`
+	Channel       ReminderChannel `json:"channel" validate:"omitempty,oneof=email webhook"`
+}
@@ -0,0 +1,176 @@
+package models
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RevisionAction names the kind of change a revision records
+type RevisionAction string
+
+const (
+	RevisionCreated   RevisionAction = "created"
+	RevisionUpdated   RevisionAction = "updated"
+	RevisionToggled   RevisionAction = "toggled"
+	RevisionDeleted   RevisionAction = "deleted"
+	RevisionRestored  RevisionAction = "restored"
+	RevisionUndeleted RevisionAction = "undeleted"
+)
+
+// TodoRevision records the state of a todo after a change, what changed and who changed it.
+// Revisions are numbered from 1 per todo.
+type TodoRevision struct {
+	ID          uuid.UUID      `json:"id" gorm:"type:uuid;primary_key"`
+	WorkspaceID uuid.UUID      `json:"-" gorm:"type:uuid;not null;index"`
+	TodoID      uuid.UUID      `json:"todo_id" gorm:"type:uuid;not null;uniqueIndex:idx_todo_revisions_number"`
+	Number      int            `json:"revision" gorm:"not null;uniqueIndex:idx_todo_revisions_number"`
+	Action      RevisionAction `json:"action" gorm:"size:20;not null"`
+	ActorID     uuid.UUID      `json:"-" gorm:"type:uuid;not null;index"`
+	Snapshot    TodoSnapshot   `json:"snapshot" gorm:"type:text;serializer:json"`
+	Changes     []FieldChange  `json:"changes" gorm:"type:text;serializer:json"`
+	CreatedAt   time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	Actor       *User          `json:"-" gorm:"foreignKey:ActorID"`
+}
+
+// TableName specifies the table name for TodoRevision
+func (TodoRevision) TableName() string {
+	return "todo_revisions"
+}
+
+// BeforeCreate is called before creating a new revision
+func (r *TodoRevision) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+// TodoSnapshot is the user-editable state of a todo at one point in time
+type TodoSnapshot struct {
+	Title          string         `json:"title"`
+	Description    string         `json:"description"`
+	Status         string         `json:"status"`
+	Completed      bool           `json:"completed"`
+	Priority       Priority       `json:"priority"`
+	DueDate        *time.Time     `json:"due_date"`
+	ListID         *uuid.UUID     `json:"list_id"`
+	ParentID       *uuid.UUID     `json:"parent_id"`
+	Tags           []string       `json:"tags"`
+	Recurrence     string         `json:"recurrence"`
+	RecurrenceMode RecurrenceMode `json:"recurrence_mode"`
+}
+
+// Snapshot captures the current state of the todo; its tags must be loaded
+func (t *Todo) Snapshot() TodoSnapshot {
+	return TodoSnapshot{
+		Title:          t.Title,
+		Description:    t.Description,
+		Status:         t.Status,
+		Completed:      t.Completed,
+		Priority:       t.Priority,
+		DueDate:        t.DueDate,
+		ListID:         t.ListID,
+		ParentID:       t.ParentID,
+		Tags:           t.TagNames(),
+		Recurrence:     t.Recurrence,
+		RecurrenceMode: t.RecurrenceMode,
+	}
+}
+
+// FieldChange is the old and new value of one field of a todo
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// Diff lists the fields that differ between an earlier snapshot and s, in field order
+func (s TodoSnapshot) Diff(before TodoSnapshot) []FieldChange {
+	changes := []FieldChange{}
+	add := func(field string, equal bool, from, to interface{}) {
+		if !equal {
+			changes = append(changes, FieldChange{Field: field, From: from, To: to})
+		}
+	}
+
+	add("title", before.Title == s.Title, before.Title, s.Title)
+	add("description", before.Description == s.Description, before.Description, s.Description)
+	add("status", before.Status == s.Status, before.Status, s.Status)
+	add("completed", before.Completed == s.Completed, before.Completed, s.Completed)
+	add("priority", before.Priority == s.Priority, before.Priority, s.Priority)
+	add("due_date", equalTimes(before.DueDate, s.DueDate), before.DueDate, s.DueDate)
+	add("list_id", equalIDs(before.ListID, s.ListID), before.ListID, s.ListID)
+	add("parent_id", equalIDs(before.ParentID, s.ParentID), before.ParentID, s.ParentID)
+	add("tags", slices.Equal(before.Tags, s.Tags), before.Tags, s.Tags)
+	add("recurrence", before.Recurrence == s.Recurrence, before.Recurrence, s.Recurrence)
+	add("recurrence_mode", before.RecurrenceMode == s.RecurrenceMode, before.RecurrenceMode, s.RecurrenceMode)
+	return changes
+}
+
+// equalTimes reports whether two optional timestamps are both unset or the same instant
+func equalTimes(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// equalIDs reports whether two optional IDs are both unset or the same
+func equalIDs(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// RevisionActor identifies the user who made a change
+type RevisionActor struct {
+	ID    uuid.UUID `json:"id"`
+	Name  string    `json:"name"`
+	Email string    `json:"email"`
+}
+
+// TodoRevisionResponse represents the response body for revision operations
+type TodoRevisionResponse struct {
+	ID        uuid.UUID      `json:"id"`
+	TodoID    uuid.UUID      `json:"todo_id"`
+	Revision  int            `json:"revision"`
+	Action    RevisionAction `json:"action"`
+	Actor     RevisionActor  `json:"actor"`
+	Snapshot  TodoSnapshot   `json:"snapshot"`
+	Changes   []FieldChange  `json:"changes"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// ToResponse converts a TodoRevision to TodoRevisionResponse. The actor is only
+// filled in when the user was loaded with the revision.
+func (r *TodoRevision) ToResponse() TodoRevisionResponse {
+	actor := RevisionActor{ID: r.ActorID}
+	if r.Actor != nil {
+		actor.Name = r.Actor.Name
+		actor.Email = r.Actor.Email
+	}
+	changes := r.Changes
+	if changes == nil {
+		changes = []FieldChange{}
+	}
+	return TodoRevisionResponse{
+		ID:        r.ID,
+		TodoID:    r.TodoID,
+		Revision:  r.Number,
+		Action:    r.Action,
+		Actor:     actor,
+		Snapshot:  r.Snapshot,
+		Changes:   changes,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+// TodoRevisionListResponse represents the response for listing revisions
+type TodoRevisionListResponse struct {
+	Data []TodoRevisionResponse `json:"data"`
+	Meta Meta                   `json:"meta"`
+}
@@ -0,0 +1,15 @@
+package models
+
+// Role represents the access level of a user
+type Role string
+
+const (
+	RoleAdmin  Role = "admin"
+	RoleMember Role = "member"
+	RoleViewer Role = "viewer"
+)
+
+// UpdateRoleRequest represents the request body for changing a user's role
+type UpdateRoleRequest struct {
+	Role Role `json:"role" validate:"required,oneof=admin member viewer"`
+}
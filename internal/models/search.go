@@ -0,0 +1,30 @@
+package models
+
+// TodoSearchHit is a todo found by a full-text search with its relevance and
+// the matching text as HTML, escaped, with the search terms wrapped in <mark> tags
+type TodoSearchHit struct {
+	Todo           Todo
+	Rank           float64
+	TitleHighlight string
+	Snippet        string
+}
+
+// TodoHighlights holds the title and an excerpt of the description of a todo as
+// escaped HTML, with the search terms wrapped in <mark> tags
+type TodoHighlights struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// TodoSearchResult represents a todo in the search results; a higher rank is a better match
+type TodoSearchResult struct {
+	TodoResponse
+	Rank       float64        `json:"rank"`
+	Highlights TodoHighlights `json:"highlights"`
+}
+
+// TodoSearchResponse represents the response for searching todos
+type TodoSearchResponse struct {
+	Data []TodoSearchResult `json:"data"`
+	Meta Meta               `json:"meta"`
+}
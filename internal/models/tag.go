@@ -0,0 +1,79 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Tag represents a label that can be attached to many todos of a workspace
+type Tag struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+	WorkspaceID uuid.UUID `json:"workspace_id" gorm:"type:uuid;not null;uniqueIndex:idx_tags_workspace_name"`
+	Name        string    `json:"name" gorm:"not null;size:50;uniqueIndex:idx_tags_workspace_name"`
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName specifies the table name for Tag
+func (Tag) TableName() string {
+	return "tags"
+}
+
+// BeforeCreate is called before creating a new tag
+func (t *Tag) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
+
+// TodoTag is the join table between todos and tags
+type TodoTag struct {
+	TodoID uuid.UUID `gorm:"type:uuid;primary_key"`
+	TagID  uuid.UUID `gorm:"type:uuid;primary_key;index"`
+}
+
+// TableName specifies the table name for TodoTag
+func (TodoTag) TableName() string {
+	return "todo_tags"
+}
+
+// TagMode controls how a tag filter with several tags matches todos
+type TagMode string
+
+const (
+	// TagModeAny matches todos carrying at least one of the tags
+	TagModeAny TagMode = "any"
+	// TagModeAll matches todos carrying every one of the tags
+	TagModeAll TagMode = "all"
+)
+
+// RenameTagRequest represents the request body for renaming a tag
+type RenameTagRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=50,excludesall=0x2C"`
+}
+
+// MergeTagRequest represents the request body for merging a tag into another
+type MergeTagRequest struct {
+	TargetID uuid.UUID `json:"target_id" validate:"required"`
+}
+
+// TagResponse represents the response body for tag operations
+type TagResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	TodoCount int64     `json:"todo_count"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ToResponse converts a Tag to TagResponse
+func (t *Tag) ToResponse(todoCount int64) TagResponse {
+	return TagResponse{
+		ID:        t.ID,
+		Name:      t.Name,
+		TodoCount: todoCount,
+		CreatedAt: t.CreatedAt,
+	}
+}
@@ -0,0 +1,158 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TimeEntry represents time a user spent on a todo. A running timer is an
+// entry without EndedAt; a user has at most one running timer at a time.
+type TimeEntry struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key"`
+	WorkspaceID uuid.UUID  `json:"-" gorm:"type:uuid;not null;index"`
+	TodoID      uuid.UUID  `json:"todo_id" gorm:"type:uuid;not null;index"`
+	UserID      uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index;uniqueIndex:idx_time_entries_running,where:ended_at IS NULL"`
+	StartedAt   time.Time  `json:"started_at" gorm:"not null;index"`
+	EndedAt     *time.Time `json:"ended_at"`
+	Duration    int64      `json:"duration" gorm:"not null;default:0"` // seconds, 0 while running
+	Note        string     `json:"note" gorm:"size:500"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName specifies the table name for TimeEntry
+func (TimeEntry) TableName() string {
+	return "time_entries"
+}
+
+// BeforeCreate is called before creating a new time entry
+func (e *TimeEntry) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
+// Finish ends the entry at endedAt and records its duration
+func (e *TimeEntry) Finish(endedAt time.Time) {
+	endedAt = endedAt.UTC()
+	e.EndedAt = &endedAt
+	e.Duration = int64(endedAt.Sub(e.StartedAt) / time.Second)
+}
+
+// Elapsed returns the tracked seconds, counting a running timer up to now
+func (e *TimeEntry) Elapsed(now time.Time) int64 {
+	if e.EndedAt == nil {
+		return int64(now.Sub(e.StartedAt) / time.Second)
+	}
+	return e.Duration
+}
+
+// StartTimerRequest represents the optional request body for starting a timer
+type StartTimerRequest struct {
+	Note string `json:"note" validate:"max=500"`
+}
+
+// CreateTimeEntryRequest represents the request body for logging time manually
+type CreateTimeEntryRequest struct {
+	StartedAt time.Time `json:"started_at" validate:"required"`
+	EndedAt   time.Time `json:"ended_at" validate:"required"`
+	Note      string    `json:"note" validate:"max=500"`
+}
+
+// UpdateTimeEntryRequest represents the request body for correcting a time entry.
+// Setting ended_at on a running timer stops it.
+type UpdateTimeEntryRequest struct {
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+	Note      *string    `json:"note,omitempty" validate:"omitempty,max=500"`
+}
+
+// TimeEntryResponse represents the response body for time entry operations
+type TimeEntryResponse struct {
+	ID        uuid.UUID  `json:"id"`
+	TodoID    uuid.UUID  `json:"todo_id"`
+	UserID    uuid.UUID  `json:"user_id"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at"`
+	Duration  int64      `json:"duration"`
+	Running   bool       `json:"running"`
+	Note      string     `json:"note"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// ToResponse converts a TimeEntry to TimeEntryResponse; a running timer reports the seconds elapsed until now
+func (e *TimeEntry) ToResponse(now time.Time) TimeEntryResponse {
+	return TimeEntryResponse{
+		ID:        e.ID,
+		TodoID:    e.TodoID,
+		UserID:    e.UserID,
+		StartedAt: e.StartedAt,
+		EndedAt:   e.EndedAt,
+		Duration:  e.Elapsed(now),
+		Running:   e.EndedAt == nil,
+		Note:      e.Note,
+		CreatedAt: e.CreatedAt,
+		UpdatedAt: e.UpdatedAt,
+	}
+}
+
+// TimeEntryListResponse represents the response for listing time entries
+type TimeEntryListResponse struct {
+	Data []TimeEntryResponse `json:"data"`
+	Meta Meta                `json:"meta"`
+}
+
+// TimeReportGroup represents how tracked time is aggregated in a report
+type TimeReportGroup string
+
+const (
+	// TimeReportByDay groups entries by the UTC day they started on
+	TimeReportByDay TimeReportGroup = "day"
+	// TimeReportByList groups entries by the list of their todo
+	TimeReportByList TimeReportGroup = "list"
+	// TimeReportByTag groups entries by the tags of their todo; an entry counts toward each tag
+	TimeReportByTag TimeReportGroup = "tag"
+)
+
+// TimeReportFilter selects the entries of a time report
+type TimeReportFilter struct {
+	From    time.Time // first day, inclusive
+	To      time.Time // last day, inclusive
+	GroupBy TimeReportGroup
+	UserID  *uuid.UUID
+}
+
+// TimeReportEntry is a finished time entry with the list and tags of its todo
+type TimeReportEntry struct {
+	ID        uuid.UUID
+	TodoID    uuid.UUID
+	StartedAt time.Time
+	Duration  int64
+	ListID    *uuid.UUID
+	ListName  string
+	Tags      []string `gorm:"-"`
+}
+
+// TimeReportRow is the time tracked in one group of a report
+type TimeReportRow struct {
+	Key     string  `json:"key"`
+	Label   string  `json:"label"`
+	Seconds int64   `json:"seconds"`
+	Hours   float64 `json:"hours"`
+	Entries int     `json:"entries"`
+}
+
+// TimeReport represents the response body for time reports
+type TimeReport struct {
+	From         string          `json:"from"`
+	To           string          `json:"to"`
+	GroupBy      TimeReportGroup `json:"group_by"`
+	Rows         []TimeReportRow `json:"rows"`
+	TotalSeconds int64           `json:"total_seconds"`
+	TotalHours   float64         `json:"total_hours"`
+	TotalEntries int             `json:"total_entries"`
+}
@@ -1,6 +1,10 @@
 package models
 
 import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,15 +13,28 @@ import (
 
 // Todo represents a todo item
 type Todo struct {
-	ID          uuid.UUID      `json:"id" gorm:"type:uuid;primary_key"`
-	Title       string         `json:"title" gorm:"not null;size:255" validate:"required,min=1,max=255"`
-	Description string         `json:"description" gorm:"size:1000"`
-	Completed   bool           `json:"completed" gorm:"default:false"`
-	Priority    Priority       `json:"priority" gorm:"default:medium"`
-	DueDate     *time.Time     `json:"due_date,omitempty"`
-	CreatedAt   time.Time      `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt   time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
-	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+	ID               uuid.UUID      `json:"id" gorm:"type:uuid;primary_key"`
+	WorkspaceID      uuid.UUID      `json:"workspace_id" gorm:"type:uuid;index"`
+	UserID           uuid.UUID      `json:"-" gorm:"type:uuid;index"`
+	ListID           *uuid.UUID     `json:"list_id,omitempty" gorm:"type:uuid;index"`
+	ParentID         *uuid.UUID     `json:"parent_id,omitempty" gorm:"type:uuid;index"`
+	Title            string         `json:"title" gorm:"not null;size:255" validate:"required,min=1,max=255"`
+	Description      string         `json:"description" gorm:"size:1000"`
+	Status           string         `json:"status" gorm:"size:50;not null;default:'';index"`
+	Completed        bool           `json:"completed" gorm:"default:false;index"` // derived from Status, kept for queries
+	Priority         Priority       `json:"priority" gorm:"default:medium;index"`
+	DueDate          *time.Time     `json:"due_date,omitempty" gorm:"index"`
+	Position         string         `json:"position" gorm:"size:255;not null;default:'';index"` // rank key within the list, see package rank
+	Tags             []Tag          `json:"tags,omitempty" gorm:"many2many:todo_tags"`
+	Recurrence       string         `json:"recurrence,omitempty" gorm:"size:500"`
+	RecurrenceMode   RecurrenceMode `json:"recurrence_mode,omitempty" gorm:"size:20"`
+	SeriesID         *uuid.UUID     `json:"series_id,omitempty" gorm:"type:uuid;index"`
+	SeriesStart      *time.Time     `json:"-"`
+	Occurrence       int            `json:"occurrence,omitempty"`
+	NextOccurrenceID *uuid.UUID     `json:"next_occurrence_id,omitempty" gorm:"type:uuid"`
+	CreatedAt        time.Time      `json:"created_at" gorm:"autoCreateTime;index"`
+	UpdatedAt        time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt        gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
 // Priority represents the priority level of a todo
@@ -30,6 +47,17 @@ const (
 	PriorityUrgent Priority = "urgent"
 )
 
+// RecurrenceMode selects what the next occurrence of a recurring todo is scheduled from
+type RecurrenceMode string
+
+const (
+	// RecurrenceFromDueDate schedules the next occurrence from the series' due dates,
+	// so a late completion does not shift the schedule
+	RecurrenceFromDueDate RecurrenceMode = "due_date"
+	// RecurrenceFromCompletion schedules the next occurrence from the moment the todo was completed
+	RecurrenceFromCompletion RecurrenceMode = "completion"
+)
+
 // TableName specifies the table name for Todo
 func (Todo) TableName() string {
 	return "todos"
@@ -49,6 +77,14 @@ type CreateTodoRequest struct {
 	Description string     `json:"description" validate:"max=1000"`
 	Priority    Priority   `json:"priority" validate:"oneof=low medium high urgent"`
 	DueDate     *time.Time `json:"due_date,omitempty"`
+	ListID      *uuid.UUID `json:"list_id,omitempty"`
+	ParentID    *uuid.UUID `json:"parent_id,omitempty"`
+	Tags        []string   `json:"tags,omitempty" validate:"omitempty,max=20,dive,required,max=50,excludesall=0x2C"`
+	// Status is a status of the list's workflow; defaults to its first open status
+	Status string `json:"status,omitempty" validate:"max=50"`
+	// Recurrence is an RRULE such as FREQ=WEEKLY;BYDAY=MO
+	Recurrence     string         `json:"recurrence,omitempty" validate:"max=500"`
+	RecurrenceMode RecurrenceMode `json:"recurrence_mode,omitempty" validate:"omitempty,oneof=due_date completion"`
 }
 
 // UpdateTodoRequest represents the request body for updating a todo
@@ -58,32 +94,117 @@ type UpdateTodoRequest struct {
 	Completed   *bool      `json:"completed,omitempty"`
 	Priority    *Priority  `json:"priority,omitempty" validate:"omitempty,oneof=low medium high urgent"`
 	DueDate     *time.Time `json:"due_date,omitempty"`
+	// Tags replaces the todo's tags when present; an empty array removes them all
+	Tags []string `json:"tags,omitempty" validate:"omitempty,max=20,dive,required,max=50,excludesall=0x2C"`
+	// Recurrence sets or changes the todo's RRULE; use DELETE /todos/{id}/recurrence to stop a series
+	Recurrence     *string         `json:"recurrence,omitempty" validate:"omitempty,min=1,max=500"`
+	RecurrenceMode *RecurrenceMode `json:"recurrence_mode,omitempty" validate:"omitempty,oneof=due_date completion"`
 }
 
 // TodoResponse represents the response body for todo operations
 type TodoResponse struct {
-	ID          uuid.UUID  `json:"id"`
-	Title       string     `json:"title"`
-	Description string     `json:"description"`
-	Completed   bool       `json:"completed"`
-	Priority    Priority   `json:"priority"`
-	DueDate     *time.Time `json:"due_date,omitempty"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	ID               uuid.UUID       `json:"id"`
+	WorkspaceID      uuid.UUID       `json:"workspace_id"`
+	CreatedBy        uuid.UUID       `json:"created_by"`
+	ListID           *uuid.UUID      `json:"list_id,omitempty"`
+	ParentID         *uuid.UUID      `json:"parent_id,omitempty"`
+	Title            string          `json:"title"`
+	Description      string          `json:"description"`
+	Status           string          `json:"status"`
+	Position         string          `json:"position"`
+	Completed        bool            `json:"completed"`
+	Priority         Priority        `json:"priority"`
+	DueDate          *time.Time      `json:"due_date,omitempty"`
+	Tags             []string        `json:"tags"`
+	Recurrence       string          `json:"recurrence,omitempty"`
+	RecurrenceMode   RecurrenceMode  `json:"recurrence_mode,omitempty"`
+	SeriesID         *uuid.UUID      `json:"series_id,omitempty"`
+	Occurrence       int             `json:"occurrence,omitempty"`
+	NextOccurrenceID *uuid.UUID      `json:"next_occurrence_id,omitempty"`
+	Subtasks         *Progress       `json:"subtasks,omitempty"`
+	BlockedBy        []DependencyRef `json:"blocked_by"`
+	Blocking         []DependencyRef `json:"blocking"`
+	CommentCount     int64           `json:"comment_count"`
+	TrackedSeconds   int64           `json:"tracked_seconds"`
+	CreatedAt        time.Time       `json:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at"`
+	DeletedAt        *time.Time      `json:"deleted_at,omitempty"`
 }
 
 // ToResponse converts a Todo to TodoResponse
 func (t *Todo) ToResponse() TodoResponse {
-	return TodoResponse{
-		ID:          t.ID,
-		Title:       t.Title,
-		Description: t.Description,
-		Completed:   t.Completed,
-		Priority:    t.Priority,
-		DueDate:     t.DueDate,
-		CreatedAt:   t.CreatedAt,
-		UpdatedAt:   t.UpdatedAt,
+	response := TodoResponse{
+		ID:               t.ID,
+		WorkspaceID:      t.WorkspaceID,
+		CreatedBy:        t.UserID,
+		ListID:           t.ListID,
+		ParentID:         t.ParentID,
+		Title:            t.Title,
+		Description:      t.Description,
+		Status:           t.Status,
+		Position:         t.Position,
+		Completed:        t.Completed,
+		Priority:         t.Priority,
+		DueDate:          t.DueDate,
+		Tags:             t.TagNames(),
+		Recurrence:       t.Recurrence,
+		RecurrenceMode:   t.RecurrenceMode,
+		SeriesID:         t.SeriesID,
+		Occurrence:       t.Occurrence,
+		NextOccurrenceID: t.NextOccurrenceID,
+		CreatedAt:        t.CreatedAt,
+		UpdatedAt:        t.UpdatedAt,
+	}
+	if t.DeletedAt.Valid {
+		deletedAt := t.DeletedAt.Time
+		response.DeletedAt = &deletedAt
 	}
+	return response
+}
+
+// TagNames returns the names of the todo's tags in alphabetical order
+func (t *Todo) TagNames() []string {
+	names := make([]string, len(t.Tags))
+	for i, tag := range t.Tags {
+		names[i] = tag.Name
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Progress summarizes how many of a todo's subtasks are done
+type Progress struct {
+	Total     int64 `json:"total"`
+	Completed int64 `json:"completed"`
+}
+
+// TodoTreeResponse represents a todo together with all of its subtasks
+type TodoTreeResponse struct {
+	TodoResponse
+	Children []TodoTreeResponse `json:"children"`
+}
+
+// SetParentRequest represents the request body for moving a todo under another todo.
+// A null parent_id makes the todo a top-level todo.
+type SetParentRequest struct {
+	ParentID *uuid.UUID `json:"parent_id"`
+}
+
+// SubtaskDeleteMode controls what happens to the subtasks of a deleted todo
+type SubtaskDeleteMode string
+
+const (
+	// SubtaskDeleteOrphan keeps the subtasks and makes them top-level todos
+	SubtaskDeleteOrphan SubtaskDeleteMode = "orphan"
+	// SubtaskDeleteCascade deletes the subtasks and all of their descendants
+	SubtaskDeleteCascade SubtaskDeleteMode = "cascade"
+)
+
+// RecurrencePreviewResponse lists the upcoming due dates of a recurring todo
+type RecurrencePreviewResponse struct {
+	Recurrence     string         `json:"recurrence"`
+	RecurrenceMode RecurrenceMode `json:"recurrence_mode"`
+	Occurrences    []time.Time    `json:"occurrences"`
 }
 
 // TodoListResponse represents the response for listing todos
@@ -92,12 +213,157 @@ type TodoListResponse struct {
 	Meta Meta           `json:"meta"`
 }
 
+// TodoFilter narrows the todos returned by a listing. Unset fields do not
+// restrict the listing; the Before bounds are exclusive, the After bounds inclusive.
+type TodoFilter struct {
+	ListID        *uuid.UUID
+	ParentID      *uuid.UUID
+	Tags          []string
+	TagMode       TagMode
+	Completed     *bool
+	Priorities    []Priority
+	DueBefore     *time.Time
+	DueAfter      *time.Time
+	CreatedBefore *time.Time
+	CreatedAfter  *time.Time
+	HasDueDate    *bool
+	// Text holds words or phrases that each appear in the title or description, ignoring case
+	Text []string
+	// Overdue keeps open todos whose due date has passed
+	Overdue bool
+	// Sort is the order of the listing, most significant key first; newest first when empty
+	Sort []TodoSort
+}
+
+// TodoSortField names a field todo listings can be sorted by
+type TodoSortField string
+
+const (
+	TodoSortCreated   TodoSortField = "created_at"
+	TodoSortUpdated   TodoSortField = "updated_at"
+	TodoSortDueDate   TodoSortField = "due_date"
+	TodoSortPriority  TodoSortField = "priority"
+	TodoSortTitle     TodoSortField = "title"
+	TodoSortStatus    TodoSortField = "status"
+	TodoSortCompleted TodoSortField = "completed"
+	// TodoSortPosition is the manual order of the list
+	TodoSortPosition TodoSortField = "position"
+)
+
+// TodoSortFields lists the fields todo listings can be sorted by
+var TodoSortFields = []TodoSortField{
+	TodoSortCreated, TodoSortUpdated, TodoSortDueDate, TodoSortPriority,
+	TodoSortTitle, TodoSortStatus, TodoSortCompleted, TodoSortPosition,
+}
+
+// MaxTodoSortKeys is the most keys a todo listing can be sorted by
+const MaxTodoSortKeys = 4
+
+// TodoSort is one key of the order of a todo listing
+type TodoSort struct {
+	Field TodoSortField
+	Desc  bool
+}
+
+// DefaultTodoSort lists the newest todos first
+var DefaultTodoSort = []TodoSort{{Field: TodoSortCreated, Desc: true}}
+
+// ParseTodoSort parses a comma-separated list of sort keys such as
+// "-priority,due_date", where a leading "-" sorts that key in descending order
+func ParseTodoSort(value string) ([]TodoSort, error) {
+	var keys []TodoSort
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		key := TodoSort{Field: TodoSortField(strings.TrimPrefix(part, "-")), Desc: strings.HasPrefix(part, "-")}
+		if key.Field == "" {
+			return nil, fmt.Errorf("sort must not contain empty keys")
+		}
+		if !slices.Contains(TodoSortFields, key.Field) {
+			return nil, fmt.Errorf("cannot sort by %q; sortable fields are %s", key.Field, joinSortFields())
+		}
+		for _, other := range keys {
+			if other.Field == key.Field {
+				return nil, fmt.Errorf("sort lists %q more than once", key.Field)
+			}
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) > MaxTodoSortKeys {
+		return nil, fmt.Errorf("sort takes at most %d keys", MaxTodoSortKeys)
+	}
+	return keys, nil
+}
+
+// FormatTodoSort writes sort keys in the form ParseTodoSort reads, with the
+// default order for no keys
+func FormatTodoSort(keys []TodoSort) string {
+	if len(keys) == 0 {
+		keys = DefaultTodoSort
+	}
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = string(key.Field)
+		if key.Desc {
+			parts[i] = "-" + parts[i]
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// TodoCursor is a position in a todo listing: the sort key values of a todo,
+// whether the page continues after or before it and whether the page starts
+// with that todo itself. Filter and Sort identify the listing the cursor was
+// issued for.
+type TodoCursor struct {
+	Filter    string        `json:"f"`
+	Sort      string        `json:"s"`
+	Values    []interface{} `json:"v"`
+	Before    bool          `json:"b,omitempty"`
+	Inclusive bool          `json:"i,omitempty"`
+}
+
+// TodoPage is a page of a todo listing read from a cursor. Next and Prev are the
+// positions after the last and before the first todo of the page, nil when the
+// listing has no todos in that direction.
+type TodoPage struct {
+	Todos []Todo
+	Total int64
+	Next  *TodoCursor
+	Prev  *TodoCursor
+}
+
+// joinSortFields lists the sortable fields for error messages
+func joinSortFields() string {
+	names := make([]string, len(TodoSortFields))
+	for i, field := range TodoSortFields {
+		names[i] = string(field)
+	}
+	return strings.Join(names, ", ")
+}
+
+// ReorderTodoRequest represents the request body for moving a todo within its list.
+// The todo is placed right after `after`, right before `before`, or between the two.
+type ReorderTodoRequest struct {
+	Before *uuid.UUID `json:"before,omitempty"`
+	After  *uuid.UUID `json:"after,omitempty"`
+}
+
+// PositionScope identifies the todos that share one manual order: those of a
+// list, or those outside any list, in a workspace
+type PositionScope struct {
+	WorkspaceID uuid.UUID
+	ListID      *uuid.UUID
+}
+
 // Meta represents metadata for paginated responses
 type Meta struct {
 	Total       int64 `json:"total"`
-	Page        int   `json:"page"`
+	Page        int   `json:"page,omitempty"`
 	PerPage     int   `json:"per_page"`
-	TotalPages  int   `json:"total_pages"`
+	TotalPages  int   `json:"total_pages,omitempty"`
 	HasNext     bool  `json:"has_next"`
 	HasPrevious bool  `json:"has_previous"`
+	// NextCursor and PrevCursor are only set in cursor pagination
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
 }
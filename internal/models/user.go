@@ -0,0 +1,83 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// User represents an account that owns todos
+type User struct {
+	ID           uuid.UUID      `json:"id" gorm:"type:uuid;primary_key"`
+	Email        string         `json:"email" gorm:"not null;size:255;uniqueIndex"`
+	Name         string         `json:"name" gorm:"size:255"`
+	Role         Role           `json:"role" gorm:"size:20;default:member"`
+	PasswordHash string         `json:"-" gorm:"not null"`
+	CreatedAt    time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// TableName specifies the table name for User
+func (User) TableName() string {
+	return "users"
+}
+
+// BeforeCreate is called before creating a new user
+func (u *User) BeforeCreate(tx *gorm.DB) error {
+	if u.ID == uuid.Nil {
+		u.ID = uuid.New()
+	}
+	return nil
+}
+
+// RegisterRequest represents the request body for registering a user
+type RegisterRequest struct {
+	Email    string `json:"email" validate:"required,email,max=255"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+	Name     string `json:"name" validate:"max=255"`
+}
+
+// LoginRequest represents the request body for logging in
+type LoginRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+// UserResponse represents the response body for user operations
+type UserResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Email     string    `json:"email"`
+	Name      string    `json:"name"`
+	Role      Role      `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ToResponse converts a User to UserResponse
+func (u *User) ToResponse() UserResponse {
+	return UserResponse{
+		ID:        u.ID,
+		Email:     u.Email,
+		Name:      u.Name,
+		Role:      u.Role,
+		CreatedAt: u.CreatedAt,
+	}
+}
+
+// AuthResponse represents the response body for register, login and refresh
+type AuthResponse struct {
+	AccessToken      string       `json:"access_token"`
+	TokenType        string       `json:"token_type"`
+	ExpiresAt        time.Time    `json:"expires_at"`
+	RefreshToken     string       `json:"refresh_token"`
+	RefreshExpiresAt time.Time    `json:"refresh_expires_at"`
+	SessionID        uuid.UUID    `json:"session_id"`
+	User             UserResponse `json:"user"`
+}
+
+// UserListResponse represents the response for listing users
+type UserListResponse struct {
+	Data []UserResponse `json:"data"`
+	Meta Meta           `json:"meta"`
+}
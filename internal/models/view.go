@@ -0,0 +1,168 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SavedView represents a named todo listing a user keeps coming back to. Its
+// definition is evaluated whenever the view is opened, so relative dates such
+// as today always refer to the day it is opened on.
+type SavedView struct {
+	ID          uuid.UUID      `json:"id" gorm:"type:uuid;primary_key"`
+	WorkspaceID uuid.UUID      `json:"-" gorm:"type:uuid;not null;index"`
+	UserID      uuid.UUID      `json:"-" gorm:"type:uuid;not null;index"`
+	Name        string         `json:"name" gorm:"not null;size:255"`
+	Shared      bool           `json:"shared" gorm:"not null;default:false"`
+	Definition  ViewDefinition `json:"definition" gorm:"type:text;serializer:json;not null"`
+	CreatedAt   time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// TableName specifies the table name for SavedView
+func (SavedView) TableName() string {
+	return "saved_views"
+}
+
+// BeforeCreate is called before creating a new saved view
+func (v *SavedView) BeforeCreate(tx *gorm.DB) error {
+	if v.ID == uuid.Nil {
+		v.ID = uuid.New()
+	}
+	return nil
+}
+
+// ViewDefinition holds the filters and sort order of a view. Dates are days such
+// as 2026-11-01, RFC 3339 timestamps, or today, tomorrow and yesterday shifted
+// by a number of days as in today+7d; due_after is inclusive and due_before
+// exclusive, like the listing filters of the same name.
+type ViewDefinition struct {
+	Completed  *bool      `json:"completed,omitempty"`
+	Priorities []Priority `json:"priorities,omitempty" validate:"omitempty,max=4,dive,oneof=low medium high urgent"`
+	DueAfter   string     `json:"due_after,omitempty" validate:"max=40"`
+	DueBefore  string     `json:"due_before,omitempty" validate:"max=40"`
+	HasDueDate *bool      `json:"has_due_date,omitempty"`
+	Overdue    bool       `json:"overdue,omitempty"`
+	Tags       []string   `json:"tags,omitempty" validate:"omitempty,max=20,dive,required,max=50,excludesall=0x2C"`
+	TagMode    TagMode    `json:"tag_mode,omitempty" validate:"omitempty,oneof=any all"`
+	// Sort uses the syntax of the sort parameter, e.g. -priority,due_date; newest first when empty
+	Sort string `json:"sort,omitempty" validate:"max=100"`
+}
+
+// SystemView is a built-in view every user of a workspace has
+type SystemView struct {
+	Key        string
+	Name       string
+	Definition ViewDefinition
+}
+
+// SystemViews lists the built-in views, which are addressed by their keys instead of UUIDs
+var SystemViews = []SystemView{
+	{
+		Key:  "today",
+		Name: "Today",
+		Definition: ViewDefinition{
+			Completed: boolPtr(false),
+			DueAfter:  "today",
+			DueBefore: "tomorrow",
+			Sort:      "due_date,-priority",
+		},
+	},
+	{
+		Key:  "upcoming",
+		Name: "Upcoming",
+		Definition: ViewDefinition{
+			Completed: boolPtr(false),
+			DueAfter:  "tomorrow",
+			DueBefore: "today+8d",
+			Sort:      "due_date,-priority",
+		},
+	},
+	{
+		Key:  "overdue",
+		Name: "Overdue",
+		Definition: ViewDefinition{
+			Overdue: true,
+			Sort:    "due_date,-priority",
+		},
+	},
+	{
+		Key:  "no-due-date",
+		Name: "No Due Date",
+		Definition: ViewDefinition{
+			Completed:  boolPtr(false),
+			HasDueDate: boolPtr(false),
+			Sort:       "-priority,-created_at",
+		},
+	},
+}
+
+// FindSystemView returns the built-in view with the given key
+func FindSystemView(key string) (*SystemView, bool) {
+	for i := range SystemViews {
+		if SystemViews[i].Key == key {
+			return &SystemViews[i], true
+		}
+	}
+	return nil, false
+}
+
+// boolPtr returns a pointer to b
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+// CreateViewRequest represents the request body for saving a view
+type CreateViewRequest struct {
+	Name       string         `json:"name" validate:"required,min=1,max=255"`
+	Shared     bool           `json:"shared"`
+	Definition ViewDefinition `json:"definition"`
+}
+
+// UpdateViewRequest represents the request body for changing a saved view. A
+// definition replaces the previous one as a whole.
+type UpdateViewRequest struct {
+	Name       *string         `json:"name,omitempty" validate:"omitempty,min=1,max=255"`
+	Shared     *bool           `json:"shared,omitempty"`
+	Definition *ViewDefinition `json:"definition,omitempty"`
+}
+
+// ViewResponse represents the response body for view operations. Built-in
+// views have their key as ID, are marked as system views and have no owner.
+type ViewResponse struct {
+	ID         string         `json:"id"`
+	Name       string         `json:"name"`
+	System     bool           `json:"system"`
+	Shared     bool           `json:"shared"`
+	OwnerID    *uuid.UUID     `json:"owner_id,omitempty"`
+	Definition ViewDefinition `json:"definition"`
+	CreatedAt  *time.Time     `json:"created_at,omitempty"`
+	UpdatedAt  *time.Time     `json:"updated_at,omitempty"`
+}
+
+// ToResponse converts a SavedView to ViewResponse
+func (v *SavedView) ToResponse() ViewResponse {
+	return ViewResponse{
+		ID:         v.ID.String(),
+		Name:       v.Name,
+		Shared:     v.Shared,
+		OwnerID:    &v.UserID,
+		Definition: v.Definition,
+		CreatedAt:  &v.CreatedAt,
+		UpdatedAt:  &v.UpdatedAt,
+	}
+}
+
+// ToResponse converts a SystemView to ViewResponse
+func (v *SystemView) ToResponse() ViewResponse {
+	return ViewResponse{
+		ID:         v.Key,
+		Name:       v.Name,
+		System:     true,
+		Shared:     true,
+		Definition: v.Definition,
+	}
+}
@@ -0,0 +1,91 @@
+package models
+
+// Workflow describes the statuses a todo moves through and which moves are
+// allowed. Lists without a workflow of their own use DefaultWorkflow.
+type Workflow struct {
+	// Statuses in board order; new todos start in the first status that is not done
+	Statuses []WorkflowStatus `json:"statuses" validate:"required,min=2,max=20,dive"`
+	// Transitions maps a status to the statuses it may move to. When nil, any move is allowed.
+	Transitions map[string][]string `json:"transitions,omitempty"`
+}
+
+// WorkflowStatus is one column of a workflow. Todos in a done status count as completed.
+type WorkflowStatus struct {
+	Key  string `json:"key" validate:"required,max=50"`
+	Name string `json:"name" validate:"required,max=100"`
+	Done bool   `json:"done"`
+}
+
+// Default workflow status keys
+const (
+	StatusBacklog    = "backlog"
+	StatusInProgress = "in_progress"
+	StatusReview     = "review"
+	StatusDone       = "done"
+)
+
+// DefaultWorkflow returns the workflow used by lists that do not define one
+func DefaultWorkflow() *Workflow {
+	return &Workflow{
+		Statuses: []WorkflowStatus{
+			{Key: StatusBacklog, Name: "Backlog"},
+			{Key: StatusInProgress, Name: "In Progress"},
+			{Key: StatusReview, Name: "Review"},
+			{Key: StatusDone, Name: "Done", Done: true},
+		},
+		Transitions: map[string][]string{
+			StatusBacklog:    {StatusInProgress, StatusDone},
+			StatusInProgress: {StatusBacklog, StatusReview, StatusDone},
+			StatusReview:     {StatusInProgress, StatusDone},
+			StatusDone:       {StatusBacklog, StatusInProgress},
+		},
+	}
+}
+
+// Status looks up a status by key
+func (w *Workflow) Status(key string) (WorkflowStatus, bool) {
+	for _, status := range w.Statuses {
+		if status.Key == key {
+			return status, true
+		}
+	}
+	return WorkflowStatus{}, false
+}
+
+// Initial returns the key of the first status that is not done
+func (w *Workflow) Initial() string {
+	for _, status := range w.Statuses {
+		if !status.Done {
+			return status.Key
+		}
+	}
+	return ""
+}
+
+// DoneStatus returns the key of the first done status
+func (w *Workflow) DoneStatus() string {
+	for _, status := range w.Statuses {
+		if status.Done {
+			return status.Key
+		}
+	}
+	return ""
+}
+
+// Allows reports whether a todo may move from one status to another
+func (w *Workflow) Allows(from, to string) bool {
+	if w.Transitions == nil {
+		return true
+	}
+	for _, next := range w.Transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// SetStatusRequest represents the request body for moving a todo to another status
+type SetStatusRequest struct {
+	Status string `json:"status" validate:"required,max=50"`
+}
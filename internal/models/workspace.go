@@ -0,0 +1,91 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Workspace represents a tenant that owns todos and is shared by its members
+type Workspace struct {
+	ID        uuid.UUID      `json:"id" gorm:"type:uuid;primary_key"`
+	Name      string         `json:"name" gorm:"not null;size:255"`
+	CreatedAt time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// TableName specifies the table name for Workspace
+func (Workspace) TableName() string {
+	return "workspaces"
+}
+
+// BeforeCreate is called before creating a new workspace
+func (w *Workspace) BeforeCreate(tx *gorm.DB) error {
+	if w.ID == uuid.Nil {
+		w.ID = uuid.New()
+	}
+	return nil
+}
+
+// WorkspaceRole represents the role of a member within a workspace
+type WorkspaceRole string
+
+const (
+	WorkspaceRoleOwner  WorkspaceRole = "owner"
+	WorkspaceRoleMember WorkspaceRole = "member"
+)
+
+// WorkspaceMember links a user to a workspace
+type WorkspaceMember struct {
+	WorkspaceID uuid.UUID     `json:"workspace_id" gorm:"type:uuid;primary_key"`
+	UserID      uuid.UUID     `json:"user_id" gorm:"type:uuid;primary_key;index"`
+	Role        WorkspaceRole `json:"role" gorm:"size:20;not null;default:member"`
+	CreatedAt   time.Time     `json:"created_at" gorm:"autoCreateTime"`
+	User        User          `json:"-" gorm:"foreignKey:UserID"`
+}
+
+// TableName specifies the table name for WorkspaceMember
+func (WorkspaceMember) TableName() string {
+	return "workspace_members"
+}
+
+// CreateWorkspaceRequest represents the request body for creating a workspace
+type CreateWorkspaceRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=255"`
+}
+
+// AddMemberRequest represents the request body for adding a workspace member
+type AddMemberRequest struct {
+	Email string        `json:"email" validate:"required,email"`
+	Role  WorkspaceRole `json:"role" validate:"omitempty,oneof=owner member"`
+}
+
+// WorkspaceResponse represents the response body for workspace operations
+type WorkspaceResponse struct {
+	ID        uuid.UUID     `json:"id"`
+	Name      string        `json:"name"`
+	Role      WorkspaceRole `json:"role"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+// WorkspaceMemberResponse represents a member of a workspace
+type WorkspaceMemberResponse struct {
+	UserID   uuid.UUID     `json:"user_id"`
+	Email    string        `json:"email"`
+	Name     string        `json:"name"`
+	Role     WorkspaceRole `json:"role"`
+	JoinedAt time.Time     `json:"joined_at"`
+}
+
+// ToResponse converts a WorkspaceMember to WorkspaceMemberResponse
+func (m *WorkspaceMember) ToResponse() WorkspaceMemberResponse {
+	return WorkspaceMemberResponse{
+		UserID:   m.UserID,
+		Email:    m.User.Email,
+		Name:     m.User.Name,
+		Role:     m.Role,
+		JoinedAt: m.CreatedAt,
+	}
+}
@@ -0,0 +1,105 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/1cbyc/go-todo-api/internal/config"
+)
+
+// EmailNotifier sends reminders through an SMTP server. STARTTLS is used when
+// the server offers it, and credentials are only sent when configured.
+type EmailNotifier struct {
+	cfg config.SMTPConfig
+}
+
+// NewEmailNotifier creates a new email notifier
+func NewEmailNotifier(cfg config.SMTPConfig) *EmailNotifier {
+	return &EmailNotifier{cfg: cfg}
+}
+
+// Notify emails the reminder to its recipient
+func (n *EmailNotifier) Notify(ctx context.Context, msg Message) error {
+	if msg.Recipient == "" || strings.ContainsAny(msg.Recipient, "\r\n") {
+		return fmt.Errorf("invalid recipient %q", msg.Recipient)
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", n.cfg.Addr())
+	if err != nil {
+		return fmt.Errorf("failed to connect to smtp server: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, n.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start smtp session: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: n.cfg.Host}); err != nil {
+			return fmt.Errorf("failed to start tls: %w", err)
+		}
+	}
+	if n.cfg.Username != "" {
+		auth := smtp.PlainAuth("", n.cfg.Username, n.cfg.Password, n.cfg.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("failed to authenticate: %w", err)
+		}
+	}
+
+	if err := client.Mail(n.cfg.From); err != nil {
+		return fmt.Errorf("smtp MAIL FROM rejected: %w", err)
+	}
+	if err := client.Rcpt(msg.Recipient); err != nil {
+		return fmt.Errorf("smtp RCPT TO rejected: %w", err)
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp DATA rejected: %w", err)
+	}
+	if _, err := w.Write(n.compose(msg)); err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp server rejected message: %w", err)
+	}
+	return client.Quit()
+}
+
+// compose renders the reminder as an RFC 5322 message. The subject is
+// encoded so todo titles cannot inject headers.
+func (n *EmailNotifier) compose(msg Message) []byte {
+	var body strings.Builder
+	fmt.Fprintf(&body, "Reminder: %s\r\n", msg.Title)
+	if msg.DueDate != nil {
+		fmt.Fprintf(&body, "Due: %s\r\n", msg.DueDate.UTC().Format(time.RFC1123))
+	}
+	if msg.Description != "" {
+		fmt.Fprintf(&body, "\r\n%s\r\n", strings.ReplaceAll(msg.Description, "\n", "\r\n"))
+	}
+	fmt.Fprintf(&body, "\r\nTodo ID: %s\r\n", msg.TodoID)
+
+	headers := []string{
+		"From: " + n.cfg.From,
+		"To: " + msg.Recipient,
+		"Subject: " + mime.QEncoding.Encode("utf-8", "Reminder: "+msg.Title),
+		"Date: " + time.Now().UTC().Format(time.RFC1123Z),
+		"Message-ID: <" + msg.ReminderID.String() + "@todo-api>",
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=utf-8",
+		"Content-Transfer-Encoding: 8bit",
+	}
+	return []byte(strings.Join(headers, "\r\n") + "\r\n\r\n" + body.String())
+}
@@ -0,0 +1,27 @@
+// Package notify delivers reminders over external channels such as email and webhooks.
+package notify
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Message is a single reminder ready to be delivered
+type Message struct {
+	ReminderID  uuid.UUID
+	WorkspaceID uuid.UUID
+	TodoID      uuid.UUID
+	Recipient   string // email address of the user who set the reminder
+	Title       string
+	Description string
+	DueDate     *time.Time
+	RemindAt    time.Time
+}
+
+// Notifier delivers reminder messages over one channel. Implementations must
+// be safe for concurrent use and should honour the context deadline.
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
@@ -0,0 +1,253 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/1cbyc/go-todo-api/internal/config"
+	"github.com/google/uuid"
+)
+
+// testMessage returns a reminder whose title tries to inject a header
+func testMessage() Message {
+	due := time.Date(2026, 11, 1, 9, 0, 0, 0, time.UTC)
+	return Message{
+		ReminderID:  uuid.New(),
+		WorkspaceID: uuid.New(),
+		TodoID:      uuid.New(),
+		Recipient:   "ada@example.com",
+		Title:       "Pay rent\r\nBcc: eve@example.com",
+		Description: "Transfer to the landlord\nbefore noon",
+		DueDate:     &due,
+		RemindAt:    due.Add(-time.Hour),
+	}
+}
+
+// smtpSession is what a fake SMTP server received in one session
+type smtpSession struct {
+	commands []string
+	data     string
+}
+
+// fakeSMTP accepts one SMTP session on a local port and reports what it received
+func fakeSMTP(t *testing.T) (addr string, received <-chan smtpSession) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	sessions := make(chan smtpSession, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var session smtpSession
+		defer func() { sessions <- session }()
+
+		r := bufio.NewReader(conn)
+		reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+		reply("220 fake ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			command := strings.TrimRight(line, "\r\n")
+			session.commands = append(session.commands, command)
+
+			switch verb := strings.ToUpper(strings.SplitN(command, " ", 2)[0]); verb {
+			case "EHLO":
+				reply("250-fake")
+				reply("250 8BITMIME")
+			case "MAIL", "RCPT":
+				reply("250 OK")
+			case "DATA":
+				reply("354 End data with <CR><LF>.<CR><LF>")
+				var data strings.Builder
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if line == ".\r\n" {
+						break
+					}
+					data.WriteString(line)
+				}
+				session.data = data.String()
+				reply("250 OK")
+			case "QUIT":
+				reply("221 Bye")
+				return
+			default:
+				reply("502 Command not implemented")
+			}
+		}
+	}()
+	return listener.Addr().String(), sessions
+}
+
+func TestEmailNotifier(t *testing.T) {
+	addr, received := fakeSMTP(t)
+	host, port, _ := net.SplitHostPort(addr)
+	notifier := NewEmailNotifier(config.SMTPConfig{Host: host, Port: port, From: "todo@example.com"})
+
+	msg := testMessage()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := notifier.Notify(ctx, msg); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	var session smtpSession
+	select {
+	case session = <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the smtp server received no session")
+	}
+
+	for _, want := range []string{"MAIL FROM:<todo@example.com>", "RCPT TO:<ada@example.com>", "DATA", "QUIT"} {
+		if !containsCommand(session.commands, want) {
+			t.Errorf("commands %q lack %q", session.commands, want)
+		}
+	}
+
+	headers, body, ok := strings.Cut(session.data, "\r\n\r\n")
+	if !ok {
+		t.Fatalf("message has no body:\n%s", session.data)
+	}
+	for _, want := range []string{
+		"From: todo@example.com",
+		"To: ada@example.com",
+		"Message-ID: <" + msg.ReminderID.String() + "@todo-api>",
+		"Content-Type: text/plain; charset=utf-8",
+	} {
+		if !strings.Contains(headers, want+"\r\n") {
+			t.Errorf("headers lack %q:\n%s", want, headers)
+		}
+	}
+	subject := ""
+	for _, header := range strings.Split(headers, "\r\n") {
+		if strings.HasPrefix(header, "Bcc:") {
+			t.Errorf("the title injected a header: %q", header)
+		}
+		if strings.HasPrefix(header, "Subject: ") {
+			subject = header
+		}
+	}
+	if !strings.HasPrefix(subject, "Subject: =?utf-8?q?Reminder:") {
+		t.Errorf("subject is missing or not encoded: %q", subject)
+	}
+
+	for _, want := range []string{
+		"Due: Sun, 01 Nov 2026 09:00:00 UTC\r\n",
+		"Transfer to the landlord\r\nbefore noon\r\n",
+		"Todo ID: " + msg.TodoID.String() + "\r\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body lacks %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestEmailNotifierRejectsInvalidRecipient(t *testing.T) {
+	notifier := NewEmailNotifier(config.SMTPConfig{Host: "127.0.0.1", Port: "1", From: "todo@example.com"})
+	msg := testMessage()
+	msg.Recipient = "ada@example.com\r\nRCPT TO:<eve@example.com>"
+	if err := notifier.Notify(context.Background(), msg); err == nil || !strings.Contains(err.Error(), "invalid recipient") {
+		t.Fatalf("Notify with a recipient spanning lines: got %v, want an invalid recipient error", err)
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	const secret = "webhook-secret"
+	msg := testMessage()
+
+	var body []byte
+	var signature, contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get(SignatureHeader)
+		contentType = r.Header.Get("Content-Type")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(config.WebhookConfig{URL: server.URL, Secret: secret, Timeout: 5 * time.Second})
+	if err := notifier.Notify(context.Background(), msg); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); signature != want {
+		t.Errorf("%s = %q, want %q", SignatureHeader, signature, want)
+	}
+	if contentType != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", contentType)
+	}
+
+	var payload webhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("payload is not JSON: %v", err)
+	}
+	if payload.Event != "todo.reminder" || payload.ReminderID != msg.ReminderID || payload.TodoID != msg.TodoID ||
+		payload.Title != msg.Title || payload.Recipient != msg.Recipient || !payload.RemindAt.Equal(msg.RemindAt) {
+		t.Errorf("payload = %+v, want the fields of %+v", payload, msg)
+	}
+}
+
+func TestWebhookNotifierWithoutSecret(t *testing.T) {
+	signed := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, signed = r.Header[http.CanonicalHeaderKey(SignatureHeader)]
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(config.WebhookConfig{URL: server.URL, Timeout: 5 * time.Second})
+	if err := notifier.Notify(context.Background(), testMessage()); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if signed {
+		t.Errorf("request carries %s although no secret is configured", SignatureHeader)
+	}
+}
+
+func TestWebhookNotifierRejectedStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(config.WebhookConfig{URL: server.URL, Timeout: 5 * time.Second})
+	if err := notifier.Notify(context.Background(), testMessage()); err == nil {
+		t.Fatal("Notify succeeded although the webhook answered 502")
+	}
+}
+
+// containsCommand reports whether the smtp commands include want, ignoring case and parameters after it
+func containsCommand(commands []string, want string) bool {
+	for _, command := range commands {
+		if strings.HasPrefix(strings.ToUpper(command), strings.ToUpper(want)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/1cbyc/go-todo-api/internal/config"
+	"github.com/google/uuid"
+)
+
+// SignatureHeader carries the HMAC-SHA256 of the request body when a webhook secret is configured
+const SignatureHeader = "X-Todo-Signature"
+
+// WebhookNotifier posts reminders as JSON to a configured URL
+type WebhookNotifier struct {
+	cfg    config.WebhookConfig
+	client *http.Client
+}
+
+// NewWebhookNotifier creates a new webhook notifier
+func NewWebhookNotifier(cfg config.WebhookConfig) *WebhookNotifier {
+	return &WebhookNotifier{cfg: cfg, client: &http.Client{Timeout: cfg.Timeout}}
+}
+
+// webhookPayload is the JSON body sent for each reminder
+type webhookPayload struct {
+	Event       string     `json:"event"`
+	ReminderID  uuid.UUID  `json:"reminder_id"`
+	WorkspaceID uuid.UUID  `json:"workspace_id"`
+	TodoID      uuid.UUID  `json:"todo_id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	DueDate     *time.Time `json:"due_date"`
+	RemindAt    time.Time  `json:"remind_at"`
+	Recipient   string     `json:"recipient"`
+}
+
+// Notify posts the reminder to the webhook. Any non-2xx response is an error.
+func (n *WebhookNotifier) Notify(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(webhookPayload{
+		Event:       "todo.reminder",
+		ReminderID:  msg.ReminderID,
+		WorkspaceID: msg.WorkspaceID,
+		TodoID:      msg.TodoID,
+		Title:       msg.Title,
+		Description: msg.Description,
+		DueDate:     msg.DueDate,
+		RemindAt:    msg.RemindAt,
+		Recipient:   msg.Recipient,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.cfg.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(n.cfg.Secret))
+		mac.Write(body)
+		req.Header.Set(SignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,431 @@
+// Package query parses the compact query language of todo listings into todo filters.
+//
+//	priority:high,urgent due<2026-11-01 -completed tag:backend "invoice"
+//
+// A query is a list of terms separated by spaces, all of which a todo must match:
+//
+//   - a word or a "quoted phrase" that the title or description contains
+//   - completed or overdue, with -completed for open todos
+//   - priority:high,urgent for todos with any of the priorities, or -priority:low for all others
+//   - tag:a,b for todos with any of the tags; repeated tag: terms must all match
+//   - has:due for todos with a due date, or -has:due for todos without one
+//   - due and created compared with a value by :, <, <=, > or >=
+//
+// Dates are days such as 2026-11-01, RFC 3339 timestamps, or today, tomorrow
+// and yesterday, optionally shifted by a number of days as in today+7d. Days
+// are UTC and timestamps are converted to UTC; due:2026-11-01 matches the
+// whole day. Field names and keywords are case-insensitive.
+package query
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/1cbyc/go-todo-api/internal/models"
+)
+
+const (
+	// MaxLength is the longest query accepted, in characters
+	MaxLength = 500
+	// MaxTerms is the most terms a query can contain
+	MaxTerms = 30
+)
+
+// Error describes why a query was rejected and where. Position counts
+// characters from 1.
+type Error struct {
+	Position int    `json:"position"`
+	Message  string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("at position %d: %s", e.Position, e.Message)
+}
+
+// parser holds the state of parsing one query
+type parser struct {
+	input  []rune
+	pos    int
+	today  time.Time
+	filter *models.TodoFilter
+	// tagTerms counts the tag: terms, which decide the tag mode
+	tagTerms int
+}
+
+// Apply parses a query and narrows the filter by its terms. now is the moment
+// relative dates such as today are resolved against. Fields the filter already
+// restricts cannot be restricted again by the query. On error the filter may
+// be partly changed.
+func Apply(input string, filter *models.TodoFilter, now time.Time) error {
+	p := &parser{
+		input:  []rune(input),
+		today:  now.UTC().Truncate(24 * time.Hour),
+		filter: filter,
+	}
+	if len(p.input) > MaxLength {
+		return p.fail(MaxLength, "query is longer than %d characters", MaxLength)
+	}
+
+	terms := 0
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.input) {
+			return nil
+		}
+		if terms++; terms > MaxTerms {
+			return p.fail(p.pos, "query has more than %d terms", MaxTerms)
+		}
+		if err := p.term(); err != nil {
+			return err
+		}
+	}
+}
+
+// Date parses a date written as in queries and reports whether it names a whole
+// day rather than a moment. now is the moment relative dates are resolved against.
+func Date(value string, now time.Time) (time.Time, bool, error) {
+	p := &parser{input: []rune(value), today: now.UTC().Truncate(24 * time.Hour)}
+	t, day, err := p.date(0, value)
+	if err != nil {
+		return time.Time{}, false, errors.New(err.(*Error).Message)
+	}
+	return t, day, nil
+}
+
+// term parses one term starting at the current position
+func (p *parser) term() error {
+	start := p.pos
+	negated := p.accept('-')
+
+	if p.peek() == '"' {
+		phrase, err := p.quoted()
+		if err != nil {
+			return err
+		}
+		return p.text(start, negated, phrase)
+	}
+
+	nameStart := p.pos
+	for p.pos < len(p.input) && (unicode.IsLetter(p.input[p.pos]) || p.input[p.pos] == '_') {
+		p.pos++
+	}
+	name := strings.ToLower(string(p.input[nameStart:p.pos]))
+	opStart := p.pos
+	op := p.operator()
+
+	if op == "" {
+		for p.pos < len(p.input) && !unicode.IsSpace(p.input[p.pos]) {
+			p.pos++
+		}
+		word := string(p.input[nameStart:p.pos])
+		if word == "" {
+			return p.fail(start, "expected a word or a filter after -")
+		}
+		return p.keyword(start, negated, word)
+	}
+
+	if name == "" {
+		return p.fail(opStart, "expected a field name before %q", op)
+	}
+	valueStart := p.pos
+	value, err := p.value()
+	if err != nil {
+		return err
+	}
+	if value == "" {
+		return p.fail(valueStart, "expected a value after %s%s", name, op)
+	}
+
+	switch name {
+	case "priority":
+		return p.priority(start, valueStart, op, negated, value)
+	case "tag":
+		return p.tags(start, valueStart, op, negated, value)
+	case "has":
+		return p.has(start, valueStart, op, negated, value)
+	case "due":
+		return p.dateRange(start, valueStart, op, negated, value, &p.filter.DueAfter, &p.filter.DueBefore)
+	case "created":
+		return p.dateRange(start, valueStart, op, negated, value, &p.filter.CreatedAfter, &p.filter.CreatedBefore)
+	default:
+		return p.fail(nameStart, "unknown field %q; fields are priority, tag, has, due and created", name)
+	}
+}
+
+// keyword handles a term without an operator: a flag or a word to search for
+func (p *parser) keyword(start int, negated bool, word string) error {
+	switch strings.ToLower(word) {
+	case "completed":
+		if p.filter.Completed != nil {
+			return p.fail(start, "completion is already restricted")
+		}
+		completed := !negated
+		p.filter.Completed = &completed
+		return nil
+	case "overdue":
+		if negated {
+			return p.fail(start, "overdue cannot be negated")
+		}
+		if p.filter.Overdue {
+			return p.fail(start, "overdue is given twice")
+		}
+		p.filter.Overdue = true
+		return nil
+	}
+	return p.text(start, negated, word)
+}
+
+// text adds a word or phrase the title or description must contain
+func (p *parser) text(start int, negated bool, text string) error {
+	if negated {
+		return p.fail(start, "words cannot be excluded")
+	}
+	if strings.TrimSpace(text) == "" {
+		return p.fail(start, "expected text between the quotes")
+	}
+	p.filter.Text = append(p.filter.Text, text)
+	return nil
+}
+
+// priority handles priority:a,b and its negation
+func (p *parser) priority(start, valueStart int, op string, negated bool, value string) error {
+	if op != ":" {
+		return p.fail(valueStart-len(op), "priority only supports :")
+	}
+	if len(p.filter.Priorities) > 0 {
+		return p.fail(start, "priority is already restricted")
+	}
+
+	all := []models.Priority{models.PriorityLow, models.PriorityMedium, models.PriorityHigh, models.PriorityUrgent}
+	named := make(map[models.Priority]bool)
+	pos := valueStart
+	for _, item := range strings.Split(value, ",") {
+		priority := models.Priority(strings.ToLower(item))
+		switch priority {
+		case models.PriorityLow, models.PriorityMedium, models.PriorityHigh, models.PriorityUrgent:
+			named[priority] = true
+		default:
+			return p.fail(pos, "unknown priority %q; priorities are low, medium, high and urgent", item)
+		}
+		pos += len([]rune(item)) + 1
+	}
+
+	for _, priority := range all {
+		if named[priority] != negated {
+			p.filter.Priorities = append(p.filter.Priorities, priority)
+		}
+	}
+	if len(p.filter.Priorities) == 0 {
+		return p.fail(start, "the term excludes every priority")
+	}
+	return nil
+}
+
+// tags handles tag:a,b. One tag: term matches any of its tags; several terms
+// must all match, so each of them may then name only one tag.
+func (p *parser) tags(start, valueStart int, op string, negated bool, value string) error {
+	if negated {
+		return p.fail(start, "tags cannot be excluded")
+	}
+	if op != ":" {
+		return p.fail(valueStart-len(op), "tag only supports :")
+	}
+
+	names := strings.Split(value, ",")
+	pos := valueStart
+	for _, name := range names {
+		if strings.TrimSpace(name) == "" {
+			return p.fail(pos, "expected a tag name")
+		}
+		pos += len([]rune(name)) + 1
+	}
+
+	p.tagTerms++
+	switch {
+	case p.tagTerms == 1 && len(p.filter.Tags) > 0:
+		return p.fail(start, "tags are already restricted")
+	case p.tagTerms > 1 && (len(names) > 1 || len(p.filter.Tags) > 1):
+		return p.fail(start, "a query with several tag: terms can only name one tag in each")
+	}
+	p.filter.Tags = append(p.filter.Tags, names...)
+	p.filter.TagMode = models.TagModeAny
+	if p.tagTerms > 1 {
+		p.filter.TagMode = models.TagModeAll
+	}
+	return nil
+}
+
+// has handles has:due and its negation
+func (p *parser) has(start, valueStart int, op string, negated bool, value string) error {
+	if op != ":" || strings.ToLower(value) != "due" {
+		return p.fail(valueStart, "has only supports has:due")
+	}
+	if p.filter.HasDueDate != nil {
+		return p.fail(start, "having a due date is already restricted")
+	}
+	hasDue := !negated
+	p.filter.HasDueDate = &hasDue
+	return nil
+}
+
+// dateRange narrows a date field to the range a comparison describes. Lower
+// bounds are inclusive and upper bounds exclusive, so due<=2026-11-01 ends
+// before the next day and due>2026-11-01 starts with it.
+func (p *parser) dateRange(start, valueStart int, op string, negated bool, value string, lower, upper **time.Time) error {
+	if negated {
+		return p.fail(start, "date comparisons cannot be negated; reverse the comparison instead")
+	}
+	t, day, err := p.date(valueStart, value)
+	if err != nil {
+		return err
+	}
+
+	next := t.Add(time.Nanosecond)
+	if day {
+		next = t.AddDate(0, 0, 1)
+	}
+
+	var from, until *time.Time
+	switch op {
+	case ":":
+		if !day {
+			return p.fail(valueStart, "%s needs a day, not a timestamp; compare timestamps with < or >", op)
+		}
+		from, until = &t, &next
+	case "<":
+		until = &t
+	case "<=":
+		until = &next
+	case ">":
+		from = &next
+	case ">=":
+		from = &t
+	}
+
+	if from != nil {
+		if *lower != nil {
+			return p.fail(start, "the range already has a lower bound")
+		}
+		*lower = from
+	}
+	if until != nil {
+		if *upper != nil {
+			return p.fail(start, "the range already has an upper bound")
+		}
+		*upper = until
+	}
+	if *lower != nil && *upper != nil && !(*lower).Before(**upper) {
+		return p.fail(start, "the range is empty")
+	}
+	return nil
+}
+
+// date parses a date value and reports whether it names a whole day
+func (p *parser) date(pos int, value string) (time.Time, bool, error) {
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, true, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), false, nil
+	}
+
+	lower := strings.ToLower(value)
+	base, offset := lower, ""
+	if i := strings.IndexAny(lower, "+-"); i >= 0 {
+		base, offset = lower[:i], lower[i:]
+	}
+
+	var day time.Time
+	switch base {
+	case "today":
+		day = p.today
+	case "tomorrow":
+		day = p.today.AddDate(0, 0, 1)
+	case "yesterday":
+		day = p.today.AddDate(0, 0, -1)
+	default:
+		return time.Time{}, false, p.fail(pos, "invalid date %q; use a day like 2026-11-01, a timestamp like 2026-11-01T09:00:00Z or today", value)
+	}
+
+	if offset != "" {
+		days, err := strconv.Atoi(strings.TrimSuffix(offset, "d"))
+		if err != nil || !strings.HasSuffix(offset, "d") || days < -3660 || days > 3660 {
+			return time.Time{}, false, p.fail(pos+len([]rune(base)), "invalid day offset %q; use a number of days like +7d", offset)
+		}
+		day = day.AddDate(0, 0, days)
+	}
+	return day, true, nil
+}
+
+// operator consumes a comparison operator, if any
+func (p *parser) operator() string {
+	for _, op := range []string{"<=", ">=", ":", "<", ">"} {
+		if strings.HasPrefix(string(p.input[p.pos:min(p.pos+2, len(p.input))]), op) {
+			p.pos += len(op)
+			return op
+		}
+	}
+	return ""
+}
+
+// value consumes a quoted or bare value
+func (p *parser) value() (string, error) {
+	if p.peek() == '"' {
+		return p.quoted()
+	}
+	start := p.pos
+	for p.pos < len(p.input) && !unicode.IsSpace(p.input[p.pos]) {
+		p.pos++
+	}
+	return string(p.input[start:p.pos]), nil
+}
+
+// quoted consumes a double-quoted string; quotes cannot be escaped
+func (p *parser) quoted() (string, error) {
+	start := p.pos
+	p.pos++
+	for p.pos < len(p.input) && p.input[p.pos] != '"' {
+		p.pos++
+	}
+	if p.pos >= len(p.input) {
+		return "", p.fail(start, "unterminated quote")
+	}
+	p.pos++
+	if p.pos < len(p.input) && !unicode.IsSpace(p.input[p.pos]) {
+		return "", p.fail(p.pos, "expected a space after the closing quote")
+	}
+	return string(p.input[start+1 : p.pos-1]), nil
+}
+
+// skipSpace moves past whitespace
+func (p *parser) skipSpace() {
+	for p.pos < len(p.input) && unicode.IsSpace(p.input[p.pos]) {
+		p.pos++
+	}
+}
+
+// peek returns the current character, or 0 at the end of the input
+func (p *parser) peek() rune {
+	if p.pos < len(p.input) {
+		return p.input[p.pos]
+	}
+	return 0
+}
+
+// accept consumes the current character if it is r
+func (p *parser) accept(r rune) bool {
+	if p.peek() == r {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// fail returns an error for the character at offset pos
+func (p *parser) fail(pos int, format string, args ...interface{}) error {
+	return &Error{Position: pos + 1, Message: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,139 @@
+package query
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/1cbyc/go-todo-api/internal/models"
+)
+
+// now is the moment relative dates are resolved against in the tests
+var now = time.Date(2026, 10, 17, 15, 30, 0, 0, time.UTC)
+
+func TestApplyErrorPosition(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		position int
+	}{
+		{"unknown field", "priority:high colour:red", 15},
+		{"unknown priority", "priority:high,hgih", 15},
+		{"priority comparison", "priority>high", 9},
+		{"missing field name", "milk :high", 6},
+		{"missing value", "due:", 5},
+		{"unterminated quote", `milk "oat`, 6},
+		{"text after quote", `"oat"milk`, 6},
+		{"excluded word", "milk -oat", 6},
+		{"empty tag", "tag:a,,b", 7},
+		{"has other than due", "has:tags", 5},
+		{"negated overdue", "-overdue", 1},
+		{"invalid date", "due<soon", 5},
+		{"invalid day offset", "due<today+7w", 10},
+		{"day needed", "due:2026-11-01T09:00:00Z", 5},
+		{"empty range", "due>=2026-11-02 due<2026-11-01", 17},
+		{"characters, not bytes", "ünïcödé colour:red", 9},
+		{"too long", strings.Repeat("a", MaxLength+1), MaxLength + 1},
+		{"too many terms", strings.Repeat("a ", MaxTerms) + "b", 2*MaxTerms + 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Apply(tt.input, &models.TodoFilter{}, now)
+			var queryErr *Error
+			if !errors.As(err, &queryErr) {
+				t.Fatalf("Apply(%q) = %v, want a query error", tt.input, err)
+			}
+			if queryErr.Position != tt.position {
+				t.Errorf("Apply(%q) failed at position %d (%s), want %d", tt.input, queryErr.Position, queryErr.Message, tt.position)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	var filter models.TodoFilter
+	err := Apply(`priority:high,urgent due<2026-11-01T09:00:00+02:00 -completed tag:backend "oat milk"`, &filter, now)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	if len(filter.Priorities) != 2 || filter.Priorities[0] != models.PriorityHigh || filter.Priorities[1] != models.PriorityUrgent {
+		t.Errorf("priorities = %v, want [high urgent]", filter.Priorities)
+	}
+	if filter.Completed == nil || *filter.Completed {
+		t.Errorf("completed = %v, want false", filter.Completed)
+	}
+	if len(filter.Tags) != 1 || filter.Tags[0] != "backend" {
+		t.Errorf("tags = %v, want [backend]", filter.Tags)
+	}
+	if len(filter.Text) != 1 || filter.Text[0] != "oat milk" {
+		t.Errorf("text = %q, want [\"oat milk\"]", filter.Text)
+	}
+
+	want := time.Date(2026, 11, 1, 7, 0, 0, 0, time.UTC)
+	if filter.DueBefore == nil || !filter.DueBefore.Equal(want) || filter.DueBefore.Location() != time.UTC {
+		t.Errorf("due before = %v, want %v", filter.DueBefore, want)
+	}
+}
+
+func TestDate(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Time
+		day   bool
+	}{
+		{"2026-11-01", time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), true},
+		{"2026-11-01T09:00:00-05:00", time.Date(2026, 11, 1, 14, 0, 0, 0, time.UTC), false},
+		{"today", time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC), true},
+		{"Tomorrow", time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC), true},
+		{"today+7d", time.Date(2026, 10, 24, 0, 0, 0, 0, time.UTC), true},
+		{"yesterday-1d", time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC), true},
+	}
+
+	for _, tt := range tests {
+		got, day, err := Date(tt.value, now)
+		if err != nil {
+			t.Errorf("Date(%q): %v", tt.value, err)
+			continue
+		}
+		if !got.Equal(tt.want) || got.Location() != time.UTC || day != tt.day {
+			t.Errorf("Date(%q) = %v, %v, want %v, %v", tt.value, got, day, tt.want, tt.day)
+		}
+	}
+
+	if _, _, err := Date("soon", now); err == nil {
+		t.Error("Date(\"soon\") succeeded")
+	}
+}
+
+// FuzzApply checks that no query makes Apply panic and that every error points
+// at a character of the query, or just past its end when it ends too early
+func FuzzApply(f *testing.F) {
+	for _, seed := range []string{
+		"",
+		`priority:high,urgent due<2026-11-01 -completed tag:backend "invoice"`,
+		"due>=today-3d due<today+7d has:due overdue",
+		"-priority:low tag:a tag:b created<=2026-11-01T09:00:00Z",
+		`"unterminated -has:due: due:: <`,
+		"ünïcödé tag:ä,,ö due:tömörrow",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		err := Apply(input, &models.TodoFilter{}, now)
+		if err == nil {
+			return
+		}
+		var queryErr *Error
+		if !errors.As(err, &queryErr) {
+			t.Fatalf("Apply(%q) returned %T, want *Error", input, err)
+		}
+		if length := utf8.RuneCountInString(input); queryErr.Position < 1 || queryErr.Position > length+1 {
+			t.Fatalf("Apply(%q) failed at position %d, outside of its %d characters", input, queryErr.Position, length)
+		}
+	})
+}
@@ -0,0 +1,157 @@
+// Package rank generates lexicographic sort keys for manually ordered todos.
+//
+// A key is a base-36 fraction written with the digits 0-9a-z and no trailing
+// zeros, so keys compare the same as strings and as numbers. There is always
+// another key between two distinct keys, which lets a todo move by rewriting
+// only its own key. Keys grow as the same gap is split again and again; Spread
+// hands out short, evenly spaced keys to rebalance a list.
+package rank
+
+import (
+	"errors"
+	"strings"
+)
+
+// digits are the key digits in ascending order
+const digits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+const base = len(digits)
+
+var (
+	// ErrInvalidKey is returned for keys that contain other characters than 0-9a-z or end in 0
+	ErrInvalidKey = errors.New("invalid rank key")
+	// ErrInvalidRange is returned when the lower key is not below the upper key
+	ErrInvalidRange = errors.New("lower rank key must sort before the upper key")
+)
+
+// Between returns a key that sorts strictly after a and before b. An empty a
+// stands for the start and an empty b for the end of the order, so
+// Between("", "") returns a first key and Between(last, "") appends.
+func Between(a, b string) (string, error) {
+	if !valid(a) || !valid(b) {
+		return "", ErrInvalidKey
+	}
+	if a != "" && b != "" && a >= b {
+		return "", ErrInvalidRange
+	}
+
+	switch {
+	case b == "":
+		return after(a), nil
+	case a == "":
+		return before(b), nil
+	default:
+		return midpoint(a, b), nil
+	}
+}
+
+// Spread returns n keys in ascending order, evenly spaced and one digit
+// longer than needed to tell them apart so that later moves stay short
+func Spread(n int) []string {
+	if n <= 0 {
+		return nil
+	}
+
+	length, total := 1, uint64(base)
+	for total < uint64(base)*uint64(n+1) {
+		length++
+		total *= uint64(base)
+	}
+
+	keys := make([]string, n)
+	buf := make([]byte, length)
+	for i := range keys {
+		value := uint64(i+1) * total / uint64(n+1)
+		for j := length - 1; j >= 0; j-- {
+			buf[j] = digits[value%uint64(base)]
+			value /= uint64(base)
+		}
+		keys[i] = strings.TrimRight(string(buf), "0")
+	}
+	return keys
+}
+
+// valid reports whether key is empty or a well-formed key
+func valid(key string) bool {
+	for i := 0; i < len(key); i++ {
+		if strings.IndexByte(digits, key[i]) < 0 {
+			return false
+		}
+	}
+	return !strings.HasSuffix(key, "0")
+}
+
+// after returns a key after a, bumping its first digit that can still grow
+// so that repeated appends lengthen keys only slowly
+func after(a string) string {
+	if a == "" {
+		return string(digits[base/2])
+	}
+	for i := 0; i < len(a); i++ {
+		if d := strings.IndexByte(digits, a[i]); d < base-1 {
+			return a[:i] + string(digits[d+1])
+		}
+	}
+	return a + string(digits[1])
+}
+
+// before returns a key before b, lowering its first digit that can shrink
+// without reaching zero
+func before(b string) string {
+	for i := 0; i < len(b); i++ {
+		if d := strings.IndexByte(digits, b[i]); d > 1 {
+			return b[:i] + string(digits[d-1])
+		}
+	}
+	return b[:len(b)-1] + string(digits[0]) + string(digits[base-1])
+}
+
+// midpoint returns a key strictly between a and b, where an empty b stands
+// for the end of the order. a must sort before b.
+func midpoint(a, b string) string {
+	if b != "" {
+		// Keep the digits both keys share, reading missing digits of a as zeros
+		n := 0
+		for n < len(b) && digitAt(a, n) == b[n] {
+			n++
+		}
+		if n > 0 {
+			return b[:n] + midpoint(suffix(a, n), b[n:])
+		}
+	}
+
+	lo := 0
+	if a != "" {
+		lo = strings.IndexByte(digits, a[0])
+	}
+	hi := base
+	if b != "" {
+		hi = strings.IndexByte(digits, b[0])
+	}
+	if hi-lo > 1 {
+		return string(digits[(lo+hi)/2])
+	}
+
+	// The leading digits are adjacent: b's first digit alone fits when b goes
+	// on, otherwise keep a's first digit and look further down
+	if len(b) > 1 {
+		return b[:1]
+	}
+	return string(digits[lo]) + midpoint(suffix(a, 1), "")
+}
+
+// digitAt returns the digit of key at i, or zero past its end
+func digitAt(key string, i int) byte {
+	if i < len(key) {
+		return key[i]
+	}
+	return digits[0]
+}
+
+// suffix returns key without its first n digits
+func suffix(key string, n int) string {
+	if n >= len(key) {
+		return ""
+	}
+	return key[n:]
+}
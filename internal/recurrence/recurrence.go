@@ -0,0 +1,95 @@
+// Package recurrence evaluates iCalendar (RFC 5545) recurrence rules for recurring todos.
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/teambition/rrule-go"
+)
+
+// ErrInvalidRule is returned for recurrence rules that cannot be parsed or are not supported
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+// Rule is a parsed RRULE without a start date; the start is supplied per evaluation
+// because it belongs to the series, not to the rule.
+type Rule struct {
+	option rrule.ROption
+}
+
+// Parse parses an RRULE such as "FREQ=WEEKLY;BYDAY=MO" (an "RRULE:" prefix is allowed).
+// DTSTART is not accepted, and rules more frequent than daily or that never
+// produce an occurrence are rejected.
+func Parse(value string) (*Rule, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	if value == "" || strings.ContainsAny(value, "\r\n") || strings.Contains(value, "DTSTART") {
+		return nil, fmt.Errorf("%w: expected a single RRULE such as FREQ=WEEKLY;BYDAY=MO", ErrInvalidRule)
+	}
+
+	option, err := rrule.StrToROption(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRule, err)
+	}
+	switch option.Freq {
+	case rrule.DAILY, rrule.WEEKLY, rrule.MONTHLY, rrule.YEARLY:
+	default:
+		return nil, fmt.Errorf("%w: FREQ must be DAILY, WEEKLY, MONTHLY or YEARLY", ErrInvalidRule)
+	}
+	if _, err := rrule.NewRRule(*option); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRule, err)
+	}
+
+	rule := &Rule{option: *option}
+	if option.Until.IsZero() && option.Count == 0 {
+		now := time.Now()
+		if _, ok := rule.Next(now, now); !ok {
+			return nil, fmt.Errorf("%w: the rule never produces an occurrence", ErrInvalidRule)
+		}
+	}
+	return rule, nil
+}
+
+// String returns the rule in canonical RRULE form
+func (r *Rule) String() string {
+	return r.option.RRuleString()
+}
+
+// Count returns the COUNT limit of the rule, or 0 if it has none
+func (r *Rule) Count() int {
+	return r.option.Count
+}
+
+// Next returns the first occurrence strictly after after, for a series starting at start.
+// The boolean is false once the series has ended through COUNT or UNTIL.
+func (r *Rule) Next(start, after time.Time) (time.Time, bool) {
+	occurrences := r.Upcoming(start, after, 1)
+	if len(occurrences) == 0 {
+		return time.Time{}, false
+	}
+	return occurrences[0], true
+}
+
+// Upcoming returns up to n occurrences strictly after after, for a series starting at start
+func (r *Rule) Upcoming(start, after time.Time, n int) []time.Time {
+	option := r.option
+	option.Dtstart = start.UTC().Truncate(time.Second)
+	rule, err := rrule.NewRRule(option)
+	if err != nil {
+		return nil
+	}
+
+	occurrences := make([]time.Time, 0, n)
+	next := rule.Iterator()
+	for len(occurrences) < n {
+		occurrence, ok := next()
+		if !ok {
+			break
+		}
+		if occurrence.After(after) {
+			occurrences = append(occurrences, occurrence)
+		}
+	}
+	return occurrences
+}
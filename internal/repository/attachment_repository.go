@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AttachmentRepository defines the interface for attachment data operations.
+// Orphaned and Remove serve the cleanup job and are not tenant scoped.
+type AttachmentRepository interface {
+	Create(ctx context.Context, attachment *models.Attachment) error
+	GetByID(ctx context.Context, todoID, id uuid.UUID) (*models.Attachment, error)
+	ListByTodo(ctx context.Context, todoID uuid.UUID) ([]models.Attachment, error)
+	Delete(ctx context.Context, todoID, id uuid.UUID) error
+
+	Orphaned(ctx context.Context, limit int) ([]models.Attachment, error)
+	Remove(ctx context.Context, id uuid.UUID) error
+}
+
+// attachmentRepository implements AttachmentRepository
+type attachmentRepository struct {
+	db *gorm.DB
+}
+
+// NewAttachmentRepository creates a new attachment repository
+func NewAttachmentRepository(db *gorm.DB) AttachmentRepository {
+	return &attachmentRepository{db: db}
+}
+
+// Create records an attachment uploaded by the current user
+func (r *attachmentRepository) Create(ctx context.Context, attachment *models.Attachment) error {
+	db, principal, err := tenantScope(ctx, r.db, "attachments")
+	if err != nil {
+		return err
+	}
+
+	attachment.WorkspaceID = principal.WorkspaceID
+	attachment.UserID = principal.UserID
+	if err := db.Create(attachment).Error; err != nil {
+		return fmt.Errorf("failed to create attachment: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves an attachment of a todo
+func (r *attachmentRepository) GetByID(ctx context.Context, todoID, id uuid.UUID) (*models.Attachment, error) {
+	db, _, err := tenantScope(ctx, r.db, "attachments")
+	if err != nil {
+		return nil, err
+	}
+
+	var attachment models.Attachment
+	if err := db.Where("id = ? AND todo_id = ?", id, todoID).First(&attachment).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("attachment not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+	return &attachment, nil
+}
+
+// ListByTodo retrieves the attachments of a todo, oldest first
+func (r *attachmentRepository) ListByTodo(ctx context.Context, todoID uuid.UUID) ([]models.Attachment, error) {
+	db, _, err := tenantScope(ctx, r.db, "attachments")
+	if err != nil {
+		return nil, err
+	}
+
+	var attachments []models.Attachment
+	if err := db.Where("todo_id = ?", todoID).Order("created_at ASC").Find(&attachments).Error; err != nil {
+		return nil, fmt.Errorf("failed to get attachments: %w", err)
+	}
+	return attachments, nil
+}
+
+// Delete deletes an attachment of a todo
+func (r *attachmentRepository) Delete(ctx context.Context, todoID, id uuid.UUID) error {
+	db, _, err := tenantScope(ctx, r.db, "attachments")
+	if err != nil {
+		return err
+	}
+
+	result := db.Where("id = ? AND todo_id = ?", id, todoID).Delete(&models.Attachment{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete attachment: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("attachment not found: %w", gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
+// Orphaned returns attachments whose todo has been purged. Soft-deleted todos
+// keep their attachments so they can be restored.
+func (r *attachmentRepository) Orphaned(ctx context.Context, limit int) ([]models.Attachment, error) {
+	var attachments []models.Attachment
+	err := r.db.WithContext(ctx).
+		Where("NOT EXISTS (SELECT 1 FROM todos WHERE todos.id = attachments.todo_id)").
+		Limit(limit).
+		Find(&attachments).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get orphaned attachments: %w", err)
+	}
+	return attachments, nil
+}
+
+// Remove deletes an attachment record regardless of workspace
+func (r *attachmentRepository) Remove(ctx context.Context, id uuid.UUID) error {
+	if err := r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.Attachment{}).Error; err != nil {
+		return fmt.Errorf("failed to remove attachment: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CommentRepository defines the interface for comment data operations
+type CommentRepository interface {
+	Create(ctx context.Context, comment *models.Comment) error
+	GetByID(ctx context.Context, todoID, id uuid.UUID) (*models.Comment, error)
+	ListByTodo(ctx context.Context, todoID uuid.UUID, page, perPage int) ([]models.Comment, int64, error)
+	Update(ctx context.Context, comment *models.Comment) error
+	Delete(ctx context.Context, todoID, id uuid.UUID) error
+	Counts(ctx context.Context, todoIDs []uuid.UUID) (map[uuid.UUID]int64, error)
+}
+
+// commentRepository implements CommentRepository
+type commentRepository struct {
+	db *gorm.DB
+}
+
+// NewCommentRepository creates a new comment repository
+func NewCommentRepository(db *gorm.DB) CommentRepository {
+	return &commentRepository{db: db}
+}
+
+// Create adds a comment written by the current user
+func (r *commentRepository) Create(ctx context.Context, comment *models.Comment) error {
+	db, principal, err := tenantScope(ctx, r.db, "comments")
+	if err != nil {
+		return err
+	}
+
+	comment.WorkspaceID = principal.WorkspaceID
+	comment.UserID = principal.UserID
+	if err := db.Omit(clause.Associations).Create(comment).Error; err != nil {
+		return fmt.Errorf("failed to create comment: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a comment on a todo with its author
+func (r *commentRepository) GetByID(ctx context.Context, todoID, id uuid.UUID) (*models.Comment, error) {
+	db, _, err := tenantScope(ctx, r.db, "comments")
+	if err != nil {
+		return nil, err
+	}
+
+	var comment models.Comment
+	if err := db.Preload("User").Where("id = ? AND todo_id = ?", id, todoID).First(&comment).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("comment not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get comment: %w", err)
+	}
+	return &comment, nil
+}
+
+// ListByTodo retrieves a page of the comments on a todo, oldest first
+func (r *commentRepository) ListByTodo(ctx context.Context, todoID uuid.UUID, page, perPage int) ([]models.Comment, int64, error) {
+	db, _, err := tenantScope(ctx, r.db, "comments")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	db = db.Where("todo_id = ?", todoID)
+
+	var total int64
+	if err := db.Model(&models.Comment{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count comments: %w", err)
+	}
+
+	var comments []models.Comment
+	err = db.
+		Preload("User").
+		Order("created_at ASC").
+		Offset((page - 1) * perPage).
+		Limit(perPage).
+		Find(&comments).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get comments: %w", err)
+	}
+	return comments, total, nil
+}
+
+// Update saves the body of a comment
+func (r *commentRepository) Update(ctx context.Context, comment *models.Comment) error {
+	db, _, err := tenantScope(ctx, r.db, "comments")
+	if err != nil {
+		return err
+	}
+
+	err = db.Model(comment).
+		Select("body", "edited_at", "updated_at").
+		Updates(comment).Error
+	if err != nil {
+		return fmt.Errorf("failed to update comment: %w", err)
+	}
+	return nil
+}
+
+// Delete soft-deletes a comment
+func (r *commentRepository) Delete(ctx context.Context, todoID, id uuid.UUID) error {
+	db, _, err := tenantScope(ctx, r.db, "comments")
+	if err != nil {
+		return err
+	}
+
+	result := db.Where("id = ? AND todo_id = ?", id, todoID).Delete(&models.Comment{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete comment: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("comment not found: %w", gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
+// Counts returns the number of live comments on each of the given todos
+func (r *commentRepository) Counts(ctx context.Context, todoIDs []uuid.UUID) (map[uuid.UUID]int64, error) {
+	counts := make(map[uuid.UUID]int64)
+	if len(todoIDs) == 0 {
+		return counts, nil
+	}
+
+	db, _, err := tenantScope(ctx, r.db, "comments")
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		TodoID uuid.UUID
+		Count  int64
+	}
+	err = db.Model(&models.Comment{}).
+		Select("todo_id, COUNT(*) AS count").
+		Where("todo_id IN ?", todoIDs).
+		Group("todo_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count comments: %w", err)
+	}
+	for _, row := range rows {
+		counts[row.TodoID] = row.Count
+	}
+	return counts, nil
+}
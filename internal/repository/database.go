@@ -27,11 +27,13 @@ func NewDatabase(cfg config.DatabaseConfig) (*gorm.DB, error) {
 	switch cfg.Driver {
 	case "postgres":
 		db, err = gorm.Open(postgres.Open(cfg.DSN), &gorm.Config{
-			Logger: gormLogger,
+			Logger:         gormLogger,
+			TranslateError: true,
 		})
 	case "sqlite":
 		db, err = gorm.Open(sqlite.Open(cfg.DSN), &gorm.Config{
-			Logger: gormLogger,
+			Logger:         gormLogger,
+			TranslateError: true,
 		})
 	default:
 		return nil, fmt.Errorf("unsupported database driver: %s", cfg.Driver)
@@ -41,11 +43,47 @@ func NewDatabase(cfg config.DatabaseConfig) (*gorm.DB, error) {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	// Use the explicit join model for the todo/tag association
+	if err := db.SetupJoinTable(&models.Todo{}, "Tags", &models.TodoTag{}); err != nil {
+		return nil, fmt.Errorf("failed to set up todo tags: %w", err)
+	}
+
 	// Auto migrate models
-	if err := db.AutoMigrate(&models.Todo{}); err != nil {
+	if err := db.AutoMigrate(
+		&models.User{},
+		&models.Session{},
+		&models.RefreshToken{},
+		&models.RevokedSession{},
+		&models.APIKey{},
+		&models.Workspace{},
+		&models.WorkspaceMember{},
+		&models.List{},
+		&models.Tag{},
+		&models.Todo{},
+		&models.TodoTag{},
+		&models.TodoDependency{},
+		&models.Reminder{},
+		&models.Comment{},
+		&models.Attachment{},
+		&models.TimeEntry{},
+		&models.TodoRevision{},
+		&models.SavedView{},
+	); err != nil {
 		return nil, fmt.Errorf("failed to migrate database: %w", err)
 	}
 
+	// Todos created before statuses existed get the default status matching their completion
+	err = db.Model(&models.Todo{}).Unscoped().
+		Where("status = ?", "").
+		Update("status", gorm.Expr("CASE WHEN completed THEN ? ELSE ? END", models.StatusDone, models.StatusBacklog)).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to backfill todo statuses: %w", err)
+	}
+
+	if err := setupTodoSearch(db); err != nil {
+		return nil, fmt.Errorf("failed to set up todo search: %w", err)
+	}
+
 	log.Printf("Connected to %s database", cfg.Driver)
 
 	return db, nil
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DependencyRepository defines the interface for todo dependency data operations
+type DependencyRepository interface {
+	Lock(ctx context.Context) error
+	Add(ctx context.Context, todoID, blockedByID uuid.UUID) error
+	Remove(ctx context.Context, todoID, blockedByID uuid.UUID) error
+	BlockerIDs(ctx context.Context, todoIDs []uuid.UUID) ([]uuid.UUID, error)
+	CountOpenBlockers(ctx context.Context, todoID uuid.UUID) (int64, error)
+	ForTodos(ctx context.Context, todoIDs []uuid.UUID) (blockedBy, blocking map[uuid.UUID][]models.DependencyRef, err error)
+}
+
+// dependencyRepository implements DependencyRepository
+type dependencyRepository struct {
+	db *gorm.DB
+}
+
+// NewDependencyRepository creates a new dependency repository
+func NewDependencyRepository(db *gorm.DB) DependencyRepository {
+	return &dependencyRepository{db: db}
+}
+
+// Lock serializes changes to the workspace's dependencies until the surrounding
+// transaction ends, so that a change can be checked against the others first. On
+// SQLite every transaction already holds the database's write lock.
+func (r *dependencyRepository) Lock(ctx context.Context) error {
+	db, principal, err := tenantScope(ctx, r.db, "todo_dependencies")
+	if err != nil {
+		return err
+	}
+	if r.db.Dialector.Name() != "postgres" {
+		return nil
+	}
+
+	err = db.Session(&gorm.Session{NewDB: true}).
+		Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "todo_dependencies:"+principal.WorkspaceID.String()).Error
+	if err != nil {
+		return fmt.Errorf("failed to lock dependencies: %w", err)
+	}
+	return nil
+}
+
+// Add records that a todo is blocked by another. Adding an existing edge is a no-op.
+func (r *dependencyRepository) Add(ctx context.Context, todoID, blockedByID uuid.UUID) error {
+	db, principal, err := tenantScope(ctx, r.db, "todo_dependencies")
+	if err != nil {
+		return err
+	}
+
+	dependency := &models.TodoDependency{
+		TodoID:      todoID,
+		BlockedByID: blockedByID,
+		WorkspaceID: principal.WorkspaceID,
+	}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(dependency).Error; err != nil {
+		return fmt.Errorf("failed to add dependency: %w", err)
+	}
+	return nil
+}
+
+// Remove deletes a dependency edge
+func (r *dependencyRepository) Remove(ctx context.Context, todoID, blockedByID uuid.UUID) error {
+	db, _, err := tenantScope(ctx, r.db, "todo_dependencies")
+	if err != nil {
+		return err
+	}
+
+	result := db.Where("todo_id = ? AND blocked_by_id = ?", todoID, blockedByID).Delete(&models.TodoDependency{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to remove dependency: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("dependency not found: %w", gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
+// BlockerIDs returns the IDs of the live todos that block any of the given todos
+func (r *dependencyRepository) BlockerIDs(ctx context.Context, todoIDs []uuid.UUID) ([]uuid.UUID, error) {
+	if len(todoIDs) == 0 {
+		return nil, nil
+	}
+
+	db, _, err := tenantScope(ctx, r.db, "todo_dependencies")
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []uuid.UUID
+	err = db.Model(&models.TodoDependency{}).
+		Joins("JOIN todos ON todos.id = todo_dependencies.blocked_by_id AND todos.deleted_at IS NULL").
+		Where("todo_dependencies.todo_id IN ?", todoIDs).
+		Distinct().
+		Pluck("todo_dependencies.blocked_by_id", &ids).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blockers: %w", err)
+	}
+	return ids, nil
+}
+
+// CountOpenBlockers returns how many live, incomplete todos block the given todo
+func (r *dependencyRepository) CountOpenBlockers(ctx context.Context, todoID uuid.UUID) (int64, error) {
+	db, _, err := tenantScope(ctx, r.db, "todo_dependencies")
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	err = db.Model(&models.TodoDependency{}).
+		Joins("JOIN todos ON todos.id = todo_dependencies.blocked_by_id AND todos.deleted_at IS NULL").
+		Where("todo_dependencies.todo_id = ? AND NOT todos.completed", todoID).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count blockers: %w", err)
+	}
+	return count, nil
+}
+
+// dependencyRow is the shape of one row of the dependency lookup queries
+type dependencyRow struct {
+	OwnerID   uuid.UUID
+	ID        uuid.UUID
+	Title     string
+	Completed bool
+}
+
+// ForTodos returns, for each of the given todos, the live todos blocking it and the live todos it blocks
+func (r *dependencyRepository) ForTodos(ctx context.Context, todoIDs []uuid.UUID) (map[uuid.UUID][]models.DependencyRef, map[uuid.UUID][]models.DependencyRef, error) {
+	blockedBy := make(map[uuid.UUID][]models.DependencyRef)
+	blocking := make(map[uuid.UUID][]models.DependencyRef)
+	if len(todoIDs) == 0 {
+		return blockedBy, blocking, nil
+	}
+
+	db, _, err := tenantScope(ctx, r.db, "todo_dependencies")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	lookups := []struct {
+		owner, other string
+		into         map[uuid.UUID][]models.DependencyRef
+	}{
+		{owner: "todo_id", other: "blocked_by_id", into: blockedBy},
+		{owner: "blocked_by_id", other: "todo_id", into: blocking},
+	}
+	for _, lookup := range lookups {
+		var rows []dependencyRow
+		err := db.Model(&models.TodoDependency{}).
+			Select("todo_dependencies."+lookup.owner+" AS owner_id, todos.id, todos.title, todos.completed").
+			Joins("JOIN todos ON todos.id = todo_dependencies."+lookup.other+" AND todos.deleted_at IS NULL").
+			Where("todo_dependencies."+lookup.owner+" IN ?", todoIDs).
+			Order("todos.created_at ASC").
+			Scan(&rows).Error
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get dependencies: %w", err)
+		}
+		for _, row := range rows {
+			lookup.into[row.OwnerID] = append(lookup.into[row.OwnerID], models.DependencyRef{
+				ID:        row.ID,
+				Title:     row.Title,
+				Completed: row.Completed,
+			})
+		}
+	}
+	return blockedBy, blocking, nil
+}
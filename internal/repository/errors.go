@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrUnauthenticated is returned when a scoped query runs without an authenticated user in the context
+	ErrUnauthenticated = errors.New("no authenticated user in context")
+	// ErrNoWorkspace is returned when a tenant-scoped query runs without a workspace in the context
+	ErrNoWorkspace = errors.New("no workspace in context")
+	// ErrInvalidCursor is returned for cursors whose values do not fit the sort order of the listing
+	ErrInvalidCursor = errors.New("invalid cursor")
+	// ErrSearchUnavailable is returned when the database has no full-text index,
+	// which happens on SQLite builds without FTS5
+	ErrSearchUnavailable = errors.New("full-text search is not available; build with -tags sqlite_fts5 to enable it on SQLite")
+)
+
+// IsNotFound reports whether err was caused by a missing record
+func IsNotFound(err error) bool {
+	return errors.Is(err, gorm.ErrRecordNotFound)
+}
+
+// IsDuplicate reports whether err was caused by a unique constraint violation
+func IsDuplicate(err error) bool {
+	return errors.Is(err, gorm.ErrDuplicatedKey)
+}
@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ListRepository defines the interface for list data operations
+type ListRepository interface {
+	Create(ctx context.Context, list *models.List) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.List, error)
+	GetAll(ctx context.Context) ([]models.List, error)
+	Update(ctx context.Context, list *models.List) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	UpdateWorkflow(ctx context.Context, list *models.List) error
+	StatusesInUse(ctx context.Context, id uuid.UUID) ([]string, error)
+	Counts(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]models.ListCounts, error)
+}
+
+// listRepository implements ListRepository
+type listRepository struct {
+	db *gorm.DB
+}
+
+// NewListRepository creates a new list repository
+func NewListRepository(db *gorm.DB) ListRepository {
+	return &listRepository{db: db}
+}
+
+// Create creates a new list in the current workspace
+func (r *listRepository) Create(ctx context.Context, list *models.List) error {
+	db, principal, err := tenantScope(ctx, r.db, "lists")
+	if err != nil {
+		return err
+	}
+	list.WorkspaceID = principal.WorkspaceID
+	list.UserID = principal.UserID
+	if err := db.Create(list).Error; err != nil {
+		return fmt.Errorf("failed to create list: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a list by ID
+func (r *listRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.List, error) {
+	db, _, err := tenantScope(ctx, r.db, "lists")
+	if err != nil {
+		return nil, err
+	}
+
+	var list models.List
+	err = db.Where("id = ?", id).First(&list).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("list not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get list: %w", err)
+	}
+	return &list, nil
+}
+
+// GetAll retrieves all lists of the current workspace
+func (r *listRepository) GetAll(ctx context.Context) ([]models.List, error) {
+	db, _, err := tenantScope(ctx, r.db, "lists")
+	if err != nil {
+		return nil, err
+	}
+
+	var lists []models.List
+	if err := db.Order("name ASC").Find(&lists).Error; err != nil {
+		return nil, fmt.Errorf("failed to get lists: %w", err)
+	}
+	return lists, nil
+}
+
+// Update updates a list
+func (r *listRepository) Update(ctx context.Context, list *models.List) error {
+	db, principal, err := tenantScope(ctx, r.db, "lists")
+	if err != nil {
+		return err
+	}
+	list.WorkspaceID = principal.WorkspaceID
+
+	result := db.Model(list).Select("*").Omit("created_at").Updates(list)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update list: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("list not found: %w", gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
+// Delete deletes a list and detaches its todos, which stay in the workspace without a list
+func (r *listRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	db, principal, err := tenantScope(ctx, r.db, "lists")
+	if err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ?", id).Delete(&models.List{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete list: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("list not found: %w", gorm.ErrRecordNotFound)
+		}
+
+		err := tx.Session(&gorm.Session{NewDB: true}).
+			Model(&models.Todo{}).
+			Where("workspace_id = ? AND list_id = ?", principal.WorkspaceID, id).
+			Update("list_id", nil).Error
+		if err != nil {
+			return fmt.Errorf("failed to detach todos: %w", err)
+		}
+		return nil
+	})
+}
+
+// UpdateWorkflow saves a list's workflow and re-derives the completed flag of
+// its todos, since a status may have changed whether it counts as done
+func (r *listRepository) UpdateWorkflow(ctx context.Context, list *models.List) error {
+	db, principal, err := tenantScope(ctx, r.db, "lists")
+	if err != nil {
+		return err
+	}
+
+	var done []string
+	for _, status := range list.EffectiveWorkflow().Statuses {
+		if status.Done {
+			done = append(done, status.Key)
+		}
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(list).Select("workflow").Updates(list)
+		if result.Error != nil {
+			return fmt.Errorf("failed to update workflow: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("list not found: %w", gorm.ErrRecordNotFound)
+		}
+
+		err := tx.Session(&gorm.Session{NewDB: true}).
+			Model(&models.Todo{}).
+			Where("workspace_id = ? AND list_id = ?", principal.WorkspaceID, list.ID).
+			Update("completed", gorm.Expr("status IN ?", done)).Error
+		if err != nil {
+			return fmt.Errorf("failed to update todos: %w", err)
+		}
+		return nil
+	})
+}
+
+// StatusesInUse returns the distinct statuses of the todos in a list
+func (r *listRepository) StatusesInUse(ctx context.Context, id uuid.UUID) ([]string, error) {
+	db, _, err := tenantScope(ctx, r.db, "todos")
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []string
+	err = db.Model(&models.Todo{}).
+		Where("list_id = ?", id).
+		Distinct().
+		Pluck("status", &statuses).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get statuses: %w", err)
+	}
+	return statuses, nil
+}
+
+// listCountRow is the shape of one row of the list counts query
+type listCountRow struct {
+	ListID    uuid.UUID
+	Total     int64
+	Completed int64
+	Overdue   int64
+}
+
+// Counts returns open, completed and overdue todo counts for the given lists
+func (r *listRepository) Counts(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]models.ListCounts, error) {
+	counts := make(map[uuid.UUID]models.ListCounts, len(ids))
+	if len(ids) == 0 {
+		return counts, nil
+	}
+
+	db, _, err := tenantScope(ctx, r.db, "todos")
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []listCountRow
+	err = db.Model(&models.Todo{}).
+		Select(`list_id,
+			COUNT(*) AS total,
+			SUM(CASE WHEN completed THEN 1 ELSE 0 END) AS completed,
+			SUM(CASE WHEN NOT completed AND due_date IS NOT NULL AND due_date < ? THEN 1 ELSE 0 END) AS overdue`, time.Now().UTC()).
+		Where("list_id IN ?", ids).
+		Group("list_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count todos: %w", err)
+	}
+
+	for _, row := range rows {
+		counts[row.ListID] = models.ListCounts{
+			Total:     row.Total,
+			Open:      row.Total - row.Completed,
+			Completed: row.Completed,
+			Overdue:   row.Overdue,
+		}
+	}
+	return counts, nil
+}
@@ -0,0 +1,237 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ReminderRepository defines the interface for reminder data operations.
+// The scheduler methods (Due, Claim, MarkSent, MarkFailed, Cancel, RequeueClaimed)
+// run outside of any request and are deliberately not tenant scoped.
+type ReminderRepository interface {
+	Create(ctx context.Context, reminder *models.Reminder) error
+	ListByTodo(ctx context.Context, todoID uuid.UUID) ([]models.Reminder, error)
+	Delete(ctx context.Context, todoID, id uuid.UUID) error
+	Reschedule(ctx context.Context, todoID uuid.UUID, dueDate *time.Time) error
+	CopyOffsets(ctx context.Context, fromTodoID uuid.UUID, to *models.Todo) error
+
+	Due(ctx context.Context, now time.Time, limit int) ([]models.Reminder, error)
+	Claim(ctx context.Context, id uuid.UUID) (bool, error)
+	MarkSent(ctx context.Context, id uuid.UUID, sentAt time.Time) error
+	MarkFailed(ctx context.Context, id uuid.UUID, attempts int, lastError string, retryAt *time.Time) error
+	Cancel(ctx context.Context, id uuid.UUID) error
+	RequeueClaimed(ctx context.Context) (int64, error)
+}
+
+// reminderRepository implements ReminderRepository
+type reminderRepository struct {
+	db *gorm.DB
+}
+
+// NewReminderRepository creates a new reminder repository
+func NewReminderRepository(db *gorm.DB) ReminderRepository {
+	return &reminderRepository{db: db}
+}
+
+// Create creates a reminder for the current user in the current workspace
+func (r *reminderRepository) Create(ctx context.Context, reminder *models.Reminder) error {
+	db, principal, err := tenantScope(ctx, r.db, "reminders")
+	if err != nil {
+		return err
+	}
+
+	reminder.WorkspaceID = principal.WorkspaceID
+	reminder.UserID = principal.UserID
+	if err := db.Omit("Todo", "User").Create(reminder).Error; err != nil {
+		return fmt.Errorf("failed to create reminder: %w", err)
+	}
+	return nil
+}
+
+// ListByTodo returns the current user's reminders for a todo, earliest first
+func (r *reminderRepository) ListByTodo(ctx context.Context, todoID uuid.UUID) ([]models.Reminder, error) {
+	db, principal, err := tenantScope(ctx, r.db, "reminders")
+	if err != nil {
+		return nil, err
+	}
+
+	var reminders []models.Reminder
+	err = db.Where("todo_id = ? AND user_id = ?", todoID, principal.UserID).
+		Order("remind_at IS NULL, remind_at ASC, created_at ASC").
+		Find(&reminders).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reminders: %w", err)
+	}
+	return reminders, nil
+}
+
+// Delete deletes one of the current user's reminders for a todo
+func (r *reminderRepository) Delete(ctx context.Context, todoID, id uuid.UUID) error {
+	db, principal, err := tenantScope(ctx, r.db, "reminders")
+	if err != nil {
+		return err
+	}
+
+	result := db.Where("id = ? AND todo_id = ? AND user_id = ?", id, todoID, principal.UserID).Delete(&models.Reminder{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete reminder: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("reminder not found: %w", gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
+// Reschedule moves the offset reminders of a todo after its due date changed.
+// Reminders that were already delivered or given up on are re-armed when the
+// new reminder time is still ahead.
+func (r *reminderRepository) Reschedule(ctx context.Context, todoID uuid.UUID, dueDate *time.Time) error {
+	db, _, err := tenantScope(ctx, r.db, "reminders")
+	if err != nil {
+		return err
+	}
+
+	var reminders []models.Reminder
+	err = db.Where("todo_id = ? AND offset_minutes IS NOT NULL AND status <> ?", todoID, models.ReminderCancelled).
+		Find(&reminders).Error
+	if err != nil {
+		return fmt.Errorf("failed to get reminders: %w", err)
+	}
+
+	now := time.Now()
+	for _, reminder := range reminders {
+		reminder.Schedule(dueDate)
+		updates := map[string]interface{}{"remind_at": reminder.RemindAt}
+		if reminder.Status != models.ReminderPending && reminder.RemindAt != nil && reminder.RemindAt.After(now) {
+			updates["status"] = models.ReminderPending
+			updates["attempts"] = 0
+			updates["last_error"] = ""
+			updates["sent_at"] = nil
+		}
+		if err := db.Model(&models.Reminder{}).Where("id = ?", reminder.ID).Updates(updates).Error; err != nil {
+			return fmt.Errorf("failed to reschedule reminder: %w", err)
+		}
+	}
+	return nil
+}
+
+// CopyOffsets gives a new todo the offset reminders of another, scheduled
+// against the new todo's due date. Used when a recurring todo advances.
+func (r *reminderRepository) CopyOffsets(ctx context.Context, fromTodoID uuid.UUID, to *models.Todo) error {
+	db, _, err := tenantScope(ctx, r.db, "reminders")
+	if err != nil {
+		return err
+	}
+
+	var reminders []models.Reminder
+	if err := db.Where("todo_id = ? AND offset_minutes IS NOT NULL", fromTodoID).Find(&reminders).Error; err != nil {
+		return fmt.Errorf("failed to get reminders: %w", err)
+	}
+
+	for _, source := range reminders {
+		copied := models.Reminder{
+			WorkspaceID:   source.WorkspaceID,
+			TodoID:        to.ID,
+			UserID:        source.UserID,
+			Channel:       source.Channel,
+			OffsetMinutes: source.OffsetMinutes,
+			Status:        models.ReminderPending,
+		}
+		copied.Schedule(to.DueDate)
+		if err := db.Omit("Todo", "User").Create(&copied).Error; err != nil {
+			return fmt.Errorf("failed to copy reminder: %w", err)
+		}
+	}
+	return nil
+}
+
+// Due returns pending reminders whose time has come, with their todo and
+// recipient loaded. The todo is nil when it has since been deleted.
+func (r *reminderRepository) Due(ctx context.Context, now time.Time, limit int) ([]models.Reminder, error) {
+	var reminders []models.Reminder
+	err := r.db.WithContext(ctx).
+		Preload("Todo").
+		Preload("User").
+		Where("status = ? AND remind_at <= ?", models.ReminderPending, now.UTC()).
+		Order("remind_at ASC").
+		Limit(limit).
+		Find(&reminders).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due reminders: %w", err)
+	}
+	return reminders, nil
+}
+
+// Claim marks a pending reminder as being sent. It reports false when the
+// reminder was deleted or claimed in the meantime.
+func (r *reminderRepository) Claim(ctx context.Context, id uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.Reminder{}).
+		Where("id = ? AND status = ?", id, models.ReminderPending).
+		Update("status", models.ReminderSending)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to claim reminder: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// MarkSent records a successful delivery
+func (r *reminderRepository) MarkSent(ctx context.Context, id uuid.UUID, sentAt time.Time) error {
+	err := r.db.WithContext(ctx).Model(&models.Reminder{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     models.ReminderSent,
+			"sent_at":    sentAt.UTC(),
+			"last_error": "",
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to mark reminder sent: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed records a failed delivery. The reminder is retried at retryAt,
+// or marked as failed for good when retryAt is nil.
+func (r *reminderRepository) MarkFailed(ctx context.Context, id uuid.UUID, attempts int, lastError string, retryAt *time.Time) error {
+	updates := map[string]interface{}{
+		"attempts":   attempts,
+		"last_error": lastError,
+		"status":     models.ReminderFailed,
+	}
+	if retryAt != nil {
+		updates["status"] = models.ReminderPending
+		updates["remind_at"] = retryAt.UTC()
+	}
+
+	if err := r.db.WithContext(ctx).Model(&models.Reminder{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+		return fmt.Errorf("failed to mark reminder failed: %w", err)
+	}
+	return nil
+}
+
+// Cancel drops a reminder that no longer needs to be delivered
+func (r *reminderRepository) Cancel(ctx context.Context, id uuid.UUID) error {
+	err := r.db.WithContext(ctx).Model(&models.Reminder{}).
+		Where("id = ?", id).
+		Update("status", models.ReminderCancelled).Error
+	if err != nil {
+		return fmt.Errorf("failed to cancel reminder: %w", err)
+	}
+	return nil
+}
+
+// RequeueClaimed returns reminders left claimed by an unclean shutdown to the
+// pending state so they are delivered after a restart
+func (r *reminderRepository) RequeueClaimed(ctx context.Context) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.Reminder{}).
+		Where("status = ?", models.ReminderSending).
+		Update("status", models.ReminderPending)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to requeue reminders: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RevisionRepository defines the interface for todo revision data operations
+type RevisionRepository interface {
+	Create(ctx context.Context, revision *models.TodoRevision) error
+	GetByNumber(ctx context.Context, todoID uuid.UUID, number int) (*models.TodoRevision, error)
+	ListByTodo(ctx context.Context, todoID uuid.UUID, page, perPage int) ([]models.TodoRevision, int64, error)
+}
+
+// revisionRepository implements RevisionRepository
+type revisionRepository struct {
+	db *gorm.DB
+}
+
+// NewRevisionRepository creates a new revision repository
+func NewRevisionRepository(db *gorm.DB) RevisionRepository {
+	return &revisionRepository{db: db}
+}
+
+// Create records a revision made by the current user, numbered after the
+// todo's latest revision
+func (r *revisionRepository) Create(ctx context.Context, revision *models.TodoRevision) error {
+	db, principal, err := tenantScope(ctx, r.db, "todo_revisions")
+	if err != nil {
+		return err
+	}
+
+	revision.WorkspaceID = principal.WorkspaceID
+	revision.ActorID = principal.UserID
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		// Locking the todo numbers concurrent revisions of it one after another
+		var locked []uuid.UUID
+		err := tx.Session(&gorm.Session{NewDB: true}).
+			Model(&models.Todo{}).
+			Unscoped().
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("workspace_id = ? AND id = ?", principal.WorkspaceID, revision.TodoID).
+			Pluck("id", &locked).Error
+		if err != nil {
+			return fmt.Errorf("failed to lock todo: %w", err)
+		}
+
+		var latest int
+		err = tx.Model(&models.TodoRevision{}).
+			Where("todo_id = ?", revision.TodoID).
+			Select("COALESCE(MAX(number), 0)").
+			Scan(&latest).Error
+		if err != nil {
+			return fmt.Errorf("failed to number revision: %w", err)
+		}
+
+		revision.Number = latest + 1
+		if err := tx.Omit(clause.Associations).Create(revision).Error; err != nil {
+			return fmt.Errorf("failed to create revision: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetByNumber retrieves a revision of a todo with its actor
+func (r *revisionRepository) GetByNumber(ctx context.Context, todoID uuid.UUID, number int) (*models.TodoRevision, error) {
+	db, _, err := tenantScope(ctx, r.db, "todo_revisions")
+	if err != nil {
+		return nil, err
+	}
+
+	var revision models.TodoRevision
+	if err := db.Preload("Actor").Where("todo_id = ? AND number = ?", todoID, number).First(&revision).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("revision not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get revision: %w", err)
+	}
+	return &revision, nil
+}
+
+// ListByTodo retrieves a page of the revisions of a todo, latest first
+func (r *revisionRepository) ListByTodo(ctx context.Context, todoID uuid.UUID, page, perPage int) ([]models.TodoRevision, int64, error) {
+	db, _, err := tenantScope(ctx, r.db, "todo_revisions")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	db = db.Where("todo_id = ?", todoID)
+
+	var total int64
+	if err := db.Model(&models.TodoRevision{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count revisions: %w", err)
+	}
+
+	var revisions []models.TodoRevision
+	err = db.
+		Preload("Actor").
+		Order("number DESC").
+		Offset((page - 1) * perPage).
+		Limit(perPage).
+		Find(&revisions).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get revisions: %w", err)
+	}
+	return revisions, total, nil
+}
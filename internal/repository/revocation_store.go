@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/1cbyc/go-todo-api/internal/auth"
+	"github.com/1cbyc/go-todo-api/internal/config"
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// dbRevocationStore implements auth.RevocationStore on the revoked_sessions table
+type dbRevocationStore struct {
+	db *gorm.DB
+}
+
+// NewDBRevocationStore creates a revocation store backed by the database
+func NewDBRevocationStore(db *gorm.DB) auth.RevocationStore {
+	return &dbRevocationStore{db: db}
+}
+
+// Revoke records the session as revoked and prunes expired entries
+func (s *dbRevocationStore) Revoke(ctx context.Context, sessionID uuid.UUID, until time.Time) error {
+	entry := models.RevokedSession{SessionID: sessionID, ExpiresAt: until}
+	err := s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(&entry).Error
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	if err := s.db.WithContext(ctx).Where("expires_at < ?", time.Now()).Delete(&models.RevokedSession{}).Error; err != nil {
+		return fmt.Errorf("failed to prune revoked sessions: %w", err)
+	}
+	return nil
+}
+
+// IsRevoked reports whether the session has an unexpired revocation entry
+func (s *dbRevocationStore) IsRevoked(ctx context.Context, sessionID uuid.UUID) (bool, error) {
+	var count int64
+	err := s.db.WithContext(ctx).
+		Model(&models.RevokedSession{}).
+		Where("session_id = ? AND expires_at > ?", sessionID, time.Now()).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check revocation: %w", err)
+	}
+	return count > 0, nil
+}
+
+// redisRevocationStore implements auth.RevocationStore with expiring Redis keys
+type redisRevocationStore struct {
+	client *redis.Client
+}
+
+// NewRedisRevocationStore creates a revocation store backed by Redis
+func NewRedisRevocationStore(client *redis.Client) auth.RevocationStore {
+	return &redisRevocationStore{client: client}
+}
+
+// NewRedisClient creates a Redis client and verifies the connection
+func NewRedisClient(ctx context.Context, cfg config.RedisConfig) (*redis.Client, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.Addr(),
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+	return client, nil
+}
+
+// revokedSessionKey returns the Redis key for a revoked session
+func revokedSessionKey(sessionID uuid.UUID) string {
+	return "revoked_session:" + sessionID.String()
+}
+
+// Revoke records the session as revoked until the given time
+func (s *redisRevocationStore) Revoke(ctx context.Context, sessionID uuid.UUID, until time.Time) error {
+	ttl := time.Until(until)
+	if ttl <= 0 {
+		return nil
+	}
+	if err := s.client.Set(ctx, revokedSessionKey(sessionID), 1, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return nil
+}
+
+// IsRevoked reports whether the session key is present
+func (s *redisRevocationStore) IsRevoked(ctx context.Context, sessionID uuid.UUID) (bool, error) {
+	n, err := s.client.Exists(ctx, revokedSessionKey(sessionID)).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check revocation: %w", err)
+	}
+	return n > 0, nil
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/1cbyc/go-todo-api/internal/auth"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// tenantScope returns a query restricted to rows of table that belong to the workspace
+// bound to the request. Tenant-owned tables are only ever queried through it, so a request
+// without a workspace fails closed instead of reading or writing another tenant's rows.
+// Within a Transactor transaction the query runs in that transaction.
+func tenantScope(ctx context.Context, db *gorm.DB, table string) (*gorm.DB, *auth.Principal, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, nil, ErrUnauthenticated
+	}
+	if principal.WorkspaceID == uuid.Nil {
+		return nil, nil, ErrNoWorkspace
+	}
+	return conn(ctx, db).WithContext(ctx).Where(table+".workspace_id = ?", principal.WorkspaceID).Session(&gorm.Session{}), principal, nil
+}
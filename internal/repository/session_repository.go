@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrRefreshTokenUsed is returned when rotating a refresh token that was already rotated
+var ErrRefreshTokenUsed = errors.New("refresh token already used")
+
+// SessionRepository defines the interface for session and refresh token data operations
+type SessionRepository interface {
+	Create(ctx context.Context, session *models.Session, token *models.RefreshToken) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Session, error)
+	ListActive(ctx context.Context, userID uuid.UUID) ([]models.Session, error)
+	Revoke(ctx context.Context, id uuid.UUID) error
+	GetRefreshToken(ctx context.Context, tokenHash string) (*models.RefreshToken, error)
+	Rotate(ctx context.Context, old *models.RefreshToken, next *models.RefreshToken) error
+}
+
+// sessionRepository implements SessionRepository
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+// NewSessionRepository creates a new session repository
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepository{db: db}
+}
+
+// Create creates a new session together with its first refresh token
+func (r *sessionRepository) Create(ctx context.Context, session *models.Session, token *models.RefreshToken) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(session).Error; err != nil {
+			return fmt.Errorf("failed to create session: %w", err)
+		}
+		token.SessionID = session.ID
+		if err := tx.Create(token).Error; err != nil {
+			return fmt.Errorf("failed to create refresh token: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetByID retrieves a session by ID
+func (r *sessionRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Session, error) {
+	var session models.Session
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&session).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("session not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	return &session, nil
+}
+
+// ListActive retrieves the sessions of a user that are neither revoked nor expired
+func (r *sessionRepository) ListActive(ctx context.Context, userID uuid.UUID) ([]models.Session, error) {
+	var sessions []models.Session
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_used_at DESC").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// Revoke marks a session as revoked
+func (r *sessionRepository) Revoke(ctx context.Context, id uuid.UUID) error {
+	err := r.db.WithContext(ctx).
+		Model(&models.Session{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now()).Error
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return nil
+}
+
+// GetRefreshToken retrieves a refresh token by its hash
+func (r *sessionRepository) GetRefreshToken(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&token).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("refresh token not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+	return &token, nil
+}
+
+// Rotate consumes the old refresh token and stores its replacement atomically.
+// It returns ErrRefreshTokenUsed if the old token was consumed concurrently.
+func (r *sessionRepository) Rotate(ctx context.Context, old *models.RefreshToken, next *models.RefreshToken) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		result := tx.Model(&models.RefreshToken{}).
+			Where("id = ? AND used_at IS NULL", old.ID).
+			Update("used_at", now)
+		if result.Error != nil {
+			return fmt.Errorf("failed to consume refresh token: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrRefreshTokenUsed
+		}
+
+		next.SessionID = old.SessionID
+		if err := tx.Create(next).Error; err != nil {
+			return fmt.Errorf("failed to create refresh token: %w", err)
+		}
+
+		err := tx.Model(&models.Session{}).
+			Where("id = ?", old.SessionID).
+			Updates(map[string]interface{}{"last_used_at": now, "expires_at": next.ExpiresAt}).Error
+		if err != nil {
+			return fmt.Errorf("failed to update session: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,256 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TagRepository defines the interface for tag data operations
+type TagRepository interface {
+	GetAll(ctx context.Context) ([]models.Tag, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Tag, error)
+	GetByName(ctx context.Context, name string) (*models.Tag, error)
+	Counts(ctx context.Context) (map[uuid.UUID]int64, error)
+	Rename(ctx context.Context, id uuid.UUID, name string) error
+	Merge(ctx context.Context, sourceID, targetID uuid.UUID) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// tagRepository implements TagRepository
+type tagRepository struct {
+	db *gorm.DB
+}
+
+// NewTagRepository creates a new tag repository
+func NewTagRepository(db *gorm.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+// GetAll retrieves all tags of the current workspace
+func (r *tagRepository) GetAll(ctx context.Context) ([]models.Tag, error) {
+	db, _, err := tenantScope(ctx, r.db, "tags")
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []models.Tag
+	if err := db.Order("name ASC").Find(&tags).Error; err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+	return tags, nil
+}
+
+// GetByID retrieves a tag by ID
+func (r *tagRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Tag, error) {
+	db, _, err := tenantScope(ctx, r.db, "tags")
+	if err != nil {
+		return nil, err
+	}
+	return findTag(db.Where("id = ?", id))
+}
+
+// GetByName retrieves a tag by its normalized name
+func (r *tagRepository) GetByName(ctx context.Context, name string) (*models.Tag, error) {
+	db, _, err := tenantScope(ctx, r.db, "tags")
+	if err != nil {
+		return nil, err
+	}
+	return findTag(db.Where("name = ?", name))
+}
+
+// findTag runs a single-tag lookup
+func findTag(db *gorm.DB) (*models.Tag, error) {
+	var tag models.Tag
+	if err := db.First(&tag).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("tag not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get tag: %w", err)
+	}
+	return &tag, nil
+}
+
+// tagCountRow is the shape of one row of the tag counts query
+type tagCountRow struct {
+	TagID uuid.UUID
+	Total int64
+}
+
+// Counts returns how many live todos carry each tag of the current workspace
+func (r *tagRepository) Counts(ctx context.Context) (map[uuid.UUID]int64, error) {
+	db, _, err := tenantScope(ctx, r.db, "todos")
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []tagCountRow
+	err = db.Model(&models.Todo{}).
+		Select("todo_tags.tag_id, COUNT(*) AS total").
+		Joins("JOIN todo_tags ON todo_tags.todo_id = todos.id").
+		Group("todo_tags.tag_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tagged todos: %w", err)
+	}
+
+	counts := make(map[uuid.UUID]int64, len(rows))
+	for _, row := range rows {
+		counts[row.TagID] = row.Total
+	}
+	return counts, nil
+}
+
+// Rename changes the name of a tag. Todos reference tags by ID, so their
+// associations follow the new name without being rewritten.
+func (r *tagRepository) Rename(ctx context.Context, id uuid.UUID, name string) error {
+	db, _, err := tenantScope(ctx, r.db, "tags")
+	if err != nil {
+		return err
+	}
+
+	result := db.Model(&models.Tag{}).Where("id = ?", id).Update("name", name)
+	if result.Error != nil {
+		return fmt.Errorf("failed to rename tag: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("tag not found: %w", gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
+// Merge moves every association of the source tag to the target tag and deletes
+// the source, all in one transaction. Todos that already carry both tags keep a
+// single association with the target.
+func (r *tagRepository) Merge(ctx context.Context, sourceID, targetID uuid.UUID) error {
+	db, _, err := tenantScope(ctx, r.db, "tags")
+	if err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		var found int64
+		if err := tx.Model(&models.Tag{}).Where("id IN ?", []uuid.UUID{sourceID, targetID}).Count(&found).Error; err != nil {
+			return fmt.Errorf("failed to get tags: %w", err)
+		}
+		if found != 2 {
+			return fmt.Errorf("tag not found: %w", gorm.ErrRecordNotFound)
+		}
+
+		raw := tx.Session(&gorm.Session{NewDB: true})
+		err := raw.Exec(`INSERT INTO todo_tags (todo_id, tag_id)
+			SELECT todo_id, ? FROM todo_tags
+			WHERE tag_id = ? AND todo_id NOT IN (SELECT todo_id FROM todo_tags WHERE tag_id = ?)`,
+			targetID, sourceID, targetID).Error
+		if err != nil {
+			return fmt.Errorf("failed to move tag associations: %w", err)
+		}
+
+		if err := raw.Where("tag_id = ?", sourceID).Delete(&models.TodoTag{}).Error; err != nil {
+			return fmt.Errorf("failed to remove tag associations: %w", err)
+		}
+		if err := tx.Where("id = ?", sourceID).Delete(&models.Tag{}).Error; err != nil {
+			return fmt.Errorf("failed to delete tag: %w", err)
+		}
+		return nil
+	})
+}
+
+// Delete deletes a tag and removes it from every todo
+func (r *tagRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	db, _, err := tenantScope(ctx, r.db, "tags")
+	if err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if _, err := findTag(tx.Where("id = ?", id)); err != nil {
+			return err
+		}
+
+		raw := tx.Session(&gorm.Session{NewDB: true})
+		if err := raw.Where("tag_id = ?", id).Delete(&models.TodoTag{}).Error; err != nil {
+			return fmt.Errorf("failed to remove tag associations: %w", err)
+		}
+		if err := tx.Where("id = ?", id).Delete(&models.Tag{}).Error; err != nil {
+			return fmt.Errorf("failed to delete tag: %w", err)
+		}
+		return nil
+	})
+}
+
+// replaceTodoTags makes the todo's tag set match todo.Tags, looking tags up by
+// name and creating the missing ones in the todo's workspace. A nil todo.Tags
+// leaves the associations untouched. tx must be a transaction without tenant
+// conditions; todo.Tags is replaced with the stored tags.
+func replaceTodoTags(tx *gorm.DB, todo *models.Todo) error {
+	if todo.Tags == nil {
+		return nil
+	}
+
+	names := make([]string, len(todo.Tags))
+	for i, tag := range todo.Tags {
+		names[i] = tag.Name
+	}
+
+	tags, err := resolveTags(tx, todo.WorkspaceID, names)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Where("todo_id = ?", todo.ID).Delete(&models.TodoTag{}).Error; err != nil {
+		return fmt.Errorf("failed to clear todo tags: %w", err)
+	}
+	if len(tags) > 0 {
+		links := make([]models.TodoTag, len(tags))
+		for i, tag := range tags {
+			links[i] = models.TodoTag{TodoID: todo.ID, TagID: tag.ID}
+		}
+		if err := tx.Create(&links).Error; err != nil {
+			return fmt.Errorf("failed to tag todo: %w", err)
+		}
+	}
+
+	todo.Tags = tags
+	return nil
+}
+
+// resolveTags returns the workspace's tags with the given names, creating any
+// that do not exist yet. Concurrent creators of the same tag converge on one row.
+func resolveTags(tx *gorm.DB, workspaceID uuid.UUID, names []string) ([]models.Tag, error) {
+	tags := []models.Tag{}
+	if len(names) == 0 {
+		return tags, nil
+	}
+
+	if err := tx.Where("workspace_id = ? AND name IN ?", workspaceID, names).Find(&tags).Error; err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+	if len(tags) == len(names) {
+		return tags, nil
+	}
+
+	existing := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		existing[tag.Name] = true
+	}
+	var missing []models.Tag
+	for _, name := range names {
+		if !existing[name] {
+			missing = append(missing, models.Tag{WorkspaceID: workspaceID, Name: name})
+		}
+	}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&missing).Error; err != nil {
+		return nil, fmt.Errorf("failed to create tags: %w", err)
+	}
+
+	tags = tags[:0]
+	if err := tx.Where("workspace_id = ? AND name IN ?", workspaceID, names).Find(&tags).Error; err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+	return tags, nil
+}
@@ -0,0 +1,276 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TimeEntryRepository defines the interface for time entry data operations
+type TimeEntryRepository interface {
+	Start(ctx context.Context, entry *models.TimeEntry) error
+	Running(ctx context.Context, todoID uuid.UUID) (*models.TimeEntry, error)
+	Create(ctx context.Context, entry *models.TimeEntry) error
+	GetByID(ctx context.Context, todoID, id uuid.UUID) (*models.TimeEntry, error)
+	ListByTodo(ctx context.Context, todoID uuid.UUID, page, perPage int) ([]models.TimeEntry, int64, error)
+	Update(ctx context.Context, entry *models.TimeEntry) error
+	Delete(ctx context.Context, todoID, id uuid.UUID) error
+	Totals(ctx context.Context, todoIDs []uuid.UUID, now time.Time) (map[uuid.UUID]int64, error)
+	Report(ctx context.Context, filter models.TimeReportFilter) ([]models.TimeReportEntry, error)
+}
+
+// timeEntryRepository implements TimeEntryRepository
+type timeEntryRepository struct {
+	db *gorm.DB
+}
+
+// NewTimeEntryRepository creates a new time entry repository
+func NewTimeEntryRepository(db *gorm.DB) TimeEntryRepository {
+	return &timeEntryRepository{db: db}
+}
+
+// Start starts a timer for the current user. The timer the user already had
+// running is stopped first, in whichever workspace it runs.
+func (r *timeEntryRepository) Start(ctx context.Context, entry *models.TimeEntry) error {
+	db, principal, err := tenantScope(ctx, r.db, "time_entries")
+	if err != nil {
+		return err
+	}
+
+	entry.WorkspaceID = principal.WorkspaceID
+	entry.UserID = principal.UserID
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		// The running timer belongs to the user, not to the workspace of this request
+		own := tx.Session(&gorm.Session{NewDB: true})
+
+		var running []models.TimeEntry
+		if err := own.Where("user_id = ? AND ended_at IS NULL", principal.UserID).Find(&running).Error; err != nil {
+			return fmt.Errorf("failed to get running timer: %w", err)
+		}
+		for _, previous := range running {
+			previous.Finish(entry.StartedAt)
+			err := own.Model(&previous).Select("ended_at", "duration", "updated_at").Updates(&previous).Error
+			if err != nil {
+				return fmt.Errorf("failed to stop running timer: %w", err)
+			}
+		}
+
+		if err := tx.Create(entry).Error; err != nil {
+			return fmt.Errorf("failed to start timer: %w", err)
+		}
+		return nil
+	})
+}
+
+// Running retrieves the current user's running timer on a todo
+func (r *timeEntryRepository) Running(ctx context.Context, todoID uuid.UUID) (*models.TimeEntry, error) {
+	db, principal, err := tenantScope(ctx, r.db, "time_entries")
+	if err != nil {
+		return nil, err
+	}
+
+	var entry models.TimeEntry
+	err = db.Where("todo_id = ? AND user_id = ? AND ended_at IS NULL", todoID, principal.UserID).First(&entry).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("timer not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get timer: %w", err)
+	}
+	return &entry, nil
+}
+
+// Create logs a finished time entry for the current user
+func (r *timeEntryRepository) Create(ctx context.Context, entry *models.TimeEntry) error {
+	db, principal, err := tenantScope(ctx, r.db, "time_entries")
+	if err != nil {
+		return err
+	}
+
+	entry.WorkspaceID = principal.WorkspaceID
+	entry.UserID = principal.UserID
+	if err := db.Create(entry).Error; err != nil {
+		return fmt.Errorf("failed to create time entry: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a time entry on a todo
+func (r *timeEntryRepository) GetByID(ctx context.Context, todoID, id uuid.UUID) (*models.TimeEntry, error) {
+	db, _, err := tenantScope(ctx, r.db, "time_entries")
+	if err != nil {
+		return nil, err
+	}
+
+	var entry models.TimeEntry
+	if err := db.Where("id = ? AND todo_id = ?", id, todoID).First(&entry).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("time entry not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get time entry: %w", err)
+	}
+	return &entry, nil
+}
+
+// ListByTodo retrieves a page of the time entries on a todo, latest first
+func (r *timeEntryRepository) ListByTodo(ctx context.Context, todoID uuid.UUID, page, perPage int) ([]models.TimeEntry, int64, error) {
+	db, _, err := tenantScope(ctx, r.db, "time_entries")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	db = db.Where("todo_id = ?", todoID)
+
+	var total int64
+	if err := db.Model(&models.TimeEntry{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count time entries: %w", err)
+	}
+
+	var entries []models.TimeEntry
+	err = db.
+		Order("started_at DESC").
+		Offset((page - 1) * perPage).
+		Limit(perPage).
+		Find(&entries).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get time entries: %w", err)
+	}
+	return entries, total, nil
+}
+
+// Update saves the times and note of a time entry
+func (r *timeEntryRepository) Update(ctx context.Context, entry *models.TimeEntry) error {
+	db, _, err := tenantScope(ctx, r.db, "time_entries")
+	if err != nil {
+		return err
+	}
+
+	err = db.Model(entry).
+		Select("started_at", "ended_at", "duration", "note", "updated_at").
+		Updates(entry).Error
+	if err != nil {
+		return fmt.Errorf("failed to update time entry: %w", err)
+	}
+	return nil
+}
+
+// Delete deletes a time entry
+func (r *timeEntryRepository) Delete(ctx context.Context, todoID, id uuid.UUID) error {
+	db, _, err := tenantScope(ctx, r.db, "time_entries")
+	if err != nil {
+		return err
+	}
+
+	result := db.Where("id = ? AND todo_id = ?", id, todoID).Delete(&models.TimeEntry{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete time entry: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("time entry not found: %w", gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
+// Totals returns the seconds tracked on each of the given todos by all users,
+// counting running timers up to now
+func (r *timeEntryRepository) Totals(ctx context.Context, todoIDs []uuid.UUID, now time.Time) (map[uuid.UUID]int64, error) {
+	totals := make(map[uuid.UUID]int64)
+	if len(todoIDs) == 0 {
+		return totals, nil
+	}
+
+	db, _, err := tenantScope(ctx, r.db, "time_entries")
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		TodoID  uuid.UUID
+		Seconds int64
+	}
+	err = db.Model(&models.TimeEntry{}).
+		Select("todo_id, SUM(duration) AS seconds").
+		Where("todo_id IN ? AND ended_at IS NOT NULL", todoIDs).
+		Group("todo_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum time entries: %w", err)
+	}
+	for _, row := range rows {
+		totals[row.TodoID] = row.Seconds
+	}
+
+	var running []models.TimeEntry
+	if err := db.Where("todo_id IN ? AND ended_at IS NULL", todoIDs).Find(&running).Error; err != nil {
+		return nil, fmt.Errorf("failed to get running timers: %w", err)
+	}
+	for _, entry := range running {
+		totals[entry.TodoID] += entry.Elapsed(now)
+	}
+	return totals, nil
+}
+
+// Report returns the finished time entries that started within the filter's
+// days, with the list and tags of their todo. Entries on deleted todos count too.
+func (r *timeEntryRepository) Report(ctx context.Context, filter models.TimeReportFilter) ([]models.TimeReportEntry, error) {
+	db, _, err := tenantScope(ctx, r.db, "time_entries")
+	if err != nil {
+		return nil, err
+	}
+
+	query := db.Model(&models.TimeEntry{}).
+		Select("time_entries.id, time_entries.todo_id, time_entries.started_at, time_entries.duration, todos.list_id, lists.name AS list_name").
+		Joins("JOIN todos ON todos.id = time_entries.todo_id").
+		Joins("LEFT JOIN lists ON lists.id = todos.list_id").
+		Where("time_entries.ended_at IS NOT NULL AND time_entries.started_at >= ? AND time_entries.started_at < ?",
+			filter.From.UTC(), filter.To.UTC().AddDate(0, 0, 1))
+	if filter.UserID != nil {
+		query = query.Where("time_entries.user_id = ?", *filter.UserID)
+	}
+
+	var entries []models.TimeReportEntry
+	if err := query.Order("time_entries.started_at ASC").Scan(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to get time entries: %w", err)
+	}
+	if len(entries) == 0 || filter.GroupBy != models.TimeReportByTag {
+		return entries, nil
+	}
+
+	seen := make(map[uuid.UUID]bool, len(entries))
+	var todoIDs []uuid.UUID
+	for _, entry := range entries {
+		if !seen[entry.TodoID] {
+			seen[entry.TodoID] = true
+			todoIDs = append(todoIDs, entry.TodoID)
+		}
+	}
+
+	var tagRows []struct {
+		TodoID uuid.UUID
+		Name   string
+	}
+	err = db.Session(&gorm.Session{NewDB: true}).
+		Table("todo_tags").
+		Select("todo_tags.todo_id, tags.name").
+		Joins("JOIN tags ON tags.id = todo_tags.tag_id").
+		Where("todo_tags.todo_id IN ?", todoIDs).
+		Order("tags.name ASC").
+		Scan(&tagRows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+
+	tags := make(map[uuid.UUID][]string)
+	for _, row := range tagRows {
+		tags[row.TodoID] = append(tags[row.TodoID], row.Name)
+	}
+	for i := range entries {
+		entries[i].Tags = tags[entries[i].TodoID]
+	}
+	return entries, nil
+}
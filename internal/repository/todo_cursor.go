@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GetPage retrieves up to limit todos matching the filter that follow the
+// position in the listing's order, or precede it for a position with Before
+// set. Without a position it returns the first page. Unlike GetAll, pages
+// neither skip nor repeat todos when todos are added or removed while paging.
+func (r *todoRepository) GetPage(ctx context.Context, filter models.TodoFilter, position *models.TodoCursor, limit int) (*models.TodoPage, error) {
+	scoped, _, err := r.scoped(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	db := applyTodoFilter(scoped, filter)
+	keys := todoSortKeys(filter.Sort)
+	sort := models.FormatTodoSort(filter.Sort)
+
+	var total int64
+	if err := db.Model(&models.Todo{}).Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count todos: %w", err)
+	}
+
+	query := db
+	before := false
+	if position != nil {
+		values, err := decodeSortValues(keys, position.Values)
+		if err != nil {
+			return nil, err
+		}
+		condition, args := keysetCondition(keys, values, position.Before, position.Inclusive)
+		query = query.Where(condition, args...)
+		before = position.Before
+	}
+
+	// One todo more than asked for tells whether the listing goes on
+	var todos []models.Todo
+	err = query.
+		Preload("Tags").
+		Order(todoOrder(keys, before)).
+		Limit(limit + 1).
+		Find(&todos).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get todos: %w", err)
+	}
+
+	more := len(todos) > limit
+	if more {
+		todos = todos[:limit]
+	}
+	if before {
+		slices.Reverse(todos)
+	}
+
+	page := &models.TodoPage{Todos: todos, Total: total}
+	if len(todos) == 0 {
+		// Past the end in one direction, the position itself leads back, its todo included
+		if position != nil {
+			back := &models.TodoCursor{Sort: sort, Values: position.Values, Before: !before, Inclusive: true}
+			if before {
+				page.Next = back
+			} else {
+				page.Prev = back
+			}
+		}
+		return page, nil
+	}
+
+	if (more && !before) || (position != nil && before) {
+		values, err := sortValues(scoped, keys, todos[len(todos)-1].ID)
+		if err != nil {
+			return nil, err
+		}
+		page.Next = &models.TodoCursor{Sort: sort, Values: values}
+	}
+	if (more && before) || (position != nil && !before) {
+		values, err := sortValues(scoped, keys, todos[0].ID)
+		if err != nil {
+			return nil, err
+		}
+		page.Prev = &models.TodoCursor{Sort: sort, Values: values, Before: true}
+	}
+	return page, nil
+}
+
+// keysetCondition matches the todos that sort after the given key values, or
+// before them when before is set, and the todo at them when inclusive is set.
+// A todo sorts after the position when it equals the position on the first keys
+// and sorts after it on the next one.
+func keysetCondition(keys []todoSortKey, values []interface{}, before, inclusive bool) (string, []interface{}) {
+	var alternatives []string
+	var args []interface{}
+	equal := func(n int) []string {
+		var terms []string
+		for j := 0; j < n; j++ {
+			if values[j] == nil {
+				terms = append(terms, keys[j].expr+" IS NULL")
+			} else {
+				terms = append(terms, keys[j].expr+" = ?")
+				args = append(args, values[j])
+			}
+		}
+		return terms
+	}
+
+	for i, key := range keys {
+		// Nothing sorts strictly before or after NULL, as NULLs are grouped by the IS NULL key
+		if values[i] == nil {
+			continue
+		}
+
+		terms := equal(i)
+		op := " > ?"
+		if key.desc != before {
+			op = " < ?"
+		}
+		terms = append(terms, key.expr+op)
+		args = append(args, values[i])
+		alternatives = append(alternatives, "("+strings.Join(terms, " AND ")+")")
+	}
+	if inclusive {
+		alternatives = append(alternatives, "("+strings.Join(equal(len(keys)), " AND ")+")")
+	}
+
+	if len(alternatives) == 0 {
+		return "1 = 0", nil
+	}
+	return "(" + strings.Join(alternatives, " OR ") + ")", args
+}
+
+// sortValues reads the sort key values of a todo as the database compares them
+func sortValues(db *gorm.DB, keys []todoSortKey, id uuid.UUID) ([]interface{}, error) {
+	exprs := make([]string, len(keys))
+	dests := make([]interface{}, len(keys))
+	for i, key := range keys {
+		exprs[i] = key.expr
+		switch key.kind {
+		case sortTime:
+			dests[i] = &sql.NullTime{}
+		case sortInt:
+			dests[i] = &sql.NullInt64{}
+		case sortString:
+			dests[i] = &sql.NullString{}
+		case sortBool:
+			dests[i] = &sql.NullBool{}
+		}
+	}
+
+	row := db.Table("todos").Select(strings.Join(exprs, ", ")).Where("todos.id = ?", id).Row()
+	if err := row.Scan(dests...); err != nil {
+		return nil, fmt.Errorf("failed to read sort values: %w", err)
+	}
+
+	values := make([]interface{}, len(keys))
+	for i, dest := range dests {
+		switch v := dest.(type) {
+		case *sql.NullTime:
+			if v.Valid {
+				values[i] = v.Time
+			}
+		case *sql.NullInt64:
+			if v.Valid {
+				values[i] = v.Int64
+			}
+		case *sql.NullString:
+			if v.Valid {
+				values[i] = v.String
+			}
+		case *sql.NullBool:
+			if v.Valid {
+				values[i] = v.Bool
+			}
+		}
+	}
+	return values, nil
+}
+
+// decodeSortValues converts the key values of a position, as read back from
+// JSON, to the types of the sort keys
+func decodeSortValues(keys []todoSortKey, raw []interface{}) ([]interface{}, error) {
+	if len(raw) != len(keys) {
+		return nil, ErrInvalidCursor
+	}
+
+	values := make([]interface{}, len(keys))
+	for i, key := range keys {
+		if raw[i] == nil {
+			continue
+		}
+		ok := false
+		switch key.kind {
+		case sortTime:
+			var s string
+			if s, ok = raw[i].(string); ok {
+				t, err := time.Parse(time.RFC3339Nano, s)
+				values[i], ok = t, err == nil
+			}
+		case sortInt:
+			var f float64
+			if f, ok = raw[i].(float64); ok {
+				values[i] = int64(f)
+			}
+		case sortString:
+			values[i], ok = raw[i].(string)
+		case sortBool:
+			values[i], ok = raw[i].(bool)
+		}
+		if !ok {
+			return nil, ErrInvalidCursor
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,270 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/google/uuid"
+)
+
+// pagedTodos seeds a tenant with todos whose due dates include ties and gaps,
+// and returns the tenant's todos in the order of an ascending due date sort:
+// by due date with todos without one last, then by creation time
+func pagedTodos(t *testing.T, repo TodoRepository, a tenant) []models.Todo {
+	t.Helper()
+	day := func(d int) *time.Time {
+		due := time.Date(2026, 11, d, 9, 0, 0, 0, time.UTC)
+		return &due
+	}
+	seeds := []*time.Time{day(3), nil, day(1), day(3), nil, day(2), day(1), nil, day(5)}
+
+	todos := []models.Todo{*a.todo}
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, due := range seeds {
+		todos = append(todos, *createPagedTodo(t, repo, a.ctx, due, created.Add(time.Duration(i)*time.Minute)))
+	}
+
+	sort.SliceStable(todos, func(i, j int) bool {
+		a, b := todos[i], todos[j]
+		if (a.DueDate == nil) != (b.DueDate == nil) {
+			return b.DueDate == nil
+		}
+		if a.DueDate != nil && !a.DueDate.Equal(*b.DueDate) {
+			return a.DueDate.Before(*b.DueDate)
+		}
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+	return todos
+}
+
+// createPagedTodo stores a todo with the given due date and creation time
+func createPagedTodo(t *testing.T, repo TodoRepository, ctx context.Context, due *time.Time, created time.Time) *models.Todo {
+	t.Helper()
+	todo := &models.Todo{
+		ID:        uuid.New(),
+		Title:     "paged todo",
+		Priority:  models.PriorityMedium,
+		Status:    models.StatusBacklog,
+		Position:  "a",
+		DueDate:   due,
+		CreatedAt: created,
+	}
+	if err := repo.Create(ctx, todo); err != nil {
+		t.Fatalf("failed to create todo: %v", err)
+	}
+	return todo
+}
+
+// roundTrip passes a position through JSON the way a cursor carries it to the client and back
+func roundTrip(t *testing.T, position *models.TodoCursor) *models.TodoCursor {
+	t.Helper()
+	if position == nil {
+		return nil
+	}
+	encoded, err := json.Marshal(position)
+	if err != nil {
+		t.Fatalf("failed to encode cursor: %v", err)
+	}
+	var decoded models.TodoCursor
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("failed to decode cursor: %v", err)
+	}
+	return &decoded
+}
+
+// getPage reads the page at a position, passing the position through JSON first
+func getPage(t *testing.T, repo TodoRepository, ctx context.Context, filter models.TodoFilter, position *models.TodoCursor, limit int) *models.TodoPage {
+	t.Helper()
+	page, err := repo.GetPage(ctx, filter, roundTrip(t, position), limit)
+	if err != nil {
+		t.Fatalf("GetPage: %v", err)
+	}
+	return page
+}
+
+// todoIDs lists the IDs of todos
+func todoIDs(todos []models.Todo) []uuid.UUID {
+	ids := make([]uuid.UUID, len(todos))
+	for i, todo := range todos {
+		ids[i] = todo.ID
+	}
+	return ids
+}
+
+// assertOrder fails the test unless got lists the todos of want in the same order
+func assertOrder(t *testing.T, got, want []uuid.UUID) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d todos, want %d:\ngot  %v\nwant %v", len(got), len(want), got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("todo %d is %s, want %s:\ngot  %v\nwant %v", i, got[i], want[i], got, want)
+		}
+	}
+}
+
+func TestTodoRepositoryGetPage(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewTodoRepository(db)
+	a := newTenant(t, db, repo, "alpha")
+	todos := pagedTodos(t, repo, a)
+	want := todoIDs(todos)
+	byDueDate := models.TodoFilter{Sort: []models.TodoSort{{Field: models.TodoSortDueDate}}}
+
+	t.Run("Forward", func(t *testing.T) {
+		var got []uuid.UUID
+		var position *models.TodoCursor
+		for pages := 0; ; pages++ {
+			page := getPage(t, repo, a.ctx, byDueDate, position, 3)
+			if page.Total != int64(len(want)) {
+				t.Fatalf("total = %d, want %d", page.Total, len(want))
+			}
+			if (page.Prev == nil) != (pages == 0) {
+				t.Fatalf("page %d has previous cursor %v", pages, page.Prev)
+			}
+			got = append(got, todoIDs(page.Todos)...)
+			if page.Next == nil {
+				break
+			}
+			position = page.Next
+		}
+		assertOrder(t, got, want)
+	})
+
+	t.Run("Backward", func(t *testing.T) {
+		// Walk to the last page, then back to the first
+		page := getPage(t, repo, a.ctx, byDueDate, nil, 4)
+		for page.Next != nil {
+			page = getPage(t, repo, a.ctx, byDueDate, page.Next, 4)
+		}
+
+		got := todoIDs(page.Todos)
+		for page.Prev != nil {
+			page = getPage(t, repo, a.ctx, byDueDate, page.Prev, 4)
+			got = append(todoIDs(page.Todos), got...)
+		}
+		assertOrder(t, got, want)
+		if page.Next == nil {
+			t.Error("the first page reached backwards has no next cursor")
+		}
+	})
+
+	t.Run("Descending", func(t *testing.T) {
+		filter := models.TodoFilter{Sort: []models.TodoSort{{Field: models.TodoSortDueDate, Desc: true}}}
+		var got []uuid.UUID
+		var position *models.TodoCursor
+		for {
+			page := getPage(t, repo, a.ctx, filter, position, 2)
+			got = append(got, todoIDs(page.Todos)...)
+			if page.Next == nil {
+				break
+			}
+			position = page.Next
+		}
+
+		// Todos without a due date stay last, and ties keep the creation order
+		desc := slices.Clone(todos)
+		sort.SliceStable(desc, func(i, j int) bool {
+			a, b := desc[i], desc[j]
+			if (a.DueDate == nil) != (b.DueDate == nil) {
+				return b.DueDate == nil
+			}
+			if a.DueDate != nil && !a.DueDate.Equal(*b.DueDate) {
+				return a.DueDate.After(*b.DueDate)
+			}
+			return a.CreatedAt.Before(b.CreatedAt)
+		})
+		assertOrder(t, got, todoIDs(desc))
+	})
+}
+
+func TestTodoRepositoryGetPageInsertBetweenPages(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewTodoRepository(db)
+	a := newTenant(t, db, repo, "alpha")
+	todos := pagedTodos(t, repo, a)
+	byDueDate := models.TodoFilter{Sort: []models.TodoSort{{Field: models.TodoSortDueDate}}}
+
+	first := getPage(t, repo, a.ctx, byDueDate, nil, 4)
+	assertOrder(t, todoIDs(first.Todos), todoIDs(todos[:4]))
+
+	// One todo lands among those already read and one among those still to come,
+	// tied on due date with a todo of each part
+	behind := createPagedTodo(t, repo, a.ctx, todos[1].DueDate, todos[1].CreatedAt.Add(time.Second))
+	ahead := createPagedTodo(t, repo, a.ctx, todos[5].DueDate, todos[5].CreatedAt.Add(time.Second))
+
+	got := todoIDs(first.Todos)
+	for position := first.Next; position != nil; {
+		page := getPage(t, repo, a.ctx, byDueDate, position, 4)
+		got = append(got, todoIDs(page.Todos)...)
+		position = page.Next
+	}
+
+	want := todoIDs(todos[:6])
+	want = append(want, ahead.ID)
+	want = append(want, todoIDs(todos[6:])...)
+	assertOrder(t, got, want)
+	for _, id := range got {
+		if id == behind.ID {
+			t.Fatalf("todo %s inserted before the cursor was listed after it", id)
+		}
+	}
+}
+
+func TestTodoRepositoryGetPagePastTheEnd(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewTodoRepository(db)
+	a := newTenant(t, db, repo, "alpha")
+	todos := pagedTodos(t, repo, a)
+	byDueDate := models.TodoFilter{Sort: []models.TodoSort{{Field: models.TodoSortDueDate}}}
+
+	first := getPage(t, repo, a.ctx, byDueDate, nil, 4)
+	if first.Next == nil {
+		t.Fatal("first page has no next cursor")
+	}
+
+	// Everything after the first page goes away before the next one is read
+	for _, todo := range todos[4:] {
+		if err := repo.Delete(a.ctx, todo.ID, models.SubtaskDeleteOrphan); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+	}
+
+	empty := getPage(t, repo, a.ctx, byDueDate, first.Next, 4)
+	if len(empty.Todos) != 0 || empty.Next != nil {
+		t.Fatalf("page past the end has %d todos and next cursor %v", len(empty.Todos), empty.Next)
+	}
+	if empty.Prev == nil {
+		t.Fatal("page past the end has no cursor leading back")
+	}
+
+	back := getPage(t, repo, a.ctx, byDueDate, empty.Prev, 4)
+	assertOrder(t, todoIDs(back.Todos), todoIDs(todos[:4]))
+	if back.Prev != nil {
+		t.Errorf("the first page reached backwards has previous cursor %v", back.Prev)
+	}
+}
+
+func TestTodoRepositoryGetPageInvalidCursor(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewTodoRepository(db)
+	a := newTenant(t, db, repo, "alpha")
+	byDueDate := models.TodoFilter{Sort: []models.TodoSort{{Field: models.TodoSortDueDate}}}
+
+	for name, values := range map[string][]interface{}{
+		"too few values": {false},
+		"wrong type":     {"no", "2026-11-01T09:00:00Z", "2026-01-01T00:00:00Z", uuid.NewString()},
+		"invalid time":   {false, "tomorrow", "2026-01-01T00:00:00Z", uuid.NewString()},
+	} {
+		position := &models.TodoCursor{Sort: "due_date", Values: values}
+		if _, err := repo.GetPage(a.ctx, byDueDate, roundTrip(t, position), 4); err != ErrInvalidCursor {
+			t.Errorf("%s: got %v, want %v", name, err, ErrInvalidCursor)
+		}
+	}
+}
@@ -3,20 +3,41 @@ package repository
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/1cbyc/go-todo-api/internal/auth"
 	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/1cbyc/go-todo-api/internal/rank"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-// TodoRepository defines the interface for todo data operations
+// TodoRepository defines the interface for todo data operations.
+// PositionScopes, Rebalance and PurgeExpired run as background jobs and are
+// deliberately not tenant scoped.
 type TodoRepository interface {
 	Create(ctx context.Context, todo *models.Todo) error
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Todo, error)
-	GetAll(ctx context.Context, page, perPage int) ([]models.Todo, int64, error)
+	GetAll(ctx context.Context, filter models.TodoFilter, page, perPage int) ([]models.Todo, int64, error)
+	GetPage(ctx context.Context, filter models.TodoFilter, position *models.TodoCursor, limit int) (*models.TodoPage, error)
+	Search(ctx context.Context, terms []string, filter models.TodoFilter, page, perPage int) ([]models.TodoSearchHit, int64, error)
 	Update(ctx context.Context, todo *models.Todo) error
-	Delete(ctx context.Context, id uuid.UUID) error
-	Toggle(ctx context.Context, id uuid.UUID) error
+	Delete(ctx context.Context, id uuid.UUID, mode models.SubtaskDeleteMode) error
+	GetDescendants(ctx context.Context, id uuid.UUID) ([]models.Todo, error)
+	ChildCounts(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]models.Progress, error)
+	StopSeries(ctx context.Context, seriesID uuid.UUID) error
+	LastPosition(ctx context.Context, listID *uuid.UUID) (string, error)
+	AdjacentPosition(ctx context.Context, listID *uuid.UUID, position string, excludeID uuid.UUID, next bool) (string, error)
+	SetPosition(ctx context.Context, id uuid.UUID, position string) error
+	Trash(ctx context.Context, page, perPage int) ([]models.Todo, int64, error)
+	Restore(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error)
+	Purge(ctx context.Context, id uuid.UUID) error
+
+	PositionScopes(ctx context.Context, maxLength int) ([]models.PositionScope, error)
+	Rebalance(ctx context.Context, scope models.PositionScope) error
+	PurgeExpired(ctx context.Context, before time.Time, limit int) (int, error)
 }
 
 // todoRepository implements TodoRepository
@@ -29,15 +50,37 @@ func NewTodoRepository(db *gorm.DB) TodoRepository {
 	return &todoRepository{db: db}
 }
 
-// Create creates a new todo
+// scoped returns a query restricted to the todos of the current workspace
+func (r *todoRepository) scoped(ctx context.Context) (*gorm.DB, *auth.Principal, error) {
+	return tenantScope(ctx, r.db, "todos")
+}
+
+// Create creates a new todo in the current workspace
 func (r *todoRepository) Create(ctx context.Context, todo *models.Todo) error {
-	return r.db.WithContext(ctx).Create(todo).Error
+	db, principal, err := r.scoped(ctx)
+	if err != nil {
+		return err
+	}
+	todo.WorkspaceID = principal.WorkspaceID
+	todo.UserID = principal.UserID
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(todo).Error; err != nil {
+			return err
+		}
+		return replaceTodoTags(tx.Session(&gorm.Session{NewDB: true}), todo)
+	})
 }
 
 // GetByID retrieves a todo by ID
 func (r *todoRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Todo, error) {
+	db, _, err := r.scoped(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	var todo models.Todo
-	err := r.db.WithContext(ctx).Where("id = ?", id).First(&todo).Error
+	err = db.Preload("Tags").Where("id = ?", id).First(&todo).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, fmt.Errorf("todo not found: %w", err)
@@ -47,13 +90,20 @@ func (r *todoRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Tod
 	return &todo, nil
 }
 
-// GetAll retrieves all todos with pagination
-func (r *todoRepository) GetAll(ctx context.Context, page, perPage int) ([]models.Todo, int64, error) {
+// GetAll retrieves all todos matching the filter with pagination
+func (r *todoRepository) GetAll(ctx context.Context, filter models.TodoFilter, page, perPage int) ([]models.Todo, int64, error) {
 	var todos []models.Todo
 	var total int64
 
+	db, _, err := r.scoped(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	db = applyTodoFilter(db, filter)
+
 	// Get total count
-	if err := r.db.WithContext(ctx).Model(&models.Todo{}).Count(&total).Error; err != nil {
+	if err := db.Model(&models.Todo{}).Count(&total).Error; err != nil {
 		return nil, 0, fmt.Errorf("failed to count todos: %w", err)
 	}
 
@@ -61,8 +111,9 @@ func (r *todoRepository) GetAll(ctx context.Context, page, perPage int) ([]model
 	offset := (page - 1) * perPage
 
 	// Get todos with pagination
-	err := r.db.WithContext(ctx).
-		Order("created_at DESC").
+	err = db.
+		Preload("Tags").
+		Order(todoOrder(todoSortKeys(filter.Sort), false)).
 		Offset(offset).
 		Limit(perPage).
 		Find(&todos).Error
@@ -74,42 +125,565 @@ func (r *todoRepository) GetAll(ctx context.Context, page, perPage int) ([]model
 	return todos, total, nil
 }
 
+// sortKind is the type of the values of a sort key
+type sortKind int
+
+const (
+	sortTime sortKind = iota
+	sortInt
+	sortString
+	sortBool
+)
+
+// todoSortColumn is the expression a sortable field sorts by
+type todoSortColumn struct {
+	expr     string
+	kind     sortKind
+	nullable bool
+}
+
+// todoSortColumns maps the sortable fields to the expressions they sort by.
+// Priorities sort by rank rather than alphabetically, from low to urgent.
+var todoSortColumns = map[models.TodoSortField]todoSortColumn{
+	models.TodoSortCreated:   {expr: "todos.created_at", kind: sortTime},
+	models.TodoSortUpdated:   {expr: "todos.updated_at", kind: sortTime},
+	models.TodoSortDueDate:   {expr: "todos.due_date", kind: sortTime, nullable: true},
+	models.TodoSortPriority:  {expr: "CASE todos.priority WHEN 'low' THEN 1 WHEN 'medium' THEN 2 WHEN 'high' THEN 3 WHEN 'urgent' THEN 4 END", kind: sortInt},
+	models.TodoSortTitle:     {expr: "LOWER(todos.title)", kind: sortString},
+	models.TodoSortStatus:    {expr: "todos.status", kind: sortString},
+	models.TodoSortCompleted: {expr: "todos.completed", kind: sortBool},
+	models.TodoSortPosition:  {expr: "todos.position", kind: sortString},
+}
+
+// todoSortKey is one expression of the ORDER BY clause of a todo listing
+type todoSortKey struct {
+	expr string
+	kind sortKind
+	desc bool
+}
+
+// todoSortKeys expands the sort order of a todo listing into the keys it is
+// ordered by. Missing values sort last in either direction; NULLS LAST is spelled
+// out with an IS NULL key since SQLite only supports it from 3.30 and sorts NULLs
+// first by default. Ties are broken by creation time and ID, so every todo has
+// its own place in the order and pages do not overlap.
+func todoSortKeys(sort []models.TodoSort) []todoSortKey {
+	if len(sort) == 0 {
+		sort = models.DefaultTodoSort
+	}
+
+	var keys []todoSortKey
+	sortsCreated := false
+	for _, key := range sort {
+		column := todoSortColumns[key.Field]
+		if column.nullable {
+			keys = append(keys, todoSortKey{expr: "(" + column.expr + " IS NULL)", kind: sortBool})
+		}
+		keys = append(keys, todoSortKey{expr: column.expr, kind: column.kind, desc: key.Desc})
+		sortsCreated = sortsCreated || key.Field == models.TodoSortCreated
+	}
+	if !sortsCreated {
+		keys = append(keys, todoSortKey{expr: "todos.created_at", kind: sortTime})
+	}
+	return append(keys, todoSortKey{expr: "todos.id", kind: sortString})
+}
+
+// todoOrder builds the ORDER BY clause for the sort keys, or for the opposite
+// order when reverse is set
+func todoOrder(keys []todoSortKey, reverse bool) string {
+	order := make([]string, len(keys))
+	for i, key := range keys {
+		if key.desc != reverse {
+			order[i] = key.expr + " DESC"
+		} else {
+			order[i] = key.expr + " ASC"
+		}
+	}
+	return strings.Join(order, ", ")
+}
+
+// likeEscaper escapes the wildcards of LIKE patterns
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// applyTodoFilter adds the filter conditions to a todo query. The result is a new
+// session so it can be reused for both the count and the page query.
+func applyTodoFilter(db *gorm.DB, filter models.TodoFilter) *gorm.DB {
+	if filter.ListID != nil {
+		db = db.Where("todos.list_id = ?", *filter.ListID)
+	}
+	if filter.ParentID != nil {
+		db = db.Where("todos.parent_id = ?", *filter.ParentID)
+	}
+	if len(filter.Tags) > 0 {
+		tagged := db.Session(&gorm.Session{NewDB: true}).
+			Table("todo_tags").
+			Select("todo_tags.todo_id").
+			Joins("JOIN tags ON tags.id = todo_tags.tag_id").
+			Where("tags.workspace_id = todos.workspace_id AND tags.name IN ?", filter.Tags)
+		if filter.TagMode == models.TagModeAll {
+			tagged = tagged.Group("todo_tags.todo_id").Having("COUNT(DISTINCT todo_tags.tag_id) = ?", len(filter.Tags))
+		}
+		db = db.Where("todos.id IN (?)", tagged)
+	}
+	if filter.Completed != nil {
+		db = db.Where("todos.completed = ?", *filter.Completed)
+	}
+	if len(filter.Priorities) > 0 {
+		db = db.Where("todos.priority IN ?", filter.Priorities)
+	}
+	if filter.DueBefore != nil {
+		db = db.Where("todos.due_date < ?", *filter.DueBefore)
+	}
+	if filter.DueAfter != nil {
+		db = db.Where("todos.due_date >= ?", *filter.DueAfter)
+	}
+	if filter.CreatedBefore != nil {
+		db = db.Where("todos.created_at < ?", *filter.CreatedBefore)
+	}
+	if filter.CreatedAfter != nil {
+		db = db.Where("todos.created_at >= ?", *filter.CreatedAfter)
+	}
+	if filter.HasDueDate != nil {
+		if *filter.HasDueDate {
+			db = db.Where("todos.due_date IS NOT NULL")
+		} else {
+			db = db.Where("todos.due_date IS NULL")
+		}
+	}
+	if filter.Overdue {
+		db = db.Where("todos.due_date < ? AND todos.completed = ?", time.Now().UTC(), false)
+	}
+	for _, text := range filter.Text {
+		pattern := "%" + likeEscaper.Replace(strings.ToLower(text)) + "%"
+		db = db.Where(`(LOWER(todos.title) LIKE ? ESCAPE '\' OR LOWER(todos.description) LIKE ? ESCAPE '\')`, pattern, pattern)
+	}
+	return db.Session(&gorm.Session{})
+}
+
 // Update updates a todo
 func (r *todoRepository) Update(ctx context.Context, todo *models.Todo) error {
-	result := r.db.WithContext(ctx).Save(todo)
-	if result.Error != nil {
-		return fmt.Errorf("failed to update todo: %w", result.Error)
+	db, principal, err := r.scoped(ctx)
+	if err != nil {
+		return err
 	}
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("todo not found")
+	todo.WorkspaceID = principal.WorkspaceID
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		// Updates is used instead of Save so that a todo outside the scope is never upserted
+		result := tx.Model(todo).Select("*").Omit("created_at", clause.Associations).Updates(todo)
+		if result.Error != nil {
+			return fmt.Errorf("failed to update todo: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("todo not found: %w", gorm.ErrRecordNotFound)
+		}
+		return replaceTodoTags(tx.Session(&gorm.Session{NewDB: true}), todo)
+	})
+}
+
+// Delete deletes a todo by ID. Its subtasks are either deleted with it, down to
+// the last descendant, or kept as top-level todos, depending on mode. The comments
+// of every deleted todo are soft-deleted along with it.
+func (r *todoRepository) Delete(ctx context.Context, id uuid.UUID, mode models.SubtaskDeleteMode) error {
+	db, principal, err := r.scoped(ctx)
+	if err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		ids := []uuid.UUID{id}
+		if mode == models.SubtaskDeleteCascade {
+			descendants, err := descendantIDs(tx, id)
+			if err != nil {
+				return err
+			}
+			ids = append(ids, descendants...)
+		} else {
+			err := tx.Model(&models.Todo{}).Where("parent_id = ?", id).Update("parent_id", nil).Error
+			if err != nil {
+				return fmt.Errorf("failed to detach subtasks: %w", err)
+			}
+		}
+
+		result := tx.Where("id IN ?", ids).Delete(&models.Todo{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete todo: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("todo not found: %w", gorm.ErrRecordNotFound)
+		}
+
+		// Comments go with their todo
+		err := tx.Session(&gorm.Session{NewDB: true}).
+			Where("workspace_id = ? AND todo_id IN ?", principal.WorkspaceID, ids).
+			Delete(&models.Comment{}).Error
+		if err != nil {
+			return fmt.Errorf("failed to delete comments: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetDescendants retrieves every subtask below a todo, level by level
+func (r *todoRepository) GetDescendants(ctx context.Context, id uuid.UUID) ([]models.Todo, error) {
+	db, _, err := r.scoped(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var descendants []models.Todo
+	parents := []uuid.UUID{id}
+	for len(parents) > 0 {
+		var level []models.Todo
+		err := db.Preload("Tags").Where("parent_id IN ?", parents).Order("created_at ASC").Find(&level).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to get subtasks: %w", err)
+		}
+		parents = parents[:0]
+		for _, todo := range level {
+			parents = append(parents, todo.ID)
+		}
+		descendants = append(descendants, level...)
+	}
+	return descendants, nil
+}
+
+// descendantIDs returns the IDs of every subtask below a todo. db must carry the tenant scope.
+func descendantIDs(db *gorm.DB, id uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	parents := []uuid.UUID{id}
+	for len(parents) > 0 {
+		var level []uuid.UUID
+		if err := db.Model(&models.Todo{}).Where("parent_id IN ?", parents).Pluck("id", &level).Error; err != nil {
+			return nil, fmt.Errorf("failed to get subtasks: %w", err)
+		}
+		ids = append(ids, level...)
+		parents = level
+	}
+	return ids, nil
+}
+
+// Trash retrieves a page of the deleted todos of the current workspace, most recently deleted first
+func (r *todoRepository) Trash(ctx context.Context, page, perPage int) ([]models.Todo, int64, error) {
+	db, _, err := r.scoped(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	db = db.Unscoped().Where("todos.deleted_at IS NOT NULL").Session(&gorm.Session{})
+
+	var total int64
+	if err := db.Model(&models.Todo{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count deleted todos: %w", err)
+	}
+
+	var todos []models.Todo
+	err = db.
+		Preload("Tags").
+		Order("todos.deleted_at DESC").
+		Offset((page - 1) * perPage).
+		Limit(perPage).
+		Find(&todos).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get deleted todos: %w", err)
+	}
+	return todos, total, nil
+}
+
+// Restore takes a deleted todo out of the trash together with the subtasks
+// and comments that were deleted along with it, and returns the IDs of the
+// restored todos, the given one first
+func (r *todoRepository) Restore(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	db, principal, err := r.scoped(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []uuid.UUID
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var todo models.Todo
+		if err := tx.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).First(&todo).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fmt.Errorf("todo not found: %w", err)
+			}
+			return fmt.Errorf("failed to get todo: %w", err)
+		}
+		deletedAt := todo.DeletedAt.Time
+
+		// A cascading delete marks the whole subtree in one statement, so its subtasks share the timestamp
+		ids = []uuid.UUID{id}
+		for parents := ids; len(parents) > 0; {
+			var level []uuid.UUID
+			err := tx.Unscoped().Model(&models.Todo{}).
+				Where("parent_id IN ? AND deleted_at = ?", parents, deletedAt).
+				Pluck("id", &level).Error
+			if err != nil {
+				return fmt.Errorf("failed to get subtasks: %w", err)
+			}
+			ids = append(ids, level...)
+			parents = level
+		}
+
+		if err := tx.Unscoped().Model(&models.Todo{}).Where("id IN ?", ids).Update("deleted_at", nil).Error; err != nil {
+			return fmt.Errorf("failed to restore todo: %w", err)
+		}
+
+		// Comments deleted before their todo was stay deleted
+		err := tx.Session(&gorm.Session{NewDB: true}).Unscoped().
+			Model(&models.Comment{}).
+			Where("workspace_id = ? AND todo_id IN ? AND deleted_at >= ?", principal.WorkspaceID, ids, deletedAt).
+			Update("deleted_at", nil).Error
+		if err != nil {
+			return fmt.Errorf("failed to restore comments: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// Purge permanently deletes a todo from the trash together with its deleted
+// subtasks and everything attached to them. Attachment files are left to the
+// attachment cleanup job.
+func (r *todoRepository) Purge(ctx context.Context, id uuid.UUID) error {
+	db, _, err := r.scoped(ctx)
+	if err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		trashed := tx.Unscoped().Where("todos.deleted_at IS NOT NULL").Session(&gorm.Session{})
+
+		var count int64
+		if err := trashed.Model(&models.Todo{}).Where("id = ?", id).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to get todo: %w", err)
+		}
+		if count == 0 {
+			return fmt.Errorf("todo not found: %w", gorm.ErrRecordNotFound)
+		}
+
+		descendants, err := descendantIDs(trashed, id)
+		if err != nil {
+			return err
+		}
+		return purgeTodos(tx.Session(&gorm.Session{NewDB: true}), append([]uuid.UUID{id}, descendants...))
+	})
+}
+
+// PurgeExpired permanently deletes up to limit todos of any workspace that
+// were deleted before the given time, and reports how many it deleted
+func (r *todoRepository) PurgeExpired(ctx context.Context, before time.Time, limit int) (int, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).Unscoped().
+		Model(&models.Todo{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Order("deleted_at ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to find expired todos: %w", err)
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return purgeTodos(tx, ids)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
+
+// purgeTodos hard-deletes todos and the rows that belong to them. db must not carry a scope.
+func purgeTodos(db *gorm.DB, ids []uuid.UUID) error {
+	// Dependencies go in both directions
+	if err := db.Where("todo_id IN ? OR blocked_by_id IN ?", ids, ids).Delete(&models.TodoDependency{}).Error; err != nil {
+		return fmt.Errorf("failed to purge dependencies: %w", err)
+	}
+	for _, model := range []interface{}{&models.TodoTag{}, &models.Reminder{}, &models.Comment{}, &models.TimeEntry{}, &models.TodoRevision{}} {
+		if err := db.Unscoped().Where("todo_id IN ?", ids).Delete(model).Error; err != nil {
+			return fmt.Errorf("failed to purge todo data: %w", err)
+		}
+	}
+
+	// Todos deleted separately from their parent may still point at it
+	err := db.Model(&models.Todo{}).Unscoped().Where("parent_id IN ?", ids).Update("parent_id", nil).Error
+	if err != nil {
+		return fmt.Errorf("failed to detach subtasks: %w", err)
+	}
+
+	if err := db.Unscoped().Where("id IN ?", ids).Delete(&models.Todo{}).Error; err != nil {
+		return fmt.Errorf("failed to purge todos: %w", err)
 	}
 	return nil
 }
 
-// Delete deletes a todo by ID
-func (r *todoRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	result := r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.Todo{})
-	if result.Error != nil {
-		return fmt.Errorf("failed to delete todo: %w", result.Error)
+// childCountRow is the shape of one row of the subtask counts query
+type childCountRow struct {
+	ParentID  uuid.UUID
+	Total     int64
+	Completed int64
+}
+
+// ChildCounts returns how many direct subtasks each of the given todos has and how many are completed
+func (r *todoRepository) ChildCounts(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]models.Progress, error) {
+	counts := make(map[uuid.UUID]models.Progress, len(ids))
+	if len(ids) == 0 {
+		return counts, nil
 	}
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("todo not found")
+
+	db, _, err := r.scoped(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []childCountRow
+	err = db.Model(&models.Todo{}).
+		Select("parent_id, COUNT(*) AS total, SUM(CASE WHEN completed THEN 1 ELSE 0 END) AS completed").
+		Where("parent_id IN ?", ids).
+		Group("parent_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count subtasks: %w", err)
+	}
+
+	for _, row := range rows {
+		counts[row.ParentID] = models.Progress{Total: row.Total, Completed: row.Completed}
+	}
+	return counts, nil
+}
+
+// StopSeries removes the recurrence rule from every occurrence of a series,
+// so completing them no longer creates new occurrences
+func (r *todoRepository) StopSeries(ctx context.Context, seriesID uuid.UUID) error {
+	db, _, err := r.scoped(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = db.Model(&models.Todo{}).
+		Where("series_id = ?", seriesID).
+		Updates(map[string]interface{}{"recurrence": "", "recurrence_mode": ""}).Error
+	if err != nil {
+		return fmt.Errorf("failed to stop series: %w", err)
 	}
 	return nil
 }
 
-// Toggle toggles the completed status of a todo
-func (r *todoRepository) Toggle(ctx context.Context, id uuid.UUID) error {
-	result := r.db.WithContext(ctx).
-		Model(&models.Todo{}).
-		Where("id = ?", id).
-		Update("completed", gorm.Expr("NOT completed"))
+// inList restricts a todo query to the todos of a list, or to those outside any list when listID is nil
+func inList(db *gorm.DB, listID *uuid.UUID) *gorm.DB {
+	if listID == nil {
+		return db.Where("list_id IS NULL")
+	}
+	return db.Where("list_id = ?", *listID)
+}
+
+// LastPosition returns the highest position in a list, or "" when it has no positioned todos
+func (r *todoRepository) LastPosition(ctx context.Context, listID *uuid.UUID) (string, error) {
+	db, _, err := r.scoped(ctx)
+	if err != nil {
+		return "", err
+	}
 
+	var positions []string
+	err = inList(db.Model(&models.Todo{}), listID).
+		Order("position DESC").
+		Limit(1).
+		Pluck("position", &positions).Error
+	if err != nil {
+		return "", fmt.Errorf("failed to get last position: %w", err)
+	}
+	if len(positions) == 0 {
+		return "", nil
+	}
+	return positions[0], nil
+}
+
+// AdjacentPosition returns the position that follows (next) or precedes a
+// position in a list, ignoring the todo being moved, or "" at either end
+func (r *todoRepository) AdjacentPosition(ctx context.Context, listID *uuid.UUID, position string, excludeID uuid.UUID, next bool) (string, error) {
+	db, _, err := r.scoped(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	db = inList(db.Model(&models.Todo{}), listID).Where("id <> ?", excludeID)
+	if next {
+		db = db.Where("position > ?", position).Order("position ASC")
+	} else {
+		db = db.Where("position < ?", position).Order("position DESC")
+	}
+
+	var positions []string
+	if err := db.Limit(1).Pluck("position", &positions).Error; err != nil {
+		return "", fmt.Errorf("failed to get adjacent position: %w", err)
+	}
+	if len(positions) == 0 {
+		return "", nil
+	}
+	return positions[0], nil
+}
+
+// SetPosition moves a todo within its list by rewriting only its own position
+func (r *todoRepository) SetPosition(ctx context.Context, id uuid.UUID, position string) error {
+	db, _, err := r.scoped(ctx)
+	if err != nil {
+		return err
+	}
+
+	result := db.Model(&models.Todo{}).Where("id = ?", id).Update("position", position)
 	if result.Error != nil {
-		return fmt.Errorf("failed to toggle todo: %w", result.Error)
+		return fmt.Errorf("failed to set position: %w", result.Error)
 	}
 	if result.RowsAffected == 0 {
-		return fmt.Errorf("todo not found")
+		return fmt.Errorf("todo not found: %w", gorm.ErrRecordNotFound)
 	}
 	return nil
 }
+
+// PositionScopes returns the lists whose order needs rebalancing: those with
+// a position key longer than maxLength or with todos that have no position yet
+func (r *todoRepository) PositionScopes(ctx context.Context, maxLength int) ([]models.PositionScope, error) {
+	var scopes []models.PositionScope
+	err := r.db.WithContext(ctx).Unscoped().
+		Model(&models.Todo{}).
+		Select("workspace_id, list_id").
+		Group("workspace_id, list_id").
+		Having("MAX(LENGTH(position)) > ? OR MIN(LENGTH(position)) = 0", maxLength).
+		Scan(&scopes).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find lists to rebalance: %w", err)
+	}
+	return scopes, nil
+}
+
+// Rebalance gives the todos of a list, deleted ones included, short evenly
+// spaced positions in their current order. Todos without a position go first,
+// oldest first, since they predate manual ordering.
+func (r *todoRepository) Rebalance(ctx context.Context, scope models.PositionScope) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ids []uuid.UUID
+		err := inList(tx.Unscoped().Model(&models.Todo{}), scope.ListID).
+			Where("workspace_id = ?", scope.WorkspaceID).
+			Order("position ASC, created_at ASC, id ASC").
+			Pluck("id", &ids).Error
+		if err != nil {
+			return fmt.Errorf("failed to get todos to rebalance: %w", err)
+		}
+
+		for i, position := range rank.Spread(len(ids)) {
+			err := tx.Session(&gorm.Session{NewDB: true}).Unscoped().
+				Model(&models.Todo{}).
+				Where("id = ?", ids[i]).
+				UpdateColumn("position", position).Error
+			if err != nil {
+				return fmt.Errorf("failed to rebalance todo: %w", err)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/1cbyc/go-todo-api/internal/auth"
+	"github.com/1cbyc/go-todo-api/internal/config"
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// newTestDB opens a migrated SQLite database in a temporary directory
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := NewDatabase(config.DatabaseConfig{
+		Driver: "sqlite",
+		DSN:    filepath.Join(t.TempDir(), "test.db"),
+	})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	return db
+}
+
+// tenant is a user acting within their own workspace
+type tenant struct {
+	ctx  context.Context
+	todo *models.Todo
+}
+
+// newTenant seeds a user, a workspace and one todo, and returns a context bound to that workspace
+func newTenant(t *testing.T, db *gorm.DB, repo TodoRepository, name string) tenant {
+	t.Helper()
+	user := &models.User{ID: uuid.New(), Email: name + "@example.com", PasswordHash: "x"}
+	workspace := &models.Workspace{ID: uuid.New(), Name: name}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	if err := db.Create(workspace).Error; err != nil {
+		t.Fatalf("failed to create workspace: %v", err)
+	}
+
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{
+		UserID:      user.ID,
+		Role:        models.RoleMember,
+		WorkspaceID: workspace.ID,
+	})
+	todo := &models.Todo{
+		ID:       uuid.New(),
+		Title:    name + " todo",
+		Priority: models.PriorityMedium,
+		Status:   models.StatusBacklog,
+		Position: "a",
+	}
+	if err := repo.Create(ctx, todo); err != nil {
+		t.Fatalf("failed to create todo: %v", err)
+	}
+	return tenant{ctx: ctx, todo: todo}
+}
+
+func TestTodoRepositoryTenantIsolation(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewTodoRepository(db)
+	a := newTenant(t, db, repo, "alpha")
+	b := newTenant(t, db, repo, "beta")
+
+	t.Run("GetByID", func(t *testing.T) {
+		if _, err := repo.GetByID(a.ctx, b.todo.ID); !IsNotFound(err) {
+			t.Fatalf("GetByID of another tenant's todo: got %v, want not found", err)
+		}
+		if _, err := repo.GetByID(a.ctx, a.todo.ID); err != nil {
+			t.Fatalf("GetByID of own todo: %v", err)
+		}
+	})
+
+	t.Run("GetAll", func(t *testing.T) {
+		todos, total, err := repo.GetAll(a.ctx, models.TodoFilter{}, 1, 20)
+		if err != nil {
+			t.Fatalf("GetAll: %v", err)
+		}
+		if total != 1 {
+			t.Errorf("GetAll counted %d todos, want 1", total)
+		}
+		if len(todos) != 1 || todos[0].ID != a.todo.ID {
+			t.Errorf("GetAll returned %v, want only the tenant's own todo", todos)
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		foreign := *b.todo
+		foreign.Title = "hijacked"
+		foreign.Completed = true
+		if err := repo.Update(a.ctx, &foreign); err == nil {
+			t.Fatal("Update of another tenant's todo succeeded")
+		}
+		assertUntouched(t, db, b.todo)
+	})
+
+	t.Run("SetPosition", func(t *testing.T) {
+		if err := repo.SetPosition(a.ctx, b.todo.ID, "z"); !IsNotFound(err) {
+			t.Fatalf("SetPosition of another tenant's todo: got %v, want not found", err)
+		}
+		assertUntouched(t, db, b.todo)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		if err := repo.Delete(a.ctx, b.todo.ID, models.SubtaskDeleteCascade); err == nil {
+			t.Fatal("Delete of another tenant's todo succeeded")
+		}
+		assertUntouched(t, db, b.todo)
+	})
+
+	t.Run("Purge", func(t *testing.T) {
+		if err := repo.Purge(a.ctx, b.todo.ID); err == nil {
+			t.Fatal("Purge of another tenant's todo succeeded")
+		}
+		assertUntouched(t, db, b.todo)
+	})
+
+	t.Run("NoWorkspace", func(t *testing.T) {
+		ctx := auth.WithPrincipal(context.Background(), &auth.Principal{UserID: uuid.New()})
+		if _, _, err := repo.GetAll(ctx, models.TodoFilter{}, 1, 20); err != ErrNoWorkspace {
+			t.Fatalf("GetAll without a workspace: got %v, want %v", err, ErrNoWorkspace)
+		}
+	})
+}
+
+// assertUntouched fails the test unless the todo is still live and unchanged in the database
+func assertUntouched(t *testing.T, db *gorm.DB, want *models.Todo) {
+	t.Helper()
+	var got models.Todo
+	if err := db.Where("id = ?", want.ID).First(&got).Error; err != nil {
+		t.Fatalf("todo %s is gone: %v", want.ID, err)
+	}
+	if got.Title != want.Title || got.Completed != want.Completed || got.Position != want.Position {
+		t.Fatalf("todo %s was changed: got %q completed=%v position=%q", want.ID, got.Title, got.Completed, got.Position)
+	}
+}
@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log"
+	"strings"
+
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Postgres indexes todos in a generated tsvector column, weighting titles above descriptions
+var postgresSearchSetup = []string{
+	`ALTER TABLE todos ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+		setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+		setweight(to_tsvector('english', coalesce(description, '')), 'B')
+	) STORED`,
+	`CREATE INDEX IF NOT EXISTS idx_todos_search ON todos USING GIN (search_vector)`,
+}
+
+// SQLite indexes todos in an FTS5 table that triggers keep in step with the todos
+// table. Soft-deleted todos stay indexed so that restoring them needs no work;
+// searches join the live todos.
+var sqliteSearchSetup = []string{
+	`CREATE VIRTUAL TABLE todos_fts USING fts5(todo_id UNINDEXED, title, description, tokenize = 'porter unicode61 remove_diacritics 2')`,
+	`CREATE TRIGGER todos_fts_insert AFTER INSERT ON todos BEGIN
+		INSERT INTO todos_fts (todo_id, title, description) VALUES (new.id, new.title, new.description);
+	END`,
+	`CREATE TRIGGER todos_fts_update AFTER UPDATE OF title, description ON todos
+	WHEN old.title IS NOT new.title OR old.description IS NOT new.description BEGIN
+		UPDATE todos_fts SET title = new.title, description = new.description WHERE todo_id = new.id;
+	END`,
+	`CREATE TRIGGER todos_fts_delete AFTER DELETE ON todos BEGIN
+		DELETE FROM todos_fts WHERE todo_id = old.id;
+	END`,
+	`INSERT INTO todos_fts (todo_id, title, description) SELECT id, title, description FROM todos`,
+}
+
+// setupTodoSearch creates the full-text index of todo titles and descriptions if
+// it does not exist yet. On SQLite builds without FTS5 search stays unavailable.
+func setupTodoSearch(db *gorm.DB) error {
+	switch db.Dialector.Name() {
+	case "postgres":
+		for _, stmt := range postgresSearchSetup {
+			if err := db.Exec(stmt).Error; err != nil {
+				return err
+			}
+		}
+	case "sqlite":
+		var fts5 bool
+		if err := db.Raw("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&fts5).Error; err != nil {
+			return err
+		}
+		if !fts5 {
+			log.Printf("SQLite was built without FTS5, todo search is disabled")
+			return nil
+		}
+		indexed, err := sqliteSearchIndexed(db)
+		if err != nil || indexed {
+			return err
+		}
+		return db.Transaction(func(tx *gorm.DB) error {
+			for _, stmt := range sqliteSearchSetup {
+				if err := tx.Exec(stmt).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	return nil
+}
+
+// sqliteSearchIndexed reports whether the FTS5 table of todos exists
+func sqliteSearchIndexed(db *gorm.DB) (bool, error) {
+	var count int64
+	err := db.Raw("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'todos_fts'").Scan(&count).Error
+	return count > 0, err
+}
+
+// Matches are marked with private-use characters rather than tags, so that the
+// text around them can be escaped before the markers become <mark> tags
+const (
+	highlightStart = "\uE000"
+	highlightStop  = "\uE001"
+)
+
+var highlightTags = strings.NewReplacer(highlightStart, "<mark>", highlightStop, "</mark>")
+
+// markHighlights escapes a highlighted text as HTML and turns its markers into <mark> tags
+func markHighlights(text string) string {
+	return highlightTags.Replace(html.EscapeString(text))
+}
+
+// searchRow is one match of a search query before its todo is loaded
+type searchRow struct {
+	ID             uuid.UUID
+	Rank           float64
+	TitleHighlight string
+	Snippet        string
+}
+
+// Search retrieves a page of the todos matching the filter whose title or
+// description contain words starting with each of the terms, best matches first
+func (r *todoRepository) Search(ctx context.Context, terms []string, filter models.TodoFilter, page, perPage int) ([]models.TodoSearchHit, int64, error) {
+	db, _, err := r.scoped(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var matches *gorm.DB
+	var columns string
+	var args []interface{}
+	base := applyTodoFilter(db, filter).Model(&models.Todo{})
+	switch r.db.Dialector.Name() {
+	case "postgres":
+		prefixes := make([]string, len(terms))
+		for i, term := range terms {
+			prefixes[i] = term + ":*"
+		}
+		matches = base.
+			Joins("CROSS JOIN to_tsquery('english', ?) AS query", strings.Join(prefixes, " & ")).
+			Where("todos.search_vector @@ query")
+		columns = `todos.id,
+				ts_rank(todos.search_vector, query) AS rank,
+				ts_headline('english', todos.title, query, ?) AS title_highlight,
+				ts_headline('english', todos.description, query, ?) AS snippet`
+		args = []interface{}{
+			fmt.Sprintf("HighlightAll=true, StartSel=%s, StopSel=%s", highlightStart, highlightStop),
+			fmt.Sprintf(`StartSel=%s, StopSel=%s, MinWords=8, MaxWords=24, MaxFragments=2, FragmentDelimiter=" … "`, highlightStart, highlightStop),
+		}
+	case "sqlite":
+		indexed, err := sqliteSearchIndexed(r.db.WithContext(ctx))
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to search todos: %w", err)
+		}
+		if !indexed {
+			return nil, 0, ErrSearchUnavailable
+		}
+		prefixes := make([]string, len(terms))
+		for i, term := range terms {
+			prefixes[i] = `"` + term + `"*`
+		}
+		// bm25 is lower for better matches and weighs each column: todo_id, title, description
+		matches = base.
+			Joins("JOIN todos_fts ON todos_fts.todo_id = todos.id").
+			Where("todos_fts MATCH ?", strings.Join(prefixes, " "))
+		columns = `todos.id,
+				-bm25(todos_fts, 0.0, 4.0, 1.0) AS rank,
+				highlight(todos_fts, 1, ?, ?) AS title_highlight,
+				snippet(todos_fts, 2, ?, ?, ' … ', 24) AS snippet`
+		args = []interface{}{highlightStart, highlightStop, highlightStart, highlightStop}
+	default:
+		return nil, 0, ErrSearchUnavailable
+	}
+
+	var total int64
+	matches = matches.Session(&gorm.Session{})
+	if err := matches.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count todos: %w", err)
+	}
+
+	var rows []searchRow
+	err = matches.
+		Select(columns, args...).
+		Order("rank DESC, todos.created_at DESC, todos.id ASC").
+		Offset((page - 1) * perPage).
+		Limit(perPage).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search todos: %w", err)
+	}
+	if len(rows) == 0 {
+		return []models.TodoSearchHit{}, total, nil
+	}
+
+	ids := make([]uuid.UUID, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+	var todos []models.Todo
+	if err := db.Preload("Tags").Where("todos.id IN ?", ids).Find(&todos).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to get todos: %w", err)
+	}
+	byID := make(map[uuid.UUID]models.Todo, len(todos))
+	for _, todo := range todos {
+		byID[todo.ID] = todo
+	}
+
+	hits := make([]models.TodoSearchHit, 0, len(rows))
+	for _, row := range rows {
+		todo, ok := byID[row.ID]
+		if !ok {
+			continue
+		}
+		hits = append(hits, models.TodoSearchHit{
+			Todo:           todo,
+			Rank:           row.Rank,
+			TitleHighlight: markHighlights(row.TitleHighlight),
+			Snippet:        markHighlights(row.Snippet),
+		})
+	}
+	return hits, total, nil
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// txKey is the context key of the transaction repository operations run in
+type txKey struct{}
+
+// Transactor runs several repository operations, possibly of different
+// repositories, as one database transaction
+type Transactor interface {
+	// Transaction calls fn with a context in which every repository operation
+	// runs in the same transaction. The transaction is committed when fn returns
+	// nil and rolled back when it returns an error or panics. Transactions started
+	// within fn, including the repositories' own, become savepoints.
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// transactor implements Transactor
+type transactor struct {
+	db *gorm.DB
+}
+
+// NewTransactor creates a new transactor
+func NewTransactor(db *gorm.DB) Transactor {
+	return &transactor{db: db}
+}
+
+// Transaction runs fn in a transaction, see Transactor
+func (t *transactor) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return conn(ctx, t.db).WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// conn returns the transaction the context carries, or db outside of one
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx
+	}
+	return db
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UserRepository defines the interface for user data operations
+type UserRepository interface {
+	LockRegistration(ctx context.Context) error
+	Lock(ctx context.Context, id uuid.UUID) error
+	Create(ctx context.Context, user *models.User) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.User, error)
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	GetAll(ctx context.Context, page, perPage int) ([]models.User, int64, error)
+	Count(ctx context.Context) (int64, error)
+	CountByRole(ctx context.Context, role models.Role) (int64, error)
+	UpdateRole(ctx context.Context, id uuid.UUID, role models.Role) error
+}
+
+// userRepository implements UserRepository
+type userRepository struct {
+	db *gorm.DB
+}
+
+// NewUserRepository creates a new user repository
+func NewUserRepository(db *gorm.DB) UserRepository {
+	return &userRepository{db: db}
+}
+
+// LockRegistration serializes registrations until the surrounding transaction
+// ends, so that a registration can count the users before it. On SQLite every
+// transaction already holds the database's write lock.
+func (r *userRepository) LockRegistration(ctx context.Context) error {
+	if r.db.Dialector.Name() != "postgres" {
+		return nil
+	}
+	err := conn(ctx, r.db).WithContext(ctx).Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "users:registration").Error
+	if err != nil {
+		return fmt.Errorf("failed to lock registrations: %w", err)
+	}
+	return nil
+}
+
+// Lock locks a user's row until the surrounding transaction ends, so that
+// changes made on behalf of the user run one after another
+func (r *userRepository) Lock(ctx context.Context, id uuid.UUID) error {
+	var locked []uuid.UUID
+	err := conn(ctx, r.db).WithContext(ctx).
+		Model(&models.User{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		Pluck("id", &locked).Error
+	if err != nil {
+		return fmt.Errorf("failed to lock user: %w", err)
+	}
+	return nil
+}
+
+// Create creates a new user. Within a Transactor transaction it runs in that transaction.
+func (r *userRepository) Create(ctx context.Context, user *models.User) error {
+	if err := conn(ctx, r.db).WithContext(ctx).Create(user).Error; err != nil {
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a user by ID
+func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	var user models.User
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&user).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("user not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return &user, nil
+}
+
+// GetByEmail retrieves a user by email address
+func (r *userRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	var user models.User
+	err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("user not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return &user, nil
+}
+
+// GetAll retrieves all users with pagination
+func (r *userRepository) GetAll(ctx context.Context, page, perPage int) ([]models.User, int64, error) {
+	var users []models.User
+	var total int64
+
+	if err := r.db.WithContext(ctx).Model(&models.User{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	err := r.db.WithContext(ctx).
+		Order("created_at ASC").
+		Offset((page - 1) * perPage).
+		Limit(perPage).
+		Find(&users).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get users: %w", err)
+	}
+
+	return users, total, nil
+}
+
+// Count returns the number of users
+func (r *userRepository) Count(ctx context.Context) (int64, error) {
+	var count int64
+	if err := conn(ctx, r.db).WithContext(ctx).Model(&models.User{}).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count users: %w", err)
+	}
+	return count, nil
+}
+
+// CountByRole returns the number of users with the given role
+func (r *userRepository) CountByRole(ctx context.Context, role models.Role) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.User{}).Where("role = ?", role).Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count users: %w", err)
+	}
+	return count, nil
+}
+
+// UpdateRole changes the role of a user
+func (r *userRepository) UpdateRole(ctx context.Context, id uuid.UUID, role models.Role) error {
+	result := r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", id).Update("role", role)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update user role: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("user not found: %w", gorm.ErrRecordNotFound)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ViewRepository defines the interface for saved view data operations. Users
+// see their own views and the views others in the workspace have shared.
+type ViewRepository interface {
+	Create(ctx context.Context, view *models.SavedView) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.SavedView, error)
+	GetAll(ctx context.Context) ([]models.SavedView, error)
+	Update(ctx context.Context, view *models.SavedView) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// viewRepository implements ViewRepository
+type viewRepository struct {
+	db *gorm.DB
+}
+
+// NewViewRepository creates a new saved view repository
+func NewViewRepository(db *gorm.DB) ViewRepository {
+	return &viewRepository{db: db}
+}
+
+// visible returns a query restricted to the views the current user can see
+func (r *viewRepository) visible(ctx context.Context) (*gorm.DB, error) {
+	db, principal, err := tenantScope(ctx, r.db, "saved_views")
+	if err != nil {
+		return nil, err
+	}
+	return db.Where("saved_views.user_id = ? OR saved_views.shared", principal.UserID), nil
+}
+
+// Create saves a view of the current user
+func (r *viewRepository) Create(ctx context.Context, view *models.SavedView) error {
+	db, principal, err := tenantScope(ctx, r.db, "saved_views")
+	if err != nil {
+		return err
+	}
+	view.WorkspaceID = principal.WorkspaceID
+	view.UserID = principal.UserID
+	if err := db.Create(view).Error; err != nil {
+		return fmt.Errorf("failed to create view: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a view that the current user owns or that was shared with the workspace
+func (r *viewRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.SavedView, error) {
+	db, err := r.visible(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var view models.SavedView
+	err = db.Where("id = ?", id).First(&view).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("view not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get view: %w", err)
+	}
+	return &view, nil
+}
+
+// GetAll retrieves the views of the current user and the shared views of the workspace
+func (r *viewRepository) GetAll(ctx context.Context) ([]models.SavedView, error) {
+	db, err := r.visible(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var views []models.SavedView
+	if err := db.Order("name ASC, created_at ASC").Find(&views).Error; err != nil {
+		return nil, fmt.Errorf("failed to get views: %w", err)
+	}
+	return views, nil
+}
+
+// Update updates one of the current user's views
+func (r *viewRepository) Update(ctx context.Context, view *models.SavedView) error {
+	db, principal, err := tenantScope(ctx, r.db, "saved_views")
+	if err != nil {
+		return err
+	}
+	view.WorkspaceID = principal.WorkspaceID
+
+	result := db.Model(view).
+		Where("user_id = ?", principal.UserID).
+		Select("name", "shared", "definition", "updated_at").
+		Updates(view)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update view: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("view not found: %w", gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
+// Delete deletes one of the current user's views
+func (r *viewRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	db, principal, err := tenantScope(ctx, r.db, "saved_views")
+	if err != nil {
+		return err
+	}
+
+	result := db.Where("id = ? AND user_id = ?", id, principal.UserID).Delete(&models.SavedView{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete view: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("view not found: %w", gorm.ErrRecordNotFound)
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// WorkspaceRepository defines the interface for workspace data operations
+type WorkspaceRepository interface {
+	Create(ctx context.Context, workspace *models.Workspace, ownerID uuid.UUID) error
+	ListForUser(ctx context.Context, userID uuid.UUID) ([]models.Workspace, []models.WorkspaceMember, error)
+	GetMembership(ctx context.Context, workspaceID, userID uuid.UUID) (*models.WorkspaceMember, error)
+	GetDefaultMembership(ctx context.Context, userID uuid.UUID) (*models.WorkspaceMember, error)
+	ListMembers(ctx context.Context, workspaceID uuid.UUID) ([]models.WorkspaceMember, error)
+	AddMember(ctx context.Context, member *models.WorkspaceMember) error
+	RemoveMember(ctx context.Context, workspaceID, userID uuid.UUID) error
+	CountOwners(ctx context.Context, workspaceID uuid.UUID) (int64, error)
+	AdoptUnassignedTodos(ctx context.Context, workspaceID, userID uuid.UUID) error
+}
+
+// workspaceRepository implements WorkspaceRepository
+type workspaceRepository struct {
+	db *gorm.DB
+}
+
+// NewWorkspaceRepository creates a new workspace repository
+func NewWorkspaceRepository(db *gorm.DB) WorkspaceRepository {
+	return &workspaceRepository{db: db}
+}
+
+// Create creates a new workspace with the given user as its owner
+func (r *workspaceRepository) Create(ctx context.Context, workspace *models.Workspace, ownerID uuid.UUID) error {
+	return conn(ctx, r.db).WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(workspace).Error; err != nil {
+			return fmt.Errorf("failed to create workspace: %w", err)
+		}
+		member := &models.WorkspaceMember{
+			WorkspaceID: workspace.ID,
+			UserID:      ownerID,
+			Role:        models.WorkspaceRoleOwner,
+		}
+		if err := tx.Create(member).Error; err != nil {
+			return fmt.Errorf("failed to add workspace owner: %w", err)
+		}
+		return nil
+	})
+}
+
+// ListForUser retrieves the workspaces a user belongs to along with the memberships
+func (r *workspaceRepository) ListForUser(ctx context.Context, userID uuid.UUID) ([]models.Workspace, []models.WorkspaceMember, error) {
+	var members []models.WorkspaceMember
+	err := r.db.WithContext(ctx).
+		Joins("JOIN workspaces ON workspaces.id = workspace_members.workspace_id AND workspaces.deleted_at IS NULL").
+		Where("workspace_members.user_id = ?", userID).
+		Order("workspace_members.created_at ASC").
+		Find(&members).Error
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list memberships: %w", err)
+	}
+
+	if len(members) == 0 {
+		return nil, nil, nil
+	}
+
+	ids := make([]uuid.UUID, len(members))
+	for i, member := range members {
+		ids[i] = member.WorkspaceID
+	}
+
+	var workspaces []models.Workspace
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&workspaces).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+
+	return workspaces, members, nil
+}
+
+// GetMembership retrieves a user's membership in a workspace
+func (r *workspaceRepository) GetMembership(ctx context.Context, workspaceID, userID uuid.UUID) (*models.WorkspaceMember, error) {
+	var member models.WorkspaceMember
+	err := r.db.WithContext(ctx).
+		Joins("JOIN workspaces ON workspaces.id = workspace_members.workspace_id AND workspaces.deleted_at IS NULL").
+		Where("workspace_members.workspace_id = ? AND workspace_members.user_id = ?", workspaceID, userID).
+		First(&member).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("membership not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get membership: %w", err)
+	}
+	return &member, nil
+}
+
+// GetDefaultMembership retrieves the oldest membership of a user
+func (r *workspaceRepository) GetDefaultMembership(ctx context.Context, userID uuid.UUID) (*models.WorkspaceMember, error) {
+	var member models.WorkspaceMember
+	err := conn(ctx, r.db).WithContext(ctx).
+		Joins("JOIN workspaces ON workspaces.id = workspace_members.workspace_id AND workspaces.deleted_at IS NULL").
+		Where("workspace_members.user_id = ?", userID).
+		Order("workspace_members.created_at ASC").
+		First(&member).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("membership not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get membership: %w", err)
+	}
+	return &member, nil
+}
+
+// ListMembers retrieves the members of a workspace with their user records
+func (r *workspaceRepository) ListMembers(ctx context.Context, workspaceID uuid.UUID) ([]models.WorkspaceMember, error) {
+	var members []models.WorkspaceMember
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Where("workspace_id = ?", workspaceID).
+		Order("created_at ASC").
+		Find(&members).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list members: %w", err)
+	}
+	return members, nil
+}
+
+// AddMember adds a user to a workspace
+func (r *workspaceRepository) AddMember(ctx context.Context, member *models.WorkspaceMember) error {
+	if err := r.db.WithContext(ctx).Omit("User").Create(member).Error; err != nil {
+		return fmt.Errorf("failed to add member: %w", err)
+	}
+	return nil
+}
+
+// RemoveMember removes a user from a workspace
+func (r *workspaceRepository) RemoveMember(ctx context.Context, workspaceID, userID uuid.UUID) error {
+	result := r.db.WithContext(ctx).
+		Where("workspace_id = ? AND user_id = ?", workspaceID, userID).
+		Delete(&models.WorkspaceMember{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to remove member: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("membership not found: %w", gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
+// CountOwners returns the number of owners of a workspace
+func (r *workspaceRepository) CountOwners(ctx context.Context, workspaceID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.WorkspaceMember{}).
+		Where("workspace_id = ? AND role = ?", workspaceID, models.WorkspaceRoleOwner).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count owners: %w", err)
+	}
+	return count, nil
+}
+
+// AdoptUnassignedTodos moves a user's todos created before workspaces existed into a workspace
+func (r *workspaceRepository) AdoptUnassignedTodos(ctx context.Context, workspaceID, userID uuid.UUID) error {
+	err := conn(ctx, r.db).WithContext(ctx).
+		Model(&models.Todo{}).
+		Where("user_id = ? AND (workspace_id IS NULL OR workspace_id = ?)", userID, uuid.Nil).
+		Update("workspace_id", workspaceID).Error
+	if err != nil {
+		return fmt.Errorf("failed to adopt todos: %w", err)
+	}
+	return nil
+}
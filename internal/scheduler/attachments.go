@@ -0,0 +1,43 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/1cbyc/go-todo-api/internal/repository"
+	"github.com/1cbyc/go-todo-api/internal/storage"
+	"github.com/rs/zerolog"
+)
+
+// CleanupAttachments returns a job that deletes the blobs and records of
+// attachments whose todo has been purged
+func CleanupAttachments(repo repository.AttachmentRepository, store storage.BlobStore, logger zerolog.Logger) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		removed := 0
+		for {
+			orphans, err := repo.Orphaned(ctx, batchSize)
+			if err != nil {
+				return err
+			}
+
+			for _, attachment := range orphans {
+				if err := store.Delete(ctx, attachment.StorageKey); err != nil {
+					return fmt.Errorf("failed to delete blob %s: %w", attachment.StorageKey, err)
+				}
+				if err := repo.Remove(ctx, attachment.ID); err != nil {
+					return err
+				}
+				removed++
+			}
+
+			if len(orphans) < batchSize {
+				break
+			}
+		}
+
+		if removed > 0 {
+			logger.Info().Int("count", removed).Msg("Removed attachments of purged todos")
+		}
+		return nil
+	}
+}
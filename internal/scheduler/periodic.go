@@ -0,0 +1,70 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Periodic runs a job once at start and then at a fixed interval until stopped
+type Periodic struct {
+	name     string
+	interval time.Duration
+	job      func(ctx context.Context) error
+	logger   zerolog.Logger
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewPeriodic creates a new periodic job
+func NewPeriodic(name string, interval time.Duration, job func(ctx context.Context) error, logger zerolog.Logger) *Periodic {
+	return &Periodic{
+		name:     name,
+		interval: interval,
+		job:      job,
+		logger:   logger,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start starts running the job in the background
+func (p *Periodic) Start() {
+	go p.run()
+}
+
+// Stop stops the job and waits for a run in progress, if any, to finish
+func (p *Periodic) Stop(ctx context.Context) error {
+	p.stopOnce.Do(func() { close(p.stop) })
+
+	select {
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run runs the job until stopped
+func (p *Periodic) run() {
+	defer close(p.done)
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.job(context.Background()); err != nil {
+			p.logger.Error().Err(err).Str("job", p.name).Msg("Background job failed")
+		}
+
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package scheduler
+
+import (
+	"context"
+
+	"github.com/1cbyc/go-todo-api/internal/repository"
+	"github.com/rs/zerolog"
+)
+
+// RebalancePositions returns a job that gives lists whose position keys have
+// grown longer than maxLength, or that have todos without a position, short
+// evenly spaced keys again
+func RebalancePositions(repo repository.TodoRepository, maxLength int, logger zerolog.Logger) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		scopes, err := repo.PositionScopes(ctx, maxLength)
+		if err != nil {
+			return err
+		}
+
+		for _, scope := range scopes {
+			if err := repo.Rebalance(ctx, scope); err != nil {
+				return err
+			}
+		}
+
+		if len(scopes) > 0 {
+			logger.Info().Int("count", len(scopes)).Msg("Rebalanced todo positions")
+		}
+		return nil
+	}
+}
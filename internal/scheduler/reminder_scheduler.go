@@ -0,0 +1,173 @@
+// Package scheduler runs background jobs alongside the API server.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/1cbyc/go-todo-api/internal/config"
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/1cbyc/go-todo-api/internal/notify"
+	"github.com/1cbyc/go-todo-api/internal/repository"
+	"github.com/1cbyc/go-todo-api/pkg/text"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// batchSize caps how many due reminders are loaded per poll
+	batchSize = 100
+	// deliveryTimeout bounds a single delivery attempt
+	deliveryTimeout = 30 * time.Second
+)
+
+// errNoNotifier is recorded on reminders whose channel has no notifier configured
+var errNoNotifier = errors.New("notification channel is not configured")
+
+// ReminderScheduler delivers due reminders. Reminders live in the database, so
+// anything that comes due while the server is down is delivered after the
+// next start. It is meant to run in a single instance.
+type ReminderScheduler struct {
+	repo      repository.ReminderRepository
+	notifiers map[models.ReminderChannel]notify.Notifier
+	cfg       config.ReminderConfig
+	logger    zerolog.Logger
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewReminderScheduler creates a new reminder scheduler
+func NewReminderScheduler(repo repository.ReminderRepository, notifiers map[models.ReminderChannel]notify.Notifier, cfg config.ReminderConfig, logger zerolog.Logger) *ReminderScheduler {
+	return &ReminderScheduler{
+		repo:      repo,
+		notifiers: notifiers,
+		cfg:       cfg,
+		logger:    logger,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+}
+
+// Start requeues reminders left claimed by an unclean shutdown and starts polling
+func (s *ReminderScheduler) Start(ctx context.Context) error {
+	requeued, err := s.repo.RequeueClaimed(ctx)
+	if err != nil {
+		return err
+	}
+	if requeued > 0 {
+		s.logger.Warn().Int64("count", requeued).Msg("Requeued reminders interrupted by shutdown")
+	}
+
+	go s.run()
+	return nil
+}
+
+// Stop stops polling and waits for the delivery in progress, if any, to finish.
+// Reminders not yet delivered stay pending for the next start.
+func (s *ReminderScheduler) Stop(ctx context.Context) error {
+	s.stopOnce.Do(func() { close(s.stop) })
+
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run polls for due reminders until stopped
+func (s *ReminderScheduler) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.dispatch()
+
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// dispatch delivers the reminders that are currently due
+func (s *ReminderScheduler) dispatch() {
+	reminders, err := s.repo.Due(context.Background(), time.Now(), batchSize)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to load due reminders")
+		return
+	}
+
+	for i := range reminders {
+		select {
+		case <-s.stop:
+			return
+		default:
+		}
+		s.deliver(&reminders[i])
+	}
+}
+
+// deliver sends one reminder and records the outcome
+func (s *ReminderScheduler) deliver(reminder *models.Reminder) {
+	ctx, cancel := context.WithTimeout(context.Background(), deliveryTimeout)
+	defer cancel()
+
+	log := s.logger.With().Str("reminder_id", reminder.ID.String()).Str("channel", string(reminder.Channel)).Logger()
+
+	claimed, err := s.repo.Claim(ctx, reminder.ID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to claim reminder")
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	// Nothing to remind about once the todo is done or gone
+	if reminder.Todo == nil || reminder.Todo.Completed || reminder.User == nil {
+		if err := s.repo.Cancel(ctx, reminder.ID); err != nil {
+			log.Error().Err(err).Msg("Failed to cancel reminder")
+		}
+		return
+	}
+
+	err = errNoNotifier
+	if notifier, ok := s.notifiers[reminder.Channel]; ok {
+		err = notifier.Notify(ctx, notify.Message{
+			ReminderID:  reminder.ID,
+			WorkspaceID: reminder.WorkspaceID,
+			TodoID:      reminder.TodoID,
+			Recipient:   reminder.User.Email,
+			Title:       reminder.Todo.Title,
+			Description: reminder.Todo.Description,
+			DueDate:     reminder.Todo.DueDate,
+			RemindAt:    *reminder.RemindAt,
+		})
+	}
+	if err == nil {
+		if err := s.repo.MarkSent(ctx, reminder.ID, time.Now()); err != nil {
+			log.Error().Err(err).Msg("Failed to mark reminder sent")
+		}
+		log.Info().Msg("Reminder delivered")
+		return
+	}
+
+	attempts := reminder.Attempts + 1
+	var retryAt *time.Time
+	if attempts < s.cfg.MaxAttempts && !errors.Is(err, errNoNotifier) {
+		next := time.Now().Add(s.cfg.RetryDelay << min(attempts-1, 10))
+		retryAt = &next
+	}
+	log.Warn().Err(err).Int("attempts", attempts).Bool("retrying", retryAt != nil).Msg("Reminder delivery failed")
+
+	if err := s.repo.MarkFailed(ctx, reminder.ID, attempts, text.Truncate(err.Error(), 1000), retryAt); err != nil {
+		log.Error().Err(err).Msg("Failed to record reminder failure")
+	}
+}
@@ -0,0 +1,35 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/1cbyc/go-todo-api/internal/repository"
+	"github.com/rs/zerolog"
+)
+
+// PurgeTrash returns a job that permanently deletes todos that have been in
+// the trash for longer than retention
+func PurgeTrash(repo repository.TodoRepository, retention time.Duration, logger zerolog.Logger) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		before := time.Now().Add(-retention)
+
+		purged := 0
+		for {
+			count, err := repo.PurgeExpired(ctx, before, batchSize)
+			if err != nil {
+				return err
+			}
+			purged += count
+
+			if count < batchSize {
+				break
+			}
+		}
+
+		if purged > 0 {
+			logger.Info().Int("count", purged).Msg("Purged todos from the trash")
+		}
+		return nil
+	}
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/1cbyc/go-todo-api/internal/auth"
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/1cbyc/go-todo-api/internal/repository"
+	"github.com/google/uuid"
+)
+
+// lastUsedResolution limits how often last-used timestamps are written for a key
+const lastUsedResolution = time.Minute
+
+var (
+	// ErrAPIKeyNotFound is returned when an API key does not exist or belongs to another user
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	// ErrAPIKeyExpiryInPast is returned when creating a key that has already expired
+	ErrAPIKeyExpiryInPast = errors.New("api key expiry must be in the future")
+)
+
+// APIKeyService defines the interface for API key operations
+type APIKeyService interface {
+	Create(ctx context.Context, req *models.CreateAPIKeyRequest) (*models.CreatedAPIKeyResponse, error)
+	List(ctx context.Context) ([]models.APIKeyResponse, error)
+	Revoke(ctx context.Context, id uuid.UUID) error
+	VerifyAPIKey(ctx context.Context, key string) (*auth.Principal, error)
+}
+
+// apiKeyService implements APIKeyService
+type apiKeyService struct {
+	repo repository.APIKeyRepository
+}
+
+// NewAPIKeyService creates a new API key service
+func NewAPIKeyService(repo repository.APIKeyRepository) APIKeyService {
+	return &apiKeyService{repo: repo}
+}
+
+// Create issues a new API key for the authenticated user. The returned key is never shown again.
+func (s *apiKeyService) Create(ctx context.Context, req *models.CreateAPIKeyRequest) (*models.CreatedAPIKeyResponse, error) {
+	userID, ok := auth.UserIDFromContext(ctx)
+	if !ok {
+		return nil, repository.ErrUnauthenticated
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, ErrAPIKeyExpiryInPast
+	}
+
+	key, prefix, err := auth.NewAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey := &models.APIKey{
+		UserID:    userID,
+		Name:      strings.TrimSpace(req.Name),
+		Prefix:    prefix,
+		KeyHash:   auth.HashToken(key),
+		Scopes:    uniqueScopes(req.Scopes),
+		ExpiresAt: req.ExpiresAt,
+	}
+
+	if err := s.repo.Create(ctx, apiKey); err != nil {
+		return nil, fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	return &models.CreatedAPIKeyResponse{
+		APIKeyResponse: apiKey.ToResponse(),
+		Key:            key,
+	}, nil
+}
+
+// List retrieves the API keys of the authenticated user
+func (s *apiKeyService) List(ctx context.Context) ([]models.APIKeyResponse, error) {
+	userID, ok := auth.UserIDFromContext(ctx)
+	if !ok {
+		return nil, repository.ErrUnauthenticated
+	}
+
+	keys, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+
+	responses := make([]models.APIKeyResponse, len(keys))
+	for i, key := range keys {
+		responses[i] = key.ToResponse()
+	}
+	return responses, nil
+}
+
+// Revoke deletes one of the authenticated user's API keys
+func (s *apiKeyService) Revoke(ctx context.Context, id uuid.UUID) error {
+	userID, ok := auth.UserIDFromContext(ctx)
+	if !ok {
+		return repository.ErrUnauthenticated
+	}
+
+	if err := s.repo.Delete(ctx, userID, id); err != nil {
+		if repository.IsNotFound(err) {
+			return ErrAPIKeyNotFound
+		}
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+	return nil
+}
+
+// VerifyAPIKey resolves an API key to the principal it acts for and records its use
+func (s *apiKeyService) VerifyAPIKey(ctx context.Context, key string) (*auth.Principal, error) {
+	prefix, err := auth.ParseAPIKeyPrefix(key)
+	if err != nil {
+		return nil, auth.ErrInvalidAPIKey
+	}
+
+	apiKey, err := s.repo.GetByPrefix(ctx, prefix)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, auth.ErrInvalidAPIKey
+		}
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+
+	now := time.Now()
+	if !auth.MatchToken(key, apiKey.KeyHash) || apiKey.IsExpired(now) {
+		return nil, auth.ErrInvalidAPIKey
+	}
+
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= lastUsedResolution {
+		if err := s.repo.TouchLastUsed(ctx, apiKey.ID, now); err != nil {
+			return nil, err
+		}
+	}
+
+	scopes := make([]auth.Permission, len(apiKey.Scopes))
+	for i, scope := range apiKey.Scopes {
+		scopes[i] = auth.Permission(scope)
+	}
+
+	return &auth.Principal{
+		UserID:   apiKey.UserID,
+		APIKeyID: apiKey.ID,
+		Scopes:   scopes,
+	}, nil
+}
+
+// uniqueScopes removes duplicate scopes while preserving order
+func uniqueScopes(scopes []string) []string {
+	seen := make(map[string]bool, len(scopes))
+	result := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if !seen[scope] {
+			seen[scope] = true
+			result = append(result, scope)
+		}
+	}
+	return result
+}
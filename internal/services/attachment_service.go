@@ -0,0 +1,231 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/1cbyc/go-todo-api/internal/auth"
+	"github.com/1cbyc/go-todo-api/internal/config"
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/1cbyc/go-todo-api/internal/repository"
+	"github.com/1cbyc/go-todo-api/internal/storage"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrAttachmentNotFound is returned when an attachment does not exist on the todo
+	ErrAttachmentNotFound = errors.New("attachment not found")
+	// ErrAttachmentTooLarge is returned when an upload exceeds the configured size limit
+	ErrAttachmentTooLarge = errors.New("attachment is too large")
+	// ErrAttachmentEmpty is returned when an upload has no content
+	ErrAttachmentEmpty = errors.New("attachment is empty")
+	// ErrUnsupportedType is returned when the detected content type of an upload is not allowed
+	ErrUnsupportedType = errors.New("attachment type is not allowed")
+)
+
+// AttachmentService defines the interface for attachment business operations
+type AttachmentService interface {
+	Upload(ctx context.Context, todoID uuid.UUID, filename string, size int64, content io.Reader) (*models.Attachment, error)
+	List(ctx context.Context, todoID uuid.UUID) ([]models.Attachment, error)
+	Open(ctx context.Context, todoID, id uuid.UUID) (*models.Attachment, io.ReadSeekCloser, error)
+	Delete(ctx context.Context, todoID, id uuid.UUID) error
+}
+
+// attachmentService implements AttachmentService
+type attachmentService struct {
+	repo  repository.AttachmentRepository
+	todos repository.TodoRepository
+	store storage.BlobStore
+	cfg   config.AttachmentConfig
+}
+
+// NewAttachmentService creates a new attachment service
+func NewAttachmentService(repo repository.AttachmentRepository, todos repository.TodoRepository, store storage.BlobStore, cfg config.AttachmentConfig) AttachmentService {
+	return &attachmentService{repo: repo, todos: todos, store: store, cfg: cfg}
+}
+
+// Upload stores a file and attaches it to a todo. The content type is detected
+// from the content itself rather than trusted from the client.
+func (s *attachmentService) Upload(ctx context.Context, todoID uuid.UUID, filename string, size int64, content io.Reader) (*models.Attachment, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosWrite); err != nil {
+		return nil, err
+	}
+
+	if size > s.cfg.MaxSize {
+		return nil, ErrAttachmentTooLarge
+	}
+	if size == 0 {
+		return nil, ErrAttachmentEmpty
+	}
+	if err := s.checkTodo(ctx, todoID); err != nil {
+		return nil, err
+	}
+
+	reader := bufio.NewReaderSize(content, 512)
+	head, err := reader.Peek(512)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, fmt.Errorf("failed to read upload: %w", err)
+	}
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	if !s.allowed(contentType) {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, contentType)
+	}
+
+	principal, _ := auth.PrincipalFromContext(ctx)
+	attachment := &models.Attachment{
+		ID:          uuid.New(),
+		TodoID:      todoID,
+		Filename:    cleanFilename(filename),
+		ContentType: contentType,
+		Size:        size,
+	}
+	attachment.StorageKey = fmt.Sprintf("%s/%s/%s", principal.WorkspaceID, todoID, attachment.ID)
+
+	hash := sha256.New()
+	if err := s.store.Put(ctx, attachment.StorageKey, io.TeeReader(reader, hash), size, contentType); err != nil {
+		return nil, fmt.Errorf("failed to store attachment: %w", err)
+	}
+	attachment.Checksum = hex.EncodeToString(hash.Sum(nil))
+
+	if err := s.repo.Create(ctx, attachment); err != nil {
+		err = fmt.Errorf("failed to create attachment: %w", err)
+		if delErr := s.store.Delete(ctx, attachment.StorageKey); delErr != nil {
+			err = errors.Join(err, delErr)
+		}
+		return nil, err
+	}
+	return attachment, nil
+}
+
+// List retrieves the attachments of a todo
+func (s *attachmentService) List(ctx context.Context, todoID uuid.UUID) ([]models.Attachment, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosRead); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkTodo(ctx, todoID); err != nil {
+		return nil, err
+	}
+
+	attachments, err := s.repo.ListByTodo(ctx, todoID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachments: %w", err)
+	}
+	return attachments, nil
+}
+
+// Open retrieves an attachment with a reader over its content. The caller must close the reader.
+func (s *attachmentService) Open(ctx context.Context, todoID, id uuid.UUID) (*models.Attachment, io.ReadSeekCloser, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosRead); err != nil {
+		return nil, nil, err
+	}
+
+	attachment, err := s.get(ctx, todoID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := s.store.Open(ctx, attachment.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrBlobNotFound) {
+			return nil, nil, ErrAttachmentNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to open attachment: %w", err)
+	}
+	return attachment, content, nil
+}
+
+// Delete removes an attachment and its content
+func (s *attachmentService) Delete(ctx context.Context, todoID, id uuid.UUID) error {
+	if err := auth.Authorize(ctx, auth.PermissionTodosWrite); err != nil {
+		return err
+	}
+
+	attachment, err := s.get(ctx, todoID, id)
+	if err != nil {
+		return err
+	}
+
+	// Remove the content first so a failure never leaves a blob without a record
+	if err := s.store.Delete(ctx, attachment.StorageKey); err != nil {
+		return fmt.Errorf("failed to delete attachment content: %w", err)
+	}
+	if err := s.repo.Delete(ctx, todoID, id); err != nil {
+		if repository.IsNotFound(err) {
+			return ErrAttachmentNotFound
+		}
+		return fmt.Errorf("failed to delete attachment: %w", err)
+	}
+	return nil
+}
+
+// get retrieves an attachment of a todo in the current workspace
+func (s *attachmentService) get(ctx context.Context, todoID, id uuid.UUID) (*models.Attachment, error) {
+	attachment, err := s.repo.GetByID(ctx, todoID, id)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrAttachmentNotFound
+		}
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+
+	// Attachments of deleted todos stay stored but are not reachable
+	if err := s.checkTodo(ctx, todoID); err != nil {
+		if errors.Is(err, ErrTodoNotFound) {
+			return nil, ErrAttachmentNotFound
+		}
+		return nil, err
+	}
+	return attachment, nil
+}
+
+// allowed reports whether uploads of the content type are accepted
+func (s *attachmentService) allowed(contentType string) bool {
+	for _, allowed := range s.cfg.AllowedTypes {
+		if strings.EqualFold(allowed, contentType) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkTodo returns ErrTodoNotFound unless the todo exists in the current workspace
+func (s *attachmentService) checkTodo(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.todos.GetByID(ctx, id); err != nil {
+		if repository.IsNotFound(err) {
+			return ErrTodoNotFound
+		}
+		return fmt.Errorf("failed to get todo: %w", err)
+	}
+	return nil
+}
+
+// cleanFilename keeps the base name of an uploaded file, without control
+// characters and at most 255 bytes long
+func cleanFilename(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, name)
+	for len(name) > 255 {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	if name == "" || name == "." || name == "/" {
+		return "attachment"
+	}
+	return name
+}
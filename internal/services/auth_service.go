@@ -0,0 +1,309 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/1cbyc/go-todo-api/internal/auth"
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/1cbyc/go-todo-api/internal/repository"
+	"github.com/1cbyc/go-todo-api/pkg/text"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	// ErrEmailTaken is returned when registering with an email that is already in use
+	ErrEmailTaken = errors.New("email already registered")
+	// ErrInvalidCredentials is returned when an email and password do not match
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	// ErrInvalidRefreshToken is returned when a refresh token is unknown, expired or revoked
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	// ErrRefreshTokenReused is returned when an already rotated refresh token is presented again
+	ErrRefreshTokenReused = errors.New("refresh token reuse detected")
+	// ErrSessionNotFound is returned when a session does not exist or belongs to another user
+	ErrSessionNotFound = errors.New("session not found")
+)
+
+// dummyPasswordHash is compared against on logins with an unknown email, so that
+// they take as long as logins with a wrong password
+var dummyPasswordHash = []byte("$2a$10$eqNiKFbORMyN6cpqr8..Je8Z8ZPCFR1Bf0O0toWqKzvh5n8P5eRKu")
+
+// AuthService defines the interface for authentication operations
+type AuthService interface {
+	Register(ctx context.Context, req *models.RegisterRequest, client models.ClientInfo) (*models.AuthResponse, error)
+	Login(ctx context.Context, req *models.LoginRequest, client models.ClientInfo) (*models.AuthResponse, error)
+	Refresh(ctx context.Context, req *models.RefreshRequest) (*models.AuthResponse, error)
+	Logout(ctx context.Context) error
+	ListSessions(ctx context.Context) ([]models.SessionResponse, error)
+	RevokeSession(ctx context.Context, id uuid.UUID) error
+}
+
+// authService implements AuthService
+type authService struct {
+	users       repository.UserRepository
+	sessions    repository.SessionRepository
+	revocations auth.RevocationStore
+	tokens      *auth.TokenManager
+	txs         repository.Transactor
+}
+
+// NewAuthService creates a new auth service
+func NewAuthService(users repository.UserRepository, sessions repository.SessionRepository, revocations auth.RevocationStore, tokens *auth.TokenManager, txs repository.Transactor) AuthService {
+	return &authService{
+		users:       users,
+		sessions:    sessions,
+		revocations: revocations,
+		tokens:      tokens,
+		txs:         txs,
+	}
+}
+
+// Register creates a new user account and opens a session
+func (s *authService) Register(ctx context.Context, req *models.RegisterRequest, client models.ClientInfo) (*models.AuthResponse, error) {
+	email := normalizeEmail(req.Email)
+
+	if _, err := s.users.GetByEmail(ctx, email); err == nil {
+		return nil, ErrEmailTaken
+	} else if !repository.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to check email: %w", err)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	user := &models.User{
+		Email:        email,
+		Name:         strings.TrimSpace(req.Name),
+		Role:         models.RoleMember,
+		PasswordHash: string(hash),
+	}
+
+	err = s.txs.Transaction(ctx, func(ctx context.Context) error {
+		// The first account on a fresh deployment becomes the administrator.
+		// Concurrent registrations wait for each other, so only one of them counts no users.
+		if err := s.users.LockRegistration(ctx); err != nil {
+			return err
+		}
+		count, err := s.users.Count(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to count users: %w", err)
+		}
+		if count == 0 {
+			user.Role = models.RoleAdmin
+		}
+
+		if err := s.users.Create(ctx, user); err != nil {
+			if repository.IsDuplicate(err) {
+				return ErrEmailTaken
+			}
+			return fmt.Errorf("failed to create user: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.openSession(ctx, user, client)
+}
+
+// Login verifies the credentials and opens a session
+func (s *authService) Login(ctx context.Context, req *models.LoginRequest, client models.ClientInfo) (*models.AuthResponse, error) {
+	user, err := s.users.GetByEmail(ctx, normalizeEmail(req.Email))
+	if err != nil {
+		if repository.IsNotFound(err) {
+			bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(req.Password))
+			return nil, ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	return s.openSession(ctx, user, client)
+}
+
+// Refresh rotates a refresh token and issues a new access token.
+// Presenting a refresh token that was already rotated revokes the whole session.
+func (s *authService) Refresh(ctx context.Context, req *models.RefreshRequest) (*models.AuthResponse, error) {
+	current, err := s.sessions.GetRefreshToken(ctx, auth.HashToken(req.RefreshToken))
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	if current.UsedAt != nil {
+		if err := s.revoke(ctx, current.SessionID); err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenReused
+	}
+
+	session, err := s.sessions.GetByID(ctx, current.SessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	now := time.Now()
+	if !session.IsActive(now) || now.After(current.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	user, err := s.users.GetByID(ctx, session.UserID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	refreshToken, next, err := s.newRefreshToken(now)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.sessions.Rotate(ctx, current, next); err != nil {
+		if errors.Is(err, repository.ErrRefreshTokenUsed) {
+			if err := s.revoke(ctx, current.SessionID); err != nil {
+				return nil, err
+			}
+			return nil, ErrRefreshTokenReused
+		}
+		return nil, fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+
+	return s.issue(user, session.ID, refreshToken, next.ExpiresAt)
+}
+
+// Logout revokes the session of the current access token
+func (s *authService) Logout(ctx context.Context) error {
+	sessionID, ok := auth.SessionIDFromContext(ctx)
+	if !ok {
+		return ErrSessionNotFound
+	}
+	return s.revoke(ctx, sessionID)
+}
+
+// ListSessions retrieves the active sessions of the authenticated user
+func (s *authService) ListSessions(ctx context.Context) ([]models.SessionResponse, error) {
+	userID, ok := auth.UserIDFromContext(ctx)
+	if !ok {
+		return nil, repository.ErrUnauthenticated
+	}
+
+	sessions, err := s.sessions.ListActive(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	currentID, _ := auth.SessionIDFromContext(ctx)
+	responses := make([]models.SessionResponse, len(sessions))
+	for i, session := range sessions {
+		responses[i] = session.ToResponse()
+		responses[i].Current = session.ID == currentID
+	}
+
+	return responses, nil
+}
+
+// RevokeSession revokes one of the authenticated user's sessions
+func (s *authService) RevokeSession(ctx context.Context, id uuid.UUID) error {
+	userID, ok := auth.UserIDFromContext(ctx)
+	if !ok {
+		return repository.ErrUnauthenticated
+	}
+
+	session, err := s.sessions.GetByID(ctx, id)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return ErrSessionNotFound
+		}
+		return fmt.Errorf("failed to get session: %w", err)
+	}
+	if session.UserID != userID {
+		return ErrSessionNotFound
+	}
+
+	return s.revoke(ctx, session.ID)
+}
+
+// openSession creates a session for the user and issues its first token pair
+func (s *authService) openSession(ctx context.Context, user *models.User, client models.ClientInfo) (*models.AuthResponse, error) {
+	now := time.Now()
+
+	refreshToken, token, err := s.newRefreshToken(now)
+	if err != nil {
+		return nil, err
+	}
+
+	session := &models.Session{
+		UserID:     user.ID,
+		UserAgent:  text.Truncate(client.UserAgent, 512),
+		IPAddress:  text.Truncate(client.IPAddress, 64),
+		LastUsedAt: now,
+		ExpiresAt:  token.ExpiresAt,
+	}
+
+	if err := s.sessions.Create(ctx, session, token); err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return s.issue(user, session.ID, refreshToken, token.ExpiresAt)
+}
+
+// newRefreshToken generates a refresh token and the record storing its hash
+func (s *authService) newRefreshToken(now time.Time) (string, *models.RefreshToken, error) {
+	refreshToken, err := auth.NewRefreshToken()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return refreshToken, &models.RefreshToken{
+		TokenHash: auth.HashToken(refreshToken),
+		ExpiresAt: now.Add(s.tokens.RefreshExpiration()),
+	}, nil
+}
+
+// revoke marks the session as revoked and rejects its outstanding access tokens
+func (s *authService) revoke(ctx context.Context, sessionID uuid.UUID) error {
+	if err := s.sessions.Revoke(ctx, sessionID); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	if err := s.revocations.Revoke(ctx, sessionID, time.Now().Add(s.tokens.Expiration())); err != nil {
+		return fmt.Errorf("failed to revoke access tokens: %w", err)
+	}
+	return nil
+}
+
+// issue builds an auth response with a fresh access token for the session
+func (s *authService) issue(user *models.User, sessionID uuid.UUID, refreshToken string, refreshExpiresAt time.Time) (*models.AuthResponse, error) {
+	token, expiresAt, err := s.tokens.Generate(user.ID, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue token: %w", err)
+	}
+
+	return &models.AuthResponse{
+		AccessToken:      token,
+		TokenType:        "Bearer",
+		ExpiresAt:        expiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: refreshExpiresAt,
+		SessionID:        sessionID,
+		User:             user.ToResponse(),
+	}, nil
+}
+
+// normalizeEmail lowercases and trims an email address for lookups
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/1cbyc/go-todo-api/internal/auth"
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/1cbyc/go-todo-api/internal/repository"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrCommentNotFound is returned when a comment does not exist on the todo
+	ErrCommentNotFound = errors.New("comment not found")
+	// ErrNotCommentAuthor is returned when someone other than its author edits or deletes a comment
+	ErrNotCommentAuthor = errors.New("only the author can change a comment")
+	// ErrBlankComment is returned when a comment body is empty after trimming whitespace
+	ErrBlankComment = errors.New("comment must not be blank")
+)
+
+// CommentService defines the interface for comment business operations
+type CommentService interface {
+	List(ctx context.Context, todoID uuid.UUID, page, perPage int) (*models.CommentListResponse, error)
+	Create(ctx context.Context, todoID uuid.UUID, req *models.CreateCommentRequest) (*models.CommentResponse, error)
+	Update(ctx context.Context, todoID, id uuid.UUID, req *models.UpdateCommentRequest) (*models.CommentResponse, error)
+	Delete(ctx context.Context, todoID, id uuid.UUID) error
+}
+
+// commentService implements CommentService
+type commentService struct {
+	repo  repository.CommentRepository
+	todos repository.TodoRepository
+}
+
+// NewCommentService creates a new comment service
+func NewCommentService(repo repository.CommentRepository, todos repository.TodoRepository) CommentService {
+	return &commentService{repo: repo, todos: todos}
+}
+
+// List retrieves the comments on a todo with pagination, oldest first
+func (s *commentService) List(ctx context.Context, todoID uuid.UUID, page, perPage int) (*models.CommentListResponse, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosRead); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkTodo(ctx, todoID); err != nil {
+		return nil, err
+	}
+
+	page, perPage = normalizePage(page, perPage)
+	comments, total, err := s.repo.ListByTodo(ctx, todoID, page, perPage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comments: %w", err)
+	}
+
+	responses := make([]models.CommentResponse, len(comments))
+	for i, comment := range comments {
+		responses[i] = comment.ToResponse()
+	}
+
+	return &models.CommentListResponse{
+		Data: responses,
+		Meta: pageMeta(total, page, perPage),
+	}, nil
+}
+
+// Create adds a comment by the current user to a todo
+func (s *commentService) Create(ctx context.Context, todoID uuid.UUID, req *models.CreateCommentRequest) (*models.CommentResponse, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosWrite); err != nil {
+		return nil, err
+	}
+
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		return nil, ErrBlankComment
+	}
+	if err := s.checkTodo(ctx, todoID); err != nil {
+		return nil, err
+	}
+
+	comment := &models.Comment{
+		TodoID: todoID,
+		Body:   body,
+	}
+	if err := s.repo.Create(ctx, comment); err != nil {
+		return nil, fmt.Errorf("failed to create comment: %w", err)
+	}
+
+	// Reload to include the author
+	return s.get(ctx, todoID, comment.ID)
+}
+
+// Update edits the body of one of the current user's comments
+func (s *commentService) Update(ctx context.Context, todoID, id uuid.UUID, req *models.UpdateCommentRequest) (*models.CommentResponse, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosWrite); err != nil {
+		return nil, err
+	}
+
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		return nil, ErrBlankComment
+	}
+
+	comment, err := s.authored(ctx, todoID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if comment.Body != body {
+		now := time.Now().UTC()
+		comment.Body = body
+		comment.EditedAt = &now
+		if err := s.repo.Update(ctx, comment); err != nil {
+			return nil, fmt.Errorf("failed to update comment: %w", err)
+		}
+	}
+
+	response := comment.ToResponse()
+	return &response, nil
+}
+
+// Delete removes one of the current user's comments
+func (s *commentService) Delete(ctx context.Context, todoID, id uuid.UUID) error {
+	if err := auth.Authorize(ctx, auth.PermissionTodosWrite); err != nil {
+		return err
+	}
+
+	if _, err := s.authored(ctx, todoID, id); err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, todoID, id); err != nil {
+		if repository.IsNotFound(err) {
+			return ErrCommentNotFound
+		}
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+	return nil
+}
+
+// get retrieves a comment as a response
+func (s *commentService) get(ctx context.Context, todoID, id uuid.UUID) (*models.CommentResponse, error) {
+	comment, err := s.repo.GetByID(ctx, todoID, id)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, fmt.Errorf("failed to get comment: %w", err)
+	}
+
+	response := comment.ToResponse()
+	return &response, nil
+}
+
+// authored retrieves a comment and returns ErrNotCommentAuthor unless the current user wrote it
+func (s *commentService) authored(ctx context.Context, todoID, id uuid.UUID) (*models.Comment, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, auth.ErrForbidden
+	}
+
+	comment, err := s.repo.GetByID(ctx, todoID, id)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, fmt.Errorf("failed to get comment: %w", err)
+	}
+	if comment.UserID != principal.UserID {
+		return nil, ErrNotCommentAuthor
+	}
+	return comment, nil
+}
+
+// checkTodo returns ErrTodoNotFound unless the todo exists in the current workspace
+func (s *commentService) checkTodo(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.todos.GetByID(ctx, id); err != nil {
+		if repository.IsNotFound(err) {
+			return ErrTodoNotFound
+		}
+		return fmt.Errorf("failed to get todo: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,258 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/1cbyc/go-todo-api/internal/auth"
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/1cbyc/go-todo-api/internal/repository"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrListNotFound is returned when a list does not exist in the current workspace
+	ErrListNotFound = errors.New("list not found")
+	// ErrInvalidWorkflow is returned for workflows that cannot be used
+	ErrInvalidWorkflow = errors.New("invalid workflow")
+	// ErrStatusInUse is returned when a workflow change would remove a status that todos are still in
+	ErrStatusInUse = errors.New("status is still in use")
+)
+
+// statusKeyPattern restricts workflow status keys to lowercase slugs
+var statusKeyPattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// ListService defines the interface for list business operations
+type ListService interface {
+	Create(ctx context.Context, req *models.CreateListRequest) (*models.ListResponse, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.ListResponse, error)
+	GetAll(ctx context.Context) ([]models.ListResponse, error)
+	Update(ctx context.Context, id uuid.UUID, req *models.UpdateListRequest) (*models.ListResponse, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	GetWorkflow(ctx context.Context, id uuid.UUID) (*models.Workflow, error)
+	UpdateWorkflow(ctx context.Context, id uuid.UUID, workflow *models.Workflow) (*models.Workflow, error)
+}
+
+// listService implements ListService
+type listService struct {
+	repo repository.ListRepository
+}
+
+// NewListService creates a new list service
+func NewListService(repo repository.ListRepository) ListService {
+	return &listService{repo: repo}
+}
+
+// Create creates a new list
+func (s *listService) Create(ctx context.Context, req *models.CreateListRequest) (*models.ListResponse, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosWrite); err != nil {
+		return nil, err
+	}
+
+	list := &models.List{
+		Name:        strings.TrimSpace(req.Name),
+		Description: req.Description,
+	}
+
+	if err := s.repo.Create(ctx, list); err != nil {
+		return nil, fmt.Errorf("failed to create list: %w", err)
+	}
+
+	response := list.ToResponse(models.ListCounts{})
+	return &response, nil
+}
+
+// GetByID retrieves a list with its todo counts
+func (s *listService) GetByID(ctx context.Context, id uuid.UUID) (*models.ListResponse, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosRead); err != nil {
+		return nil, err
+	}
+
+	list, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.repo.Counts(ctx, []uuid.UUID{list.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count todos: %w", err)
+	}
+
+	response := list.ToResponse(counts[list.ID])
+	return &response, nil
+}
+
+// GetAll retrieves all lists of the workspace with their todo counts
+func (s *listService) GetAll(ctx context.Context) ([]models.ListResponse, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosRead); err != nil {
+		return nil, err
+	}
+
+	lists, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lists: %w", err)
+	}
+
+	ids := make([]uuid.UUID, len(lists))
+	for i, list := range lists {
+		ids[i] = list.ID
+	}
+
+	counts, err := s.repo.Counts(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count todos: %w", err)
+	}
+
+	responses := make([]models.ListResponse, len(lists))
+	for i, list := range lists {
+		responses[i] = list.ToResponse(counts[list.ID])
+	}
+	return responses, nil
+}
+
+// Update updates a list
+func (s *listService) Update(ctx context.Context, id uuid.UUID, req *models.UpdateListRequest) (*models.ListResponse, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosWrite); err != nil {
+		return nil, err
+	}
+
+	list, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		list.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Description != nil {
+		list.Description = *req.Description
+	}
+
+	if err := s.repo.Update(ctx, list); err != nil {
+		return nil, fmt.Errorf("failed to update list: %w", err)
+	}
+
+	counts, err := s.repo.Counts(ctx, []uuid.UUID{list.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count todos: %w", err)
+	}
+
+	response := list.ToResponse(counts[list.ID])
+	return &response, nil
+}
+
+// Delete deletes a list; its todos remain in the workspace without a list
+func (s *listService) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := auth.Authorize(ctx, auth.PermissionTodosWrite); err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		if repository.IsNotFound(err) {
+			return ErrListNotFound
+		}
+		return fmt.Errorf("failed to delete list: %w", err)
+	}
+	return nil
+}
+
+// GetWorkflow retrieves the workflow of a list, which is the default workflow unless the list defines its own
+func (s *listService) GetWorkflow(ctx context.Context, id uuid.UUID) (*models.Workflow, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosRead); err != nil {
+		return nil, err
+	}
+
+	list, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return list.EffectiveWorkflow(), nil
+}
+
+// UpdateWorkflow replaces the workflow of a list. Statuses that todos of the
+// list are still in cannot be removed.
+func (s *listService) UpdateWorkflow(ctx context.Context, id uuid.UUID, workflow *models.Workflow) (*models.Workflow, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosWrite); err != nil {
+		return nil, err
+	}
+
+	if err := validateWorkflow(workflow); err != nil {
+		return nil, err
+	}
+
+	list, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	inUse, err := s.repo.StatusesInUse(ctx, list.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get statuses in use: %w", err)
+	}
+	for _, key := range inUse {
+		if _, ok := workflow.Status(key); !ok {
+			return nil, fmt.Errorf("%w: todos in this list are in status %q", ErrStatusInUse, key)
+		}
+	}
+
+	list.Workflow = workflow
+	if err := s.repo.UpdateWorkflow(ctx, list); err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrListNotFound
+		}
+		return nil, fmt.Errorf("failed to update workflow: %w", err)
+	}
+	return workflow, nil
+}
+
+// validateWorkflow checks that status keys are unique slugs, that there is
+// somewhere for todos to start and finish, and that transitions only name
+// known statuses
+func validateWorkflow(workflow *models.Workflow) error {
+	var open, done bool
+	seen := make(map[string]bool, len(workflow.Statuses))
+	for _, status := range workflow.Statuses {
+		if !statusKeyPattern.MatchString(status.Key) {
+			return fmt.Errorf("%w: status key %q may only contain lowercase letters, digits and underscores", ErrInvalidWorkflow, status.Key)
+		}
+		if seen[status.Key] {
+			return fmt.Errorf("%w: duplicate status %q", ErrInvalidWorkflow, status.Key)
+		}
+		seen[status.Key] = true
+		if status.Done {
+			done = true
+		} else {
+			open = true
+		}
+	}
+	if !open || !done {
+		return fmt.Errorf("%w: at least one open and one done status are required", ErrInvalidWorkflow)
+	}
+
+	for from, targets := range workflow.Transitions {
+		if _, ok := workflow.Status(from); !ok {
+			return fmt.Errorf("%w: transition from unknown status %q", ErrInvalidWorkflow, from)
+		}
+		for _, to := range targets {
+			if _, ok := workflow.Status(to); !ok {
+				return fmt.Errorf("%w: transition from %q to unknown status %q", ErrInvalidWorkflow, from, to)
+			}
+		}
+	}
+	return nil
+}
+
+// get retrieves a list, translating a missing record into ErrListNotFound
+func (s *listService) get(ctx context.Context, id uuid.UUID) (*models.List, error) {
+	list, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrListNotFound
+		}
+		return nil, fmt.Errorf("failed to get list: %w", err)
+	}
+	return list, nil
+}
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"math"
+
+	"github.com/1cbyc/go-todo-api/internal/models"
+)
+
+// normalizePage falls back to the first page and the default page size for out-of-range values
+func normalizePage(page, perPage int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+	return page, perPage
+}
+
+// pageMeta calculates the pagination metadata of a page
+func pageMeta(total int64, page, perPage int) models.Meta {
+	totalPages := int(math.Ceil(float64(total) / float64(perPage)))
+	if totalPages == 0 {
+		totalPages = 1
+	}
+
+	return models.Meta{
+		Total:       total,
+		Page:        page,
+		PerPage:     perPage,
+		TotalPages:  totalPages,
+		HasNext:     page < totalPages,
+		HasPrevious: page > 1,
+	}
+}
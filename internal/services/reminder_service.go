@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/1cbyc/go-todo-api/internal/auth"
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/1cbyc/go-todo-api/internal/notify"
+	"github.com/1cbyc/go-todo-api/internal/repository"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrReminderNotFound is returned when a reminder does not exist or belongs to another user
+	ErrReminderNotFound = errors.New("reminder not found")
+	// ErrInvalidReminder is returned when a reminder sets neither or both of remind_at and offset_minutes
+	ErrInvalidReminder = errors.New("exactly one of remind_at and offset_minutes is required")
+	// ErrReminderInPast is returned when an absolute reminder time has already passed
+	ErrReminderInPast = errors.New("remind_at must be in the future")
+	// ErrChannelUnavailable is returned when the requested notification channel is not configured
+	ErrChannelUnavailable = errors.New("notification channel is not configured")
+)
+
+// ReminderService defines the interface for reminder business operations
+type ReminderService interface {
+	List(ctx context.Context, todoID uuid.UUID) ([]models.Reminder, error)
+	Create(ctx context.Context, todoID uuid.UUID, req *models.CreateReminderRequest) (*models.Reminder, error)
+	Delete(ctx context.Context, todoID, id uuid.UUID) error
+}
+
+// reminderService implements ReminderService
+type reminderService struct {
+	repo      repository.ReminderRepository
+	todos     repository.TodoRepository
+	notifiers map[models.ReminderChannel]notify.Notifier
+}
+
+// NewReminderService creates a new reminder service. Only channels with a
+// notifier can be chosen for new reminders.
+func NewReminderService(repo repository.ReminderRepository, todos repository.TodoRepository, notifiers map[models.ReminderChannel]notify.Notifier) ReminderService {
+	return &reminderService{repo: repo, todos: todos, notifiers: notifiers}
+}
+
+// List returns the current user's reminders for a todo
+func (s *reminderService) List(ctx context.Context, todoID uuid.UUID) ([]models.Reminder, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosRead); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.getTodo(ctx, todoID); err != nil {
+		return nil, err
+	}
+
+	reminders, err := s.repo.ListByTodo(ctx, todoID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reminders: %w", err)
+	}
+	return reminders, nil
+}
+
+// Create adds a reminder for the current user to a todo
+func (s *reminderService) Create(ctx context.Context, todoID uuid.UUID, req *models.CreateReminderRequest) (*models.Reminder, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosWrite); err != nil {
+		return nil, err
+	}
+
+	if (req.RemindAt == nil) == (req.OffsetMinutes == nil) {
+		return nil, ErrInvalidReminder
+	}
+	if req.RemindAt != nil && !req.RemindAt.After(time.Now()) {
+		return nil, ErrReminderInPast
+	}
+
+	channel := req.Channel
+	if channel == "" {
+		channel = models.ReminderChannelEmail
+	}
+	if _, ok := s.notifiers[channel]; !ok {
+		return nil, ErrChannelUnavailable
+	}
+
+	todo, err := s.getTodo(ctx, todoID)
+	if err != nil {
+		return nil, err
+	}
+
+	reminder := &models.Reminder{
+		TodoID:        todo.ID,
+		Channel:       channel,
+		OffsetMinutes: req.OffsetMinutes,
+		RemindAt:      utcTime(req.RemindAt),
+		Status:        models.ReminderPending,
+	}
+	reminder.Schedule(todo.DueDate)
+
+	if err := s.repo.Create(ctx, reminder); err != nil {
+		return nil, fmt.Errorf("failed to create reminder: %w", err)
+	}
+	return reminder, nil
+}
+
+// Delete removes one of the current user's reminders
+func (s *reminderService) Delete(ctx context.Context, todoID, id uuid.UUID) error {
+	if err := auth.Authorize(ctx, auth.PermissionTodosWrite); err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, todoID, id); err != nil {
+		if repository.IsNotFound(err) {
+			return ErrReminderNotFound
+		}
+		return fmt.Errorf("failed to delete reminder: %w", err)
+	}
+	return nil
+}
+
+// getTodo returns ErrTodoNotFound unless the todo exists in the current workspace
+func (s *reminderService) getTodo(ctx context.Context, id uuid.UUID) (*models.Todo, error) {
+	todo, err := s.todos.GetByID(ctx, id)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrTodoNotFound
+		}
+		return nil, fmt.Errorf("failed to get todo: %w", err)
+	}
+	return todo, nil
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/1cbyc/go-todo-api/internal/auth"
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/1cbyc/go-todo-api/internal/repository"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrTagNotFound is returned when a tag does not exist in the current workspace
+	ErrTagNotFound = errors.New("tag not found")
+	// ErrTagExists is returned when renaming a tag to the name of another tag
+	ErrTagExists = errors.New("a tag with this name already exists")
+	// ErrBlankTagName is returned when a tag name is empty after trimming
+	ErrBlankTagName = errors.New("tag name must not be blank")
+	// ErrMergeIntoSelf is returned when a tag is merged into itself
+	ErrMergeIntoSelf = errors.New("cannot merge a tag into itself")
+)
+
+// TagService defines the interface for tag business operations
+type TagService interface {
+	GetAll(ctx context.Context) ([]models.TagResponse, error)
+	Rename(ctx context.Context, id uuid.UUID, req *models.RenameTagRequest) (*models.TagResponse, error)
+	Merge(ctx context.Context, id uuid.UUID, req *models.MergeTagRequest) (*models.TagResponse, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// tagService implements TagService
+type tagService struct {
+	repo repository.TagRepository
+}
+
+// NewTagService creates a new tag service
+func NewTagService(repo repository.TagRepository) TagService {
+	return &tagService{repo: repo}
+}
+
+// GetAll retrieves all tags of the workspace with the number of todos carrying them
+func (s *tagService) GetAll(ctx context.Context) ([]models.TagResponse, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosRead); err != nil {
+		return nil, err
+	}
+
+	tags, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+
+	counts, err := s.repo.Counts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tags: %w", err)
+	}
+
+	responses := make([]models.TagResponse, len(tags))
+	for i, tag := range tags {
+		responses[i] = tag.ToResponse(counts[tag.ID])
+	}
+	return responses, nil
+}
+
+// Rename renames a tag. Renaming onto an existing tag is refused; use Merge instead.
+func (s *tagService) Rename(ctx context.Context, id uuid.UUID, req *models.RenameTagRequest) (*models.TagResponse, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosWrite); err != nil {
+		return nil, err
+	}
+
+	name := normalizeTagName(req.Name)
+	if name == "" {
+		return nil, ErrBlankTagName
+	}
+
+	existing, err := s.repo.GetByName(ctx, name)
+	if err == nil && existing.ID != id {
+		return nil, ErrTagExists
+	}
+	if err != nil && !repository.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get tag: %w", err)
+	}
+
+	if err := s.repo.Rename(ctx, id, name); err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrTagNotFound
+		}
+		return nil, fmt.Errorf("failed to rename tag: %w", err)
+	}
+
+	return s.response(ctx, id)
+}
+
+// Merge moves every todo tagged with the tag to the target tag and deletes the tag
+func (s *tagService) Merge(ctx context.Context, id uuid.UUID, req *models.MergeTagRequest) (*models.TagResponse, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosWrite); err != nil {
+		return nil, err
+	}
+
+	if id == req.TargetID {
+		return nil, ErrMergeIntoSelf
+	}
+
+	if err := s.repo.Merge(ctx, id, req.TargetID); err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrTagNotFound
+		}
+		return nil, fmt.Errorf("failed to merge tags: %w", err)
+	}
+
+	return s.response(ctx, req.TargetID)
+}
+
+// Delete deletes a tag and removes it from every todo
+func (s *tagService) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := auth.Authorize(ctx, auth.PermissionTodosWrite); err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		if repository.IsNotFound(err) {
+			return ErrTagNotFound
+		}
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+	return nil
+}
+
+// response loads a tag with its todo count
+func (s *tagService) response(ctx context.Context, id uuid.UUID) (*models.TagResponse, error) {
+	tag, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrTagNotFound
+		}
+		return nil, fmt.Errorf("failed to get tag: %w", err)
+	}
+
+	counts, err := s.repo.Counts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tags: %w", err)
+	}
+
+	response := tag.ToResponse(counts[tag.ID])
+	return &response, nil
+}
+
+// normalizeTagName trims and lower-cases a tag name so that "Backend" and "backend " are one tag
+func normalizeTagName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// normalizeTagNames normalizes and de-duplicates tag names, keeping their order.
+// A nil slice stays nil so callers can tell "not given" from "empty".
+func normalizeTagNames(names []string) []string {
+	if names == nil {
+		return nil
+	}
+	seen := make(map[string]bool, len(names))
+	normalized := make([]string, 0, len(names))
+	for _, name := range names {
+		name = normalizeTagName(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		normalized = append(normalized, name)
+	}
+	return normalized
+}
+
+// tagsFromNames builds the tag set of a todo from request tag names.
+// A nil slice stays nil, which leaves a todo's tags untouched on update.
+func tagsFromNames(names []string) []models.Tag {
+	names = normalizeTagNames(names)
+	if names == nil {
+		return nil
+	}
+	tags := make([]models.Tag, len(names))
+	for i, name := range names {
+		tags[i] = models.Tag{Name: name}
+	}
+	return tags
+}
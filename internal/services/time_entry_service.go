@@ -0,0 +1,357 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/1cbyc/go-todo-api/internal/auth"
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/1cbyc/go-todo-api/internal/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	// maxEntryDuration caps how long a manually logged time entry may be
+	maxEntryDuration = 24 * time.Hour
+	// maxReportDays caps the date range of a time report
+	maxReportDays = 366
+)
+
+var (
+	// ErrTimeEntryNotFound is returned when a time entry does not exist on the todo
+	ErrTimeEntryNotFound = errors.New("time entry not found")
+	// ErrNoRunningTimer is returned when stopping a timer that is not running
+	ErrNoRunningTimer = errors.New("no timer is running on this todo")
+	// ErrNotTimeEntryOwner is returned when someone other than the user who tracked it changes a time entry
+	ErrNotTimeEntryOwner = errors.New("only the user who tracked the time can change it")
+	// ErrInvalidTimeEntry is returned for time entries with impossible times
+	ErrInvalidTimeEntry = errors.New("invalid time entry")
+	// ErrInvalidReport is returned for time reports over an unusable date range
+	ErrInvalidReport = errors.New("invalid report")
+)
+
+// TimeEntryService defines the interface for time tracking business operations
+type TimeEntryService interface {
+	Start(ctx context.Context, todoID uuid.UUID, req *models.StartTimerRequest) (*models.TimeEntryResponse, error)
+	Stop(ctx context.Context, todoID uuid.UUID) (*models.TimeEntryResponse, error)
+	List(ctx context.Context, todoID uuid.UUID, page, perPage int) (*models.TimeEntryListResponse, error)
+	Create(ctx context.Context, todoID uuid.UUID, req *models.CreateTimeEntryRequest) (*models.TimeEntryResponse, error)
+	Update(ctx context.Context, todoID, id uuid.UUID, req *models.UpdateTimeEntryRequest) (*models.TimeEntryResponse, error)
+	Delete(ctx context.Context, todoID, id uuid.UUID) error
+	Report(ctx context.Context, filter models.TimeReportFilter) (*models.TimeReport, error)
+}
+
+// timeEntryService implements TimeEntryService
+type timeEntryService struct {
+	repo  repository.TimeEntryRepository
+	todos repository.TodoRepository
+}
+
+// NewTimeEntryService creates a new time entry service
+func NewTimeEntryService(repo repository.TimeEntryRepository, todos repository.TodoRepository) TimeEntryService {
+	return &timeEntryService{repo: repo, todos: todos}
+}
+
+// Start starts the current user's timer on a todo, stopping the timer they had running
+func (s *timeEntryService) Start(ctx context.Context, todoID uuid.UUID, req *models.StartTimerRequest) (*models.TimeEntryResponse, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosWrite); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkTodo(ctx, todoID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	entry := &models.TimeEntry{
+		TodoID:    todoID,
+		StartedAt: now,
+		Note:      strings.TrimSpace(req.Note),
+	}
+	if err := s.repo.Start(ctx, entry); err != nil {
+		return nil, fmt.Errorf("failed to start timer: %w", err)
+	}
+
+	response := entry.ToResponse(now)
+	return &response, nil
+}
+
+// Stop stops the current user's running timer on a todo
+func (s *timeEntryService) Stop(ctx context.Context, todoID uuid.UUID) (*models.TimeEntryResponse, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosWrite); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkTodo(ctx, todoID); err != nil {
+		return nil, err
+	}
+
+	entry, err := s.repo.Running(ctx, todoID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrNoRunningTimer
+		}
+		return nil, fmt.Errorf("failed to get timer: %w", err)
+	}
+
+	now := time.Now().UTC()
+	entry.Finish(now)
+	if err := s.repo.Update(ctx, entry); err != nil {
+		return nil, fmt.Errorf("failed to stop timer: %w", err)
+	}
+
+	response := entry.ToResponse(now)
+	return &response, nil
+}
+
+// List retrieves the time entries on a todo with pagination, latest first
+func (s *timeEntryService) List(ctx context.Context, todoID uuid.UUID, page, perPage int) (*models.TimeEntryListResponse, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosRead); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkTodo(ctx, todoID); err != nil {
+		return nil, err
+	}
+
+	page, perPage = normalizePage(page, perPage)
+	entries, total, err := s.repo.ListByTodo(ctx, todoID, page, perPage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get time entries: %w", err)
+	}
+
+	now := time.Now().UTC()
+	responses := make([]models.TimeEntryResponse, len(entries))
+	for i, entry := range entries {
+		responses[i] = entry.ToResponse(now)
+	}
+
+	return &models.TimeEntryListResponse{
+		Data: responses,
+		Meta: pageMeta(total, page, perPage),
+	}, nil
+}
+
+// Create logs time the current user spent on a todo
+func (s *timeEntryService) Create(ctx context.Context, todoID uuid.UUID, req *models.CreateTimeEntryRequest) (*models.TimeEntryResponse, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosWrite); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if err := checkEntryTimes(req.StartedAt, &req.EndedAt, now); err != nil {
+		return nil, err
+	}
+	if err := s.checkTodo(ctx, todoID); err != nil {
+		return nil, err
+	}
+
+	entry := &models.TimeEntry{
+		TodoID:    todoID,
+		StartedAt: req.StartedAt.UTC(),
+		Note:      strings.TrimSpace(req.Note),
+	}
+	entry.Finish(req.EndedAt)
+	if err := s.repo.Create(ctx, entry); err != nil {
+		return nil, fmt.Errorf("failed to create time entry: %w", err)
+	}
+
+	response := entry.ToResponse(now)
+	return &response, nil
+}
+
+// Update corrects the times or note of one of the current user's time entries
+func (s *timeEntryService) Update(ctx context.Context, todoID, id uuid.UUID, req *models.UpdateTimeEntryRequest) (*models.TimeEntryResponse, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosWrite); err != nil {
+		return nil, err
+	}
+
+	entry, err := s.owned(ctx, todoID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.StartedAt != nil {
+		entry.StartedAt = req.StartedAt.UTC()
+	}
+	endedAt := entry.EndedAt
+	if req.EndedAt != nil {
+		endedAt = req.EndedAt
+	}
+	if req.Note != nil {
+		entry.Note = strings.TrimSpace(*req.Note)
+	}
+
+	now := time.Now().UTC()
+	if err := checkEntryTimes(entry.StartedAt, endedAt, now); err != nil {
+		return nil, err
+	}
+	if endedAt != nil {
+		entry.Finish(*endedAt)
+	}
+
+	if err := s.repo.Update(ctx, entry); err != nil {
+		return nil, fmt.Errorf("failed to update time entry: %w", err)
+	}
+
+	response := entry.ToResponse(now)
+	return &response, nil
+}
+
+// Delete removes one of the current user's time entries
+func (s *timeEntryService) Delete(ctx context.Context, todoID, id uuid.UUID) error {
+	if err := auth.Authorize(ctx, auth.PermissionTodosWrite); err != nil {
+		return err
+	}
+
+	if _, err := s.owned(ctx, todoID, id); err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, todoID, id); err != nil {
+		if repository.IsNotFound(err) {
+			return ErrTimeEntryNotFound
+		}
+		return fmt.Errorf("failed to delete time entry: %w", err)
+	}
+	return nil
+}
+
+// Report aggregates the finished time entries of the workspace that started
+// within a range of days, by day, list or tag
+func (s *timeEntryService) Report(ctx context.Context, filter models.TimeReportFilter) (*models.TimeReport, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosRead); err != nil {
+		return nil, err
+	}
+
+	if filter.GroupBy == "" {
+		filter.GroupBy = models.TimeReportByDay
+	}
+	if filter.To.Before(filter.From) {
+		return nil, fmt.Errorf("%w: from must not be after to", ErrInvalidReport)
+	}
+	if filter.To.Sub(filter.From) >= maxReportDays*24*time.Hour {
+		return nil, fmt.Errorf("%w: a report can span at most %d days", ErrInvalidReport, maxReportDays)
+	}
+
+	entries, err := s.repo.Report(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get time entries: %w", err)
+	}
+
+	rows := make(map[string]*models.TimeReportRow)
+	add := func(key, label string, entry models.TimeReportEntry) {
+		row, ok := rows[key]
+		if !ok {
+			row = &models.TimeReportRow{Key: key, Label: label}
+			rows[key] = row
+		}
+		row.Seconds += entry.Duration
+		row.Entries++
+	}
+
+	report := &models.TimeReport{
+		From:    filter.From.Format(time.DateOnly),
+		To:      filter.To.Format(time.DateOnly),
+		GroupBy: filter.GroupBy,
+		Rows:    []models.TimeReportRow{},
+	}
+	for _, entry := range entries {
+		report.TotalSeconds += entry.Duration
+		report.TotalEntries++
+
+		switch filter.GroupBy {
+		case models.TimeReportByList:
+			if entry.ListID == nil {
+				add("", "No list", entry)
+			} else {
+				add(entry.ListID.String(), entry.ListName, entry)
+			}
+		case models.TimeReportByTag:
+			if len(entry.Tags) == 0 {
+				add("", "Untagged", entry)
+			}
+			for _, tag := range entry.Tags {
+				add(tag, tag, entry)
+			}
+		default:
+			day := entry.StartedAt.UTC().Format(time.DateOnly)
+			add(day, day, entry)
+		}
+	}
+
+	for _, row := range rows {
+		row.Hours = hours(row.Seconds)
+		report.Rows = append(report.Rows, *row)
+	}
+	// Days read chronologically, lists and tags alphabetically
+	sort.Slice(report.Rows, func(i, j int) bool {
+		if filter.GroupBy == models.TimeReportByDay {
+			return report.Rows[i].Key < report.Rows[j].Key
+		}
+		return strings.ToLower(report.Rows[i].Label) < strings.ToLower(report.Rows[j].Label)
+	})
+	report.TotalHours = hours(report.TotalSeconds)
+	return report, nil
+}
+
+// owned retrieves a time entry and returns ErrNotTimeEntryOwner unless the current user tracked it
+func (s *timeEntryService) owned(ctx context.Context, todoID, id uuid.UUID) (*models.TimeEntry, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, auth.ErrForbidden
+	}
+
+	entry, err := s.repo.GetByID(ctx, todoID, id)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrTimeEntryNotFound
+		}
+		return nil, fmt.Errorf("failed to get time entry: %w", err)
+	}
+	if entry.UserID != principal.UserID {
+		return nil, ErrNotTimeEntryOwner
+	}
+	return entry, nil
+}
+
+// checkTodo returns ErrTodoNotFound unless the todo exists in the current workspace
+func (s *timeEntryService) checkTodo(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.todos.GetByID(ctx, id); err != nil {
+		if repository.IsNotFound(err) {
+			return ErrTodoNotFound
+		}
+		return fmt.Errorf("failed to get todo: %w", err)
+	}
+	return nil
+}
+
+// checkEntryTimes validates the times of a time entry; a nil endedAt is a running timer
+func checkEntryTimes(startedAt time.Time, endedAt *time.Time, now time.Time) error {
+	if startedAt.After(now) {
+		return fmt.Errorf("%w: started_at must not be in the future", ErrInvalidTimeEntry)
+	}
+	if endedAt == nil {
+		return nil
+	}
+	if !endedAt.After(startedAt) {
+		return fmt.Errorf("%w: ended_at must be after started_at", ErrInvalidTimeEntry)
+	}
+	if endedAt.After(now) {
+		return fmt.Errorf("%w: ended_at must not be in the future", ErrInvalidTimeEntry)
+	}
+	if endedAt.Sub(startedAt) > maxEntryDuration {
+		return fmt.Errorf("%w: an entry can span at most %d hours", ErrInvalidTimeEntry, int(maxEntryDuration.Hours()))
+	}
+	return nil
+}
+
+// hours converts seconds to hours rounded to two decimals
+func hours(seconds int64) float64 {
+	return math.Round(float64(seconds)/36) / 100
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/1cbyc/go-todo-api/internal/auth"
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/1cbyc/go-todo-api/internal/repository"
+)
+
+// Batch applies a list of create, update, delete and toggle operations in
+// order, each through the same checks as the single-todo operations. In atomic
+// mode, the default, all operations run in one transaction that the first
+// failure rolls back. In best-effort mode each operation runs in a transaction
+// of its own, so a failed operation leaves no partial changes behind and the
+// ones after it still run.
+func (s *todoService) Batch(ctx context.Context, req *models.BatchRequest) (*models.BatchResponse, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosWrite); err != nil {
+		return nil, err
+	}
+
+	if len(req.Operations) > s.cfg.BatchMaxSize {
+		return nil, fmt.Errorf("%w: a batch holds at most %d operations, got %d", ErrBatchTooLarge, s.cfg.BatchMaxSize, len(req.Operations))
+	}
+
+	mode := req.Mode
+	if mode == "" {
+		mode = models.BatchModeAtomic
+	}
+	batch := &models.BatchResponse{Mode: mode, Results: make([]models.BatchResult, 0, len(req.Operations))}
+
+	if mode == models.BatchModeAtomic {
+		var failed *models.BatchResult
+		err := s.txs.Transaction(ctx, func(ctx context.Context) error {
+			for i := range req.Operations {
+				result := s.apply(ctx, i, &req.Operations[i])
+				if result.Err != nil {
+					failed = &result
+					return result.Err
+				}
+				batch.Results = append(batch.Results, result)
+			}
+			return nil
+		})
+		if failed != nil {
+			batch.Failed = 1
+			batch.Results = []models.BatchResult{*failed}
+			return batch, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply batch: %w", err)
+		}
+		batch.Succeeded = len(batch.Results)
+		return batch, nil
+	}
+
+	for i := range req.Operations {
+		var result models.BatchResult
+		err := s.txs.Transaction(ctx, func(ctx context.Context) error {
+			result = s.apply(ctx, i, &req.Operations[i])
+			return result.Err
+		})
+		if result.Err == nil && err != nil {
+			result.Todo = nil
+			result.Err = fmt.Errorf("failed to apply operation: %w", err)
+		}
+
+		if result.Err != nil {
+			batch.Failed++
+		} else {
+			batch.Succeeded++
+		}
+		batch.Results = append(batch.Results, result)
+	}
+	return batch, nil
+}
+
+// apply runs one operation of a batch and reports its outcome
+func (s *todoService) apply(ctx context.Context, index int, op *models.BatchOperation) models.BatchResult {
+	result := models.BatchResult{Index: index, Op: op.Op, ID: op.ID}
+
+	var todo *models.TodoResponse
+	var err error
+	switch op.Op {
+	case models.BatchOpCreate:
+		todo, err = s.Create(ctx, op.Create)
+	case models.BatchOpUpdate:
+		todo, err = s.Update(ctx, *op.ID, op.Update)
+	case models.BatchOpDelete:
+		mode := op.Subtasks
+		if mode == "" {
+			mode = models.SubtaskDeleteOrphan
+		}
+		err = s.Delete(ctx, *op.ID, mode)
+	case models.BatchOpToggle:
+		todo, err = s.Toggle(ctx, *op.ID)
+	default:
+		err = fmt.Errorf("unknown batch operation %q", op.Op)
+	}
+
+	if err != nil {
+		if repository.IsNotFound(err) {
+			err = ErrTodoNotFound
+		}
+		result.Err = err
+		return result
+	}
+
+	if todo != nil {
+		result.ID = &todo.ID
+		result.Todo = todo
+	}
+	return result
+}
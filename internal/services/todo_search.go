@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/1cbyc/go-todo-api/internal/auth"
+	"github.com/1cbyc/go-todo-api/internal/models"
+)
+
+const (
+	// maxSearchLength is the longest search query accepted, in characters
+	maxSearchLength = 200
+	// maxSearchTerms is the most words a search query can contain
+	maxSearchTerms = 10
+)
+
+// Search finds the todos whose title or description contain words starting with
+// every word of the query, best matches first
+func (s *todoService) Search(ctx context.Context, query string, filter models.TodoFilter, page, perPage int) (*models.TodoSearchResponse, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosRead); err != nil {
+		return nil, err
+	}
+
+	terms, err := searchTerms(query)
+	if err != nil {
+		return nil, err
+	}
+
+	page, perPage = normalizePage(page, perPage)
+
+	if err := s.prepareFilter(ctx, &filter); err != nil {
+		return nil, err
+	}
+
+	hits, total, err := s.repo.Search(ctx, terms, filter, page, perPage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search todos: %w", err)
+	}
+
+	todos := make([]models.Todo, len(hits))
+	for i, hit := range hits {
+		todos[i] = hit.Todo
+	}
+	responses, err := s.responses(ctx, todos)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]models.TodoSearchResult, len(hits))
+	for i, hit := range hits {
+		results[i] = models.TodoSearchResult{
+			TodoResponse: responses[i],
+			Rank:         hit.Rank,
+			Highlights: models.TodoHighlights{
+				Title:       hit.TitleHighlight,
+				Description: hit.Snippet,
+			},
+		}
+	}
+
+	return &models.TodoSearchResponse{
+		Data: results,
+		Meta: pageMeta(total, page, perPage),
+	}, nil
+}
+
+// searchTerms splits a search query into lowercase words of letters and digits.
+// Everything else separates words, so queries cannot carry operators of the
+// underlying full-text engine.
+func searchTerms(query string) ([]string, error) {
+	if utf8.RuneCountInString(query) > maxSearchLength {
+		return nil, fmt.Errorf("%w: at most %d characters", ErrInvalidSearch, maxSearchLength)
+	}
+
+	var terms []string
+	seen := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r)
+	}) {
+		if !seen[word] {
+			seen[word] = true
+			terms = append(terms, word)
+		}
+	}
+
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("%w: q must contain at least one word", ErrInvalidSearch)
+	}
+	if len(terms) > maxSearchTerms {
+		return nil, fmt.Errorf("%w: at most %d words", ErrInvalidSearch, maxSearchTerms)
+	}
+	return terms, nil
+}
@@ -2,10 +2,20 @@ package services
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"math"
+	"strings"
+	"time"
 
+	"github.com/1cbyc/go-todo-api/internal/auth"
+	"github.com/1cbyc/go-todo-api/internal/config"
+	"github.com/1cbyc/go-todo-api/internal/cursor"
 	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/1cbyc/go-todo-api/internal/rank"
+	"github.com/1cbyc/go-todo-api/internal/recurrence"
 	"github.com/1cbyc/go-todo-api/internal/repository"
 	"github.com/google/uuid"
 )
@@ -14,105 +24,326 @@ import (
 type TodoService interface {
 	Create(ctx context.Context, req *models.CreateTodoRequest) (*models.TodoResponse, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*models.TodoResponse, error)
-	GetAll(ctx context.Context, page, perPage int) (*models.TodoListResponse, error)
+	GetAll(ctx context.Context, filter models.TodoFilter, page, perPage int) (*models.TodoListResponse, error)
+	GetPage(ctx context.Context, filter models.TodoFilter, after string, limit int) (*models.TodoListResponse, error)
+	Search(ctx context.Context, query string, filter models.TodoFilter, page, perPage int) (*models.TodoSearchResponse, error)
+	ViewFilter(ctx context.Context, id string) (models.TodoFilter, error)
+	Batch(ctx context.Context, req *models.BatchRequest) (*models.BatchResponse, error)
 	Update(ctx context.Context, id uuid.UUID, req *models.UpdateTodoRequest) (*models.TodoResponse, error)
-	Delete(ctx context.Context, id uuid.UUID) error
+	Delete(ctx context.Context, id uuid.UUID, mode models.SubtaskDeleteMode) error
+	Trash(ctx context.Context, page, perPage int) (*models.TodoListResponse, error)
+	Restore(ctx context.Context, id uuid.UUID) (*models.TodoResponse, error)
+	Purge(ctx context.Context, id uuid.UUID, mode models.SubtaskDeleteMode) error
 	Toggle(ctx context.Context, id uuid.UUID) (*models.TodoResponse, error)
+	SetStatus(ctx context.Context, id uuid.UUID, req *models.SetStatusRequest) (*models.TodoResponse, error)
+	Move(ctx context.Context, id uuid.UUID, listID *uuid.UUID) (*models.TodoResponse, error)
+	Reorder(ctx context.Context, id uuid.UUID, req *models.ReorderTodoRequest) (*models.TodoResponse, error)
+	SetParent(ctx context.Context, id uuid.UUID, parentID *uuid.UUID) (*models.TodoResponse, error)
+	GetTree(ctx context.Context, id uuid.UUID) (*models.TodoTreeResponse, error)
+	AddDependency(ctx context.Context, id uuid.UUID, req *models.AddDependencyRequest) (*models.TodoResponse, error)
+	RemoveDependency(ctx context.Context, id, blockedByID uuid.UUID) (*models.TodoResponse, error)
+	PreviewRecurrence(ctx context.Context, id uuid.UUID, count int) (*models.RecurrencePreviewResponse, error)
+	StopRecurrence(ctx context.Context, id uuid.UUID) (*models.TodoResponse, error)
+	ListRevisions(ctx context.Context, id uuid.UUID, page, perPage int) (*models.TodoRevisionListResponse, error)
+	GetRevision(ctx context.Context, id uuid.UUID, number int) (*models.TodoRevisionResponse, error)
+	RestoreRevision(ctx context.Context, id uuid.UUID, number int) (*models.TodoResponse, error)
 }
 
+var (
+	// ErrTodoNotFound is returned when a todo does not exist in the current workspace
+	ErrTodoNotFound = errors.New("todo not found")
+	// ErrParentNotFound is returned when a parent todo does not exist in the current workspace
+	ErrParentNotFound = errors.New("parent todo not found")
+	// ErrSubtaskCycle is returned when a todo would become a subtask of itself or of one of its subtasks
+	ErrSubtaskCycle = errors.New("a todo cannot be a subtask of itself or of its own subtasks")
+	// ErrBlockerNotFound is returned when a blocking todo does not exist in the current workspace
+	ErrBlockerNotFound = errors.New("blocking todo not found")
+	// ErrDependencyNotFound is returned when removing a dependency that does not exist
+	ErrDependencyNotFound = errors.New("dependency not found")
+	// ErrDependencyCycle is returned when a dependency would make a todo (transitively) block itself
+	ErrDependencyCycle = errors.New("dependency would create a cycle")
+	// ErrTodoBlocked is returned when completing a todo that still has open blockers
+	ErrTodoBlocked = errors.New("todo is blocked by open todos")
+	// ErrInvalidRecurrence is returned for recurrence rules that cannot be used
+	ErrInvalidRecurrence = recurrence.ErrInvalidRule
+	// ErrNotRecurring is returned for recurrence operations on a todo without a recurrence rule
+	ErrNotRecurring = errors.New("todo is not recurring")
+	// ErrUnknownStatus is returned for statuses that are not part of the todo's workflow
+	ErrUnknownStatus = errors.New("unknown status")
+	// ErrInvalidTransition is returned when the workflow does not allow a todo to move between two statuses
+	ErrInvalidTransition = errors.New("status transition not allowed")
+	// ErrInvalidReorder is returned when a todo cannot be placed relative to the given todos
+	ErrInvalidReorder = errors.New("invalid move")
+	// ErrRevisionNotFound is returned when a todo has no revision with the given number
+	ErrRevisionNotFound = errors.New("revision not found")
+	// ErrInvalidCursor is returned for cursors that were tampered with or belong to another listing
+	ErrInvalidCursor = repository.ErrInvalidCursor
+	// ErrInvalidSearch is returned for search queries without words or with too many of them
+	ErrInvalidSearch = errors.New("invalid search query")
+	// ErrSearchUnavailable is returned when the database cannot run full-text searches
+	ErrSearchUnavailable = repository.ErrSearchUnavailable
+	// ErrBatchTooLarge is returned for batches with more operations than configured
+	ErrBatchTooLarge = errors.New("batch is too large")
+)
+
 // todoService implements TodoService
 type todoService struct {
-	repo repository.TodoRepository
+	repo        repository.TodoRepository
+	lists       repository.ListRepository
+	deps        repository.DependencyRepository
+	reminders   repository.ReminderRepository
+	comments    repository.CommentRepository
+	timeEntries repository.TimeEntryRepository
+	revisions   repository.RevisionRepository
+	views       repository.ViewRepository
+	txs         repository.Transactor
+	cursors     *cursor.Codec
+	cfg         config.TodoConfig
 }
 
 // NewTodoService creates a new todo service
-func NewTodoService(repo repository.TodoRepository) TodoService {
-	return &todoService{repo: repo}
+func NewTodoService(repo repository.TodoRepository, lists repository.ListRepository, deps repository.DependencyRepository, reminders repository.ReminderRepository, comments repository.CommentRepository, timeEntries repository.TimeEntryRepository, revisions repository.RevisionRepository, views repository.ViewRepository, txs repository.Transactor, cfg config.TodoConfig) TodoService {
+	return &todoService{repo: repo, lists: lists, deps: deps, reminders: reminders, comments: comments, timeEntries: timeEntries, revisions: revisions, views: views, txs: txs, cursors: cursor.NewCodec(cfg.CursorSecret), cfg: cfg}
 }
 
 // Create creates a new todo
 func (s *todoService) Create(ctx context.Context, req *models.CreateTodoRequest) (*models.TodoResponse, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosWrite); err != nil {
+		return nil, err
+	}
+
 	// Set default priority if not provided
 	if req.Priority == "" {
 		req.Priority = models.PriorityMedium
 	}
 
+	if req.ParentID != nil {
+		parent, err := s.getParent(ctx, *req.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		// Subtasks live in their parent's list unless told otherwise
+		if req.ListID == nil {
+			req.ListID = parent.ListID
+		}
+	}
+
+	if err := s.checkList(ctx, req.ListID); err != nil {
+		return nil, err
+	}
+
+	workflow, err := s.workflow(ctx, req.ListID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Status == "" {
+		req.Status = workflow.Initial()
+	}
+	status, ok := workflow.Status(req.Status)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownStatus, req.Status)
+	}
+
+	position, err := s.endPosition(ctx, req.ListID)
+	if err != nil {
+		return nil, err
+	}
+
 	todo := &models.Todo{
+		ID:          uuid.New(),
 		Title:       req.Title,
 		Description: req.Description,
 		Priority:    req.Priority,
-		DueDate:     req.DueDate,
-		Completed:   false,
+		DueDate:     utcTime(req.DueDate),
+		ListID:      req.ListID,
+		ParentID:    req.ParentID,
+		Tags:        tagsFromNames(req.Tags),
+		Status:      status.Key,
+		Completed:   status.Done,
+		Position:    position,
+	}
+	if req.Recurrence != "" {
+		if err := startSeries(todo, req.Recurrence, req.RecurrenceMode); err != nil {
+			return nil, err
+		}
 	}
 
-	if err := s.repo.Create(ctx, todo); err != nil {
-		return nil, fmt.Errorf("failed to create todo: %w", err)
+	err = s.txs.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Create(ctx, todo); err != nil {
+			return fmt.Errorf("failed to create todo: %w", err)
+		}
+		return s.record(ctx, todo, nil, models.RevisionCreated)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	response := todo.ToResponse()
-	return &response, nil
+	return s.response(ctx, todo)
 }
 
 // GetByID retrieves a todo by ID
 func (s *todoService) GetByID(ctx context.Context, id uuid.UUID) (*models.TodoResponse, error) {
-	todo, err := s.repo.GetByID(ctx, id)
+	if err := auth.Authorize(ctx, auth.PermissionTodosRead); err != nil {
+		return nil, err
+	}
+
+	todo, err := s.getTodo(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get todo: %w", err)
+		return nil, err
 	}
 
-	response := todo.ToResponse()
-	return &response, nil
+	return s.response(ctx, todo)
 }
 
 // GetAll retrieves all todos with pagination
-func (s *todoService) GetAll(ctx context.Context, page, perPage int) (*models.TodoListResponse, error) {
-	// Validate pagination parameters
-	if page < 1 {
-		page = 1
+func (s *todoService) GetAll(ctx context.Context, filter models.TodoFilter, page, perPage int) (*models.TodoListResponse, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosRead); err != nil {
+		return nil, err
 	}
-	if perPage < 1 || perPage > 100 {
-		perPage = 20
+
+	page, perPage = normalizePage(page, perPage)
+
+	if err := s.prepareFilter(ctx, &filter); err != nil {
+		return nil, err
 	}
 
-	todos, total, err := s.repo.GetAll(ctx, page, perPage)
+	todos, total, err := s.repo.GetAll(ctx, filter, page, perPage)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get todos: %w", err)
 	}
 
 	// Convert todos to responses
-	responses := make([]models.TodoResponse, len(todos))
-	for i, todo := range todos {
-		responses[i] = todo.ToResponse()
+	responses, err := s.responses(ctx, todos)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TodoListResponse{
+		Data: responses,
+		Meta: pageMeta(total, page, perPage),
+	}, nil
+}
+
+// GetPage retrieves the todos following a cursor, or the first ones for an empty
+// cursor. A cursor only continues the listing it came from: it keeps its sort
+// order when the request has none and cannot be used with other filters.
+func (s *todoService) GetPage(ctx context.Context, filter models.TodoFilter, after string, limit int) (*models.TodoListResponse, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosRead); err != nil {
+		return nil, err
+	}
+
+	_, limit = normalizePage(1, limit)
+
+	if err := s.prepareFilter(ctx, &filter); err != nil {
+		return nil, err
 	}
+	principal, _ := auth.PrincipalFromContext(ctx)
+	digest := filterDigest(principal.WorkspaceID, filter)
 
-	// Calculate pagination metadata
-	totalPages := int(math.Ceil(float64(total) / float64(perPage)))
-	if totalPages == 0 {
-		totalPages = 1
+	var position *models.TodoCursor
+	if after != "" {
+		position = &models.TodoCursor{}
+		if err := s.cursors.Decode(after, position); err != nil {
+			return nil, ErrInvalidCursor
+		}
+		if position.Filter != digest {
+			return nil, fmt.Errorf("%w: it belongs to a listing with other filters or in another workspace", ErrInvalidCursor)
+		}
+		if filter.Sort == nil {
+			sort, err := models.ParseTodoSort(position.Sort)
+			if err != nil {
+				return nil, ErrInvalidCursor
+			}
+			filter.Sort = sort
+		} else if models.FormatTodoSort(filter.Sort) != position.Sort {
+			return nil, fmt.Errorf("%w: it belongs to a listing sorted by %s", ErrInvalidCursor, position.Sort)
+		}
+	}
+
+	page, err := s.repo.GetPage(ctx, filter, position, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get todos: %w", err)
+	}
+
+	responses, err := s.responses(ctx, page.Todos)
+	if err != nil {
+		return nil, err
 	}
 
 	meta := models.Meta{
-		Total:       total,
-		Page:        page,
-		PerPage:     perPage,
-		TotalPages:  totalPages,
-		HasNext:     page < totalPages,
-		HasPrevious: page > 1,
+		Total:       page.Total,
+		PerPage:     limit,
+		HasNext:     page.Next != nil,
+		HasPrevious: page.Prev != nil,
+	}
+	if meta.NextCursor, err = s.encodeCursor(page.Next, digest); err != nil {
+		return nil, err
+	}
+	if meta.PrevCursor, err = s.encodeCursor(page.Prev, digest); err != nil {
+		return nil, err
 	}
 
-	return &models.TodoListResponse{
-		Data: responses,
-		Meta: meta,
-	}, nil
+	return &models.TodoListResponse{Data: responses, Meta: meta}, nil
+}
+
+// encodeCursor signs a position in the listing with the given filters; no position gives an empty cursor
+func (s *todoService) encodeCursor(position *models.TodoCursor, digest string) (string, error) {
+	if position == nil {
+		return "", nil
+	}
+	position.Filter = digest
+	encoded, err := s.cursors.Encode(position)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	return encoded, nil
+}
+
+// prepareFilter checks that the list and parent a listing is narrowed to exist
+// and normalizes its tags
+func (s *todoService) prepareFilter(ctx context.Context, filter *models.TodoFilter) error {
+	if err := s.checkList(ctx, filter.ListID); err != nil {
+		return err
+	}
+	if filter.ParentID != nil {
+		if _, err := s.getParent(ctx, *filter.ParentID); err != nil {
+			return err
+		}
+	}
+
+	filter.Tags = normalizeTagNames(filter.Tags)
+	if filter.TagMode == "" {
+		filter.TagMode = models.TagModeAny
+	}
+	return nil
+}
+
+// filterDigest identifies a listing by its workspace and its filters, apart
+// from its sort order, so that cursors only continue listings of their workspace
+func filterDigest(workspaceID uuid.UUID, filter models.TodoFilter) string {
+	filter.Sort = nil
+	encoded, _ := json.Marshal(filter)
+	sum := sha256.Sum256(append(workspaceID[:], encoded...))
+	return hex.EncodeToString(sum[:8])
 }
 
 // Update updates a todo
 func (s *todoService) Update(ctx context.Context, id uuid.UUID, req *models.UpdateTodoRequest) (*models.TodoResponse, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosWrite); err != nil {
+		return nil, err
+	}
+
+	return s.update(ctx, id, req, models.RevisionUpdated)
+}
+
+// update applies an update request and records it as a revision with the given action
+func (s *todoService) update(ctx context.Context, id uuid.UUID, req *models.UpdateTodoRequest, action models.RevisionAction) (*models.TodoResponse, error) {
 	// Get existing todo
-	todo, err := s.repo.GetByID(ctx, id)
+	todo, err := s.getTodo(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get todo: %w", err)
+		return nil, err
 	}
+	before := todo.Snapshot()
 
 	// Update fields if provided
 	if req.Title != nil {
@@ -121,44 +352,1119 @@ func (s *todoService) Update(ctx context.Context, id uuid.UUID, req *models.Upda
 	if req.Description != nil {
 		todo.Description = *req.Description
 	}
-	if req.Completed != nil {
-		todo.Completed = *req.Completed
+	if req.Completed != nil && *req.Completed != todo.Completed {
+		// Completing or reopening skips the workflow's transitions, as it did before statuses existed
+		workflow, err := s.workflow(ctx, todo.ListID)
+		if err != nil {
+			return nil, err
+		}
+		if *req.Completed {
+			setStatus(todo, workflow, workflow.DoneStatus())
+		} else {
+			setStatus(todo, workflow, workflow.Initial())
+		}
 	}
 	if req.Priority != nil {
 		todo.Priority = *req.Priority
 	}
 	if req.DueDate != nil {
-		todo.DueDate = req.DueDate
+		todo.DueDate = utcTime(req.DueDate)
+	}
+	if req.Tags != nil {
+		todo.Tags = tagsFromNames(req.Tags)
+	}
+	if req.RecurrenceMode != nil && req.Recurrence == nil {
+		if todo.Recurrence == "" {
+			return nil, ErrNotRecurring
+		}
+		todo.RecurrenceMode = *req.RecurrenceMode
+	}
+	if req.Recurrence != nil {
+		mode := todo.RecurrenceMode
+		if req.RecurrenceMode != nil {
+			mode = *req.RecurrenceMode
+		}
+		if err := startSeries(todo, *req.Recurrence, mode); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.save(ctx, todo, before, action); err != nil {
+		return nil, err
+	}
+
+	return s.response(ctx, todo)
+}
+
+// save stores a changed todo and records the change as a revision, all in one
+// transaction. Completing the todo is refused while it has open blockers and
+// completes its parents and advances its series afterwards; a new due date
+// moves its reminders.
+func (s *todoService) save(ctx context.Context, todo *models.Todo, before models.TodoSnapshot, action models.RevisionAction) error {
+	return s.txs.Transaction(ctx, func(ctx context.Context) error {
+		completed := todo.Completed && !before.Completed
+		if completed {
+			if err := s.checkBlockers(ctx, todo.ID); err != nil {
+				return err
+			}
+		}
+
+		if err := s.repo.Update(ctx, todo); err != nil {
+			if repository.IsNotFound(err) {
+				return ErrTodoNotFound
+			}
+			return fmt.Errorf("failed to update todo: %w", err)
+		}
+		if err := s.record(ctx, todo, &before, action); err != nil {
+			return err
+		}
+
+		if !sameTime(before.DueDate, todo.DueDate) {
+			if err := s.reminders.Reschedule(ctx, todo.ID, todo.DueDate); err != nil {
+				return err
+			}
+		}
+
+		if completed {
+			if err := s.completeParents(ctx, todo); err != nil {
+				return err
+			}
+			if err := s.advanceSeries(ctx, todo); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// record stores a revision of a todo in its current state. Updates are diffed
+// against the state before them and skipped when nothing changed; a nil before
+// records the todo as a whole, as on creation and deletion.
+func (s *todoService) record(ctx context.Context, todo *models.Todo, before *models.TodoSnapshot, action models.RevisionAction) error {
+	revision := &models.TodoRevision{
+		TodoID:   todo.ID,
+		Action:   action,
+		Snapshot: todo.Snapshot(),
+	}
+	if before != nil {
+		revision.Changes = revision.Snapshot.Diff(*before)
+		if len(revision.Changes) == 0 {
+			return nil
+		}
+	}
+
+	if err := s.revisions.Create(ctx, revision); err != nil {
+		return fmt.Errorf("failed to record revision: %w", err)
+	}
+	return nil
+}
+
+// Delete deletes a todo. Its subtasks are deleted with it in cascade mode and
+// become top-level todos otherwise.
+func (s *todoService) Delete(ctx context.Context, id uuid.UUID, mode models.SubtaskDeleteMode) error {
+	if err := auth.Authorize(ctx, auth.PermissionTodosWrite); err != nil {
+		return err
 	}
 
+	todo, err := s.getTodo(ctx, id)
+	if err != nil {
+		return err
+	}
+	deleted := []models.Todo{*todo}
+	if mode == models.SubtaskDeleteCascade {
+		descendants, err := s.repo.GetDescendants(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get subtasks: %w", err)
+		}
+		deleted = append(deleted, descendants...)
+	}
+
+	return s.txs.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Delete(ctx, id, mode); err != nil {
+			if repository.IsNotFound(err) {
+				return ErrTodoNotFound
+			}
+			return fmt.Errorf("failed to delete todo: %w", err)
+		}
+
+		for i := range deleted {
+			if err := s.record(ctx, &deleted[i], nil, models.RevisionDeleted); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Trash retrieves the deleted todos of the workspace with pagination, most recently deleted first
+func (s *todoService) Trash(ctx context.Context, page, perPage int) (*models.TodoListResponse, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosRead); err != nil {
+		return nil, err
+	}
+
+	page, perPage = normalizePage(page, perPage)
+	todos, total, err := s.repo.Trash(ctx, page, perPage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deleted todos: %w", err)
+	}
+
+	responses, err := s.responses(ctx, todos)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TodoListResponse{
+		Data: responses,
+		Meta: pageMeta(total, page, perPage),
+	}, nil
+}
+
+// Restore takes a todo out of the trash, along with the subtasks and comments
+// deleted with it. A todo whose parent or list is gone in the meantime comes
+// back as a top-level todo or outside any list.
+func (s *todoService) Restore(ctx context.Context, id uuid.UUID) (*models.TodoResponse, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosWrite); err != nil {
+		return nil, err
+	}
+
+	var todo *models.Todo
+	err := s.txs.Transaction(ctx, func(ctx context.Context) error {
+		ids, err := s.repo.Restore(ctx, id)
+		if err != nil {
+			if repository.IsNotFound(err) {
+				return ErrTodoNotFound
+			}
+			return fmt.Errorf("failed to restore todo: %w", err)
+		}
+
+		if todo, err = s.getTodo(ctx, id); err != nil {
+			return err
+		}
+		if err := s.reattach(ctx, todo); err != nil {
+			return err
+		}
+
+		for _, restoredID := range ids {
+			restored := todo
+			if restoredID != id {
+				if restored, err = s.repo.GetByID(ctx, restoredID); err != nil {
+					return fmt.Errorf("failed to get todo: %w", err)
+				}
+			}
+			if err := s.record(ctx, restored, nil, models.RevisionUndeleted); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.response(ctx, todo)
+}
+
+// reattach detaches a restored todo from a parent or list that no longer exists
+func (s *todoService) reattach(ctx context.Context, todo *models.Todo) error {
+	changed := false
+
+	if todo.ParentID != nil {
+		if _, err := s.repo.GetByID(ctx, *todo.ParentID); err != nil {
+			if !repository.IsNotFound(err) {
+				return fmt.Errorf("failed to get parent todo: %w", err)
+			}
+			todo.ParentID = nil
+			changed = true
+		}
+	}
+
+	if todo.ListID != nil {
+		if err := s.checkList(ctx, todo.ListID); err != nil {
+			if !errors.Is(err, ErrListNotFound) {
+				return err
+			}
+			todo.ListID = nil
+			workflow := models.DefaultWorkflow()
+			if _, ok := workflow.Status(todo.Status); !ok {
+				if todo.Completed {
+					setStatus(todo, workflow, workflow.DoneStatus())
+				} else {
+					setStatus(todo, workflow, workflow.Initial())
+				}
+			}
+			position, err := s.endPosition(ctx, nil)
+			if err != nil {
+				return err
+			}
+			todo.Position = position
+			changed = true
+		}
+	}
+
+	if !changed {
+		return nil
+	}
 	if err := s.repo.Update(ctx, todo); err != nil {
-		return nil, fmt.Errorf("failed to update todo: %w", err)
+		return fmt.Errorf("failed to update todo: %w", err)
+	}
+	return nil
+}
+
+// Purge permanently deletes a todo along with its history, comments, reminders
+// and tracked time. A todo that is not in the trash yet is deleted first,
+// taking its subtasks along or leaving them behind depending on mode; deleted
+// subtasks are purged with it. A purge that fails leaves everything as it was.
+func (s *todoService) Purge(ctx context.Context, id uuid.UUID, mode models.SubtaskDeleteMode) error {
+	if err := auth.Authorize(ctx, auth.PermissionTodosWrite); err != nil {
+		return err
+	}
+
+	return s.txs.Transaction(ctx, func(ctx context.Context) error {
+		if _, err := s.repo.GetByID(ctx, id); err == nil {
+			if err := s.repo.Delete(ctx, id, mode); err != nil {
+				return fmt.Errorf("failed to delete todo: %w", err)
+			}
+		} else if !repository.IsNotFound(err) {
+			return fmt.Errorf("failed to get todo: %w", err)
+		}
+
+		if err := s.repo.Purge(ctx, id); err != nil {
+			if repository.IsNotFound(err) {
+				return ErrTodoNotFound
+			}
+			return fmt.Errorf("failed to purge todo: %w", err)
+		}
+		return nil
+	})
+}
+
+// Toggle completes an open todo or reopens a completed one, see Update
+func (s *todoService) Toggle(ctx context.Context, id uuid.UUID) (*models.TodoResponse, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosWrite); err != nil {
+		return nil, err
+	}
+
+	todo, err := s.getTodo(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	completed := !todo.Completed
+	return s.update(ctx, id, &models.UpdateTodoRequest{Completed: &completed}, models.RevisionToggled)
+}
+
+// SetStatus moves a todo to another status of its list's workflow. Moving into
+// a done status completes the todo, with the same checks and follow-ups as Update.
+func (s *todoService) SetStatus(ctx context.Context, id uuid.UUID, req *models.SetStatusRequest) (*models.TodoResponse, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosWrite); err != nil {
+		return nil, err
+	}
+
+	todo, err := s.getTodo(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	before := todo.Snapshot()
+
+	workflow, err := s.workflow(ctx, todo.ListID)
+	if err != nil {
+		return nil, err
+	}
+	status, ok := workflow.Status(req.Status)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownStatus, req.Status)
+	}
+	if status.Key == todo.Status {
+		return s.response(ctx, todo)
+	}
+	// A todo in a status its workflow no longer knows may move anywhere
+	if _, known := workflow.Status(todo.Status); known && !workflow.Allows(todo.Status, status.Key) {
+		allowed := workflow.Transitions[todo.Status]
+		if len(allowed) == 0 {
+			return nil, fmt.Errorf("%w: %q is a final status", ErrInvalidTransition, todo.Status)
+		}
+		return nil, fmt.Errorf("%w: %q can only move to %s", ErrInvalidTransition, todo.Status, strings.Join(allowed, ", "))
+	}
+
+	setStatus(todo, workflow, status.Key)
+	if err := s.save(ctx, todo, before, models.RevisionUpdated); err != nil {
+		return nil, err
 	}
 
-	response := todo.ToResponse()
+	return s.response(ctx, todo)
+}
+
+// Move moves a todo to another list, or out of any list when listID is nil
+func (s *todoService) Move(ctx context.Context, id uuid.UUID, listID *uuid.UUID) (*models.TodoResponse, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosWrite); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkList(ctx, listID); err != nil {
+		return nil, err
+	}
+
+	todo, err := s.getTodo(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	before := todo.Snapshot()
+
+	// A status the new list's workflow lacks is replaced by its first open or done status
+	workflow, err := s.workflow(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := workflow.Status(todo.Status); !ok {
+		if todo.Completed {
+			setStatus(todo, workflow, workflow.DoneStatus())
+		} else {
+			setStatus(todo, workflow, workflow.Initial())
+		}
+	}
+
+	if !sameList(todo.ListID, listID) {
+		if todo.Position, err = s.endPosition(ctx, listID); err != nil {
+			return nil, err
+		}
+	}
+
+	todo.ListID = listID
+	if err := s.save(ctx, todo, before, models.RevisionUpdated); err != nil {
+		return nil, err
+	}
+
+	return s.response(ctx, todo)
+}
+
+// Reorder moves a todo within its list, right after one todo, right before
+// another, or between the two. Only the moved todo's position changes.
+func (s *todoService) Reorder(ctx context.Context, id uuid.UUID, req *models.ReorderTodoRequest) (*models.TodoResponse, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosWrite); err != nil {
+		return nil, err
+	}
+
+	if req.Before == nil && req.After == nil {
+		return nil, fmt.Errorf("%w: before or after is required", ErrInvalidReorder)
+	}
+
+	todo, err := s.getTodo(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// The neighbour that was not given is looked up, so the todo lands right next to the given one
+	var lower, upper string
+	if req.After != nil {
+		anchor, err := s.anchor(ctx, todo, *req.After)
+		if err != nil {
+			return nil, err
+		}
+		lower = anchor.Position
+		if req.Before == nil {
+			if upper, err = s.repo.AdjacentPosition(ctx, todo.ListID, lower, todo.ID, true); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if req.Before != nil {
+		anchor, err := s.anchor(ctx, todo, *req.Before)
+		if err != nil {
+			return nil, err
+		}
+		upper = anchor.Position
+		if req.After == nil {
+			if lower, err = s.repo.AdjacentPosition(ctx, todo.ListID, upper, todo.ID, false); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	position, err := rank.Between(lower, upper)
+	if err != nil {
+		if errors.Is(err, rank.ErrInvalidRange) {
+			return nil, fmt.Errorf("%w: the after todo must come before the before todo", ErrInvalidReorder)
+		}
+		return nil, fmt.Errorf("failed to compute position: %w", err)
+	}
+
+	if err := s.repo.SetPosition(ctx, todo.ID, position); err != nil {
+		return nil, fmt.Errorf("failed to move todo: %w", err)
+	}
+
+	todo.Position = position
+	return s.response(ctx, todo)
+}
+
+// anchor retrieves a todo that another todo is placed next to; both must be in the same list
+func (s *todoService) anchor(ctx context.Context, todo *models.Todo, id uuid.UUID) (*models.Todo, error) {
+	if id == todo.ID {
+		return nil, fmt.Errorf("%w: a todo cannot be placed next to itself", ErrInvalidReorder)
+	}
+	anchor, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: todo %s not found", ErrInvalidReorder, id)
+		}
+		return nil, fmt.Errorf("failed to get todo: %w", err)
+	}
+	if !sameList(anchor.ListID, todo.ListID) {
+		return nil, fmt.Errorf("%w: todo %s is in another list", ErrInvalidReorder, id)
+	}
+	return anchor, nil
+}
+
+// SetParent makes a todo a subtask of another todo, or a top-level todo when parentID is nil
+func (s *todoService) SetParent(ctx context.Context, id uuid.UUID, parentID *uuid.UUID) (*models.TodoResponse, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosWrite); err != nil {
+		return nil, err
+	}
+
+	todo, err := s.getTodo(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	before := todo.Snapshot()
+
+	if parentID != nil {
+		// Walk up from the new parent; meeting the todo means it would become its own ancestor
+		ancestorID := parentID
+		for ancestorID != nil {
+			if *ancestorID == id {
+				return nil, ErrSubtaskCycle
+			}
+			ancestor, err := s.getParent(ctx, *ancestorID)
+			if err != nil {
+				return nil, err
+			}
+			ancestorID = ancestor.ParentID
+		}
+	}
+
+	todo.ParentID = parentID
+	if err := s.save(ctx, todo, before, models.RevisionUpdated); err != nil {
+		return nil, err
+	}
+
+	return s.response(ctx, todo)
+}
+
+// GetTree retrieves a todo with all of its subtasks nested below it
+func (s *todoService) GetTree(ctx context.Context, id uuid.UUID) (*models.TodoTreeResponse, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosRead); err != nil {
+		return nil, err
+	}
+
+	root, err := s.getTodo(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	descendants, err := s.repo.GetDescendants(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subtasks: %w", err)
+	}
+
+	todos := append([]models.Todo{*root}, descendants...)
+	responses, err := s.responses(ctx, todos)
+	if err != nil {
+		return nil, err
+	}
+
+	children := make(map[uuid.UUID][]models.TodoResponse, len(todos))
+	for _, response := range responses[1:] {
+		children[*response.ParentID] = append(children[*response.ParentID], response)
+	}
+
+	var build func(models.TodoResponse) models.TodoTreeResponse
+	build = func(response models.TodoResponse) models.TodoTreeResponse {
+		node := models.TodoTreeResponse{TodoResponse: response, Children: []models.TodoTreeResponse{}}
+		for _, child := range children[response.ID] {
+			node.Children = append(node.Children, build(child))
+		}
+		return node
+	}
+
+	tree := build(responses[0])
+	return &tree, nil
+}
+
+// completeParents completes the ancestors of a completed todo whose subtasks are
+// now all done, when auto-completion is enabled
+func (s *todoService) completeParents(ctx context.Context, todo *models.Todo) error {
+	if !s.cfg.AutoCompleteParent {
+		return nil
+	}
+
+	for parentID := todo.ParentID; parentID != nil; {
+		counts, err := s.repo.ChildCounts(ctx, []uuid.UUID{*parentID})
+		if err != nil {
+			return fmt.Errorf("failed to count subtasks: %w", err)
+		}
+		progress := counts[*parentID]
+		if progress.Completed < progress.Total {
+			return nil
+		}
+
+		parent, err := s.repo.GetByID(ctx, *parentID)
+		if err != nil {
+			return fmt.Errorf("failed to get parent todo: %w", err)
+		}
+		if parent.Completed {
+			return nil
+		}
+		// A parent that is still blocked stays open even when its subtasks are done
+		open, err := s.deps.CountOpenBlockers(ctx, parent.ID)
+		if err != nil {
+			return fmt.Errorf("failed to count blockers: %w", err)
+		}
+		if open > 0 {
+			return nil
+		}
+		workflow, err := s.workflow(ctx, parent.ListID)
+		if err != nil {
+			return err
+		}
+		before := parent.Snapshot()
+		setStatus(parent, workflow, workflow.DoneStatus())
+		if err := s.repo.Update(ctx, parent); err != nil {
+			return fmt.Errorf("failed to complete parent todo: %w", err)
+		}
+		if err := s.record(ctx, parent, &before, models.RevisionUpdated); err != nil {
+			return err
+		}
+		parentID = parent.ParentID
+	}
+	return nil
+}
+
+// AddDependency marks a todo as blocked by another todo
+func (s *todoService) AddDependency(ctx context.Context, id uuid.UUID, req *models.AddDependencyRequest) (*models.TodoResponse, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosWrite); err != nil {
+		return nil, err
+	}
+
+	var todo *models.Todo
+	err := s.txs.Transaction(ctx, func(ctx context.Context) error {
+		// Concurrent additions could each pass the cycle check and close a cycle together
+		if err := s.deps.Lock(ctx); err != nil {
+			return err
+		}
+
+		var err error
+		todo, err = s.getTodo(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if _, err := s.repo.GetByID(ctx, req.BlockedByID); err != nil {
+			if repository.IsNotFound(err) {
+				return ErrBlockerNotFound
+			}
+			return fmt.Errorf("failed to get blocking todo: %w", err)
+		}
+
+		// The new edge closes a cycle if the todo already blocks its new blocker, directly or transitively
+		if req.BlockedByID == id {
+			return ErrDependencyCycle
+		}
+		seen := map[uuid.UUID]bool{req.BlockedByID: true}
+		for frontier := []uuid.UUID{req.BlockedByID}; len(frontier) > 0; {
+			blockers, err := s.deps.BlockerIDs(ctx, frontier)
+			if err != nil {
+				return fmt.Errorf("failed to check for cycles: %w", err)
+			}
+			frontier = nil
+			for _, blocker := range blockers {
+				if blocker == id {
+					return ErrDependencyCycle
+				}
+				if !seen[blocker] {
+					seen[blocker] = true
+					frontier = append(frontier, blocker)
+				}
+			}
+		}
+
+		if err := s.deps.Add(ctx, id, req.BlockedByID); err != nil {
+			return fmt.Errorf("failed to add dependency: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.response(ctx, todo)
+}
+
+// RemoveDependency removes a blocker from a todo
+func (s *todoService) RemoveDependency(ctx context.Context, id, blockedByID uuid.UUID) (*models.TodoResponse, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosWrite); err != nil {
+		return nil, err
+	}
+
+	todo, err := s.getTodo(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.deps.Remove(ctx, id, blockedByID); err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrDependencyNotFound
+		}
+		return nil, fmt.Errorf("failed to remove dependency: %w", err)
+	}
+
+	return s.response(ctx, todo)
+}
+
+// PreviewRecurrence returns the next due dates of a recurring todo's series.
+// In completion mode the preview assumes each occurrence is completed when it falls due.
+func (s *todoService) PreviewRecurrence(ctx context.Context, id uuid.UUID, count int) (*models.RecurrencePreviewResponse, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosRead); err != nil {
+		return nil, err
+	}
+
+	if count < 1 || count > 50 {
+		count = 5
+	}
+
+	todo, err := s.getTodo(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if todo.Recurrence == "" {
+		return nil, ErrNotRecurring
+	}
+
+	rule, err := recurrence.Parse(todo.Recurrence)
+	if err != nil {
+		return nil, err
+	}
+
+	var occurrences []time.Time
+	if todo.RecurrenceMode == models.RecurrenceFromCompletion {
+		if limit := rule.Count(); limit > 0 && limit-todo.Occurrence < count {
+			count = max(limit-todo.Occurrence, 0)
+		}
+		now := time.Now().UTC()
+		occurrences = rule.Upcoming(now, now, count)
+	} else {
+		occurrences = rule.Upcoming(seriesStart(todo), seriesCursor(todo), count)
+	}
+
+	return &models.RecurrencePreviewResponse{
+		Recurrence:     todo.Recurrence,
+		RecurrenceMode: todo.RecurrenceMode,
+		Occurrences:    occurrences,
+	}, nil
+}
+
+// StopRecurrence ends a todo's series; no further occurrences are created
+func (s *todoService) StopRecurrence(ctx context.Context, id uuid.UUID) (*models.TodoResponse, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosWrite); err != nil {
+		return nil, err
+	}
+
+	todo, err := s.getTodo(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if todo.Recurrence == "" || todo.SeriesID == nil {
+		return nil, ErrNotRecurring
+	}
+
+	before := todo.Snapshot()
+	err = s.txs.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.StopSeries(ctx, *todo.SeriesID); err != nil {
+			return fmt.Errorf("failed to stop recurrence: %w", err)
+		}
+		todo.Recurrence = ""
+		todo.RecurrenceMode = ""
+		return s.record(ctx, todo, &before, models.RevisionUpdated)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.response(ctx, todo)
+}
+
+// ListRevisions retrieves the revisions of a todo with pagination, latest first.
+// Revisions outlive their todo, so the history of a deleted todo stays readable.
+func (s *todoService) ListRevisions(ctx context.Context, id uuid.UUID, page, perPage int) (*models.TodoRevisionListResponse, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosRead); err != nil {
+		return nil, err
+	}
+
+	page, perPage = normalizePage(page, perPage)
+	revisions, total, err := s.revisions.ListByTodo(ctx, id, page, perPage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revisions: %w", err)
+	}
+	// Todos from before revisions were recorded have none; any other todo without revisions does not exist
+	if total == 0 {
+		if _, err := s.repo.GetByID(ctx, id); err != nil {
+			if repository.IsNotFound(err) {
+				return nil, ErrTodoNotFound
+			}
+			return nil, fmt.Errorf("failed to get todo: %w", err)
+		}
+	}
+
+	responses := make([]models.TodoRevisionResponse, len(revisions))
+	for i, revision := range revisions {
+		responses[i] = revision.ToResponse()
+	}
+
+	return &models.TodoRevisionListResponse{
+		Data: responses,
+		Meta: pageMeta(total, page, perPage),
+	}, nil
+}
+
+// GetRevision retrieves one revision of a todo by its number
+func (s *todoService) GetRevision(ctx context.Context, id uuid.UUID, number int) (*models.TodoRevisionResponse, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosRead); err != nil {
+		return nil, err
+	}
+
+	revision, err := s.getRevision(ctx, id, number)
+	if err != nil {
+		return nil, err
+	}
+
+	response := revision.ToResponse()
 	return &response, nil
 }
 
-// Delete deletes a todo
-func (s *todoService) Delete(ctx context.Context, id uuid.UUID) error {
-	if err := s.repo.Delete(ctx, id); err != nil {
-		return fmt.Errorf("failed to delete todo: %w", err)
+// RestoreRevision puts the title, description, priority, due date, tags and
+// status of a todo back to how they were in one of its revisions. The todo
+// stays in its current list and under its current parent. Restoring is itself
+// recorded as a revision, so it can be undone the same way.
+func (s *todoService) RestoreRevision(ctx context.Context, id uuid.UUID, number int) (*models.TodoResponse, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosWrite); err != nil {
+		return nil, err
+	}
+
+	todo, err := s.getTodo(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	revision, err := s.getRevision(ctx, id, number)
+	if err != nil {
+		return nil, err
+	}
+
+	before := todo.Snapshot()
+	snapshot := revision.Snapshot
+	todo.Title = snapshot.Title
+	todo.Description = snapshot.Description
+	todo.Priority = snapshot.Priority
+	todo.DueDate = utcTime(snapshot.DueDate)
+	todo.Tags = tagsFromNames(append([]string{}, snapshot.Tags...))
+
+	// The old status comes back if the workflow still has it, otherwise only its completion does
+	workflow, err := s.workflow(ctx, todo.ListID)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := workflow.Status(snapshot.Status); ok {
+		setStatus(todo, workflow, snapshot.Status)
+	} else if snapshot.Completed != todo.Completed {
+		if snapshot.Completed {
+			setStatus(todo, workflow, workflow.DoneStatus())
+		} else {
+			setStatus(todo, workflow, workflow.Initial())
+		}
+	}
+
+	if err := s.save(ctx, todo, before, models.RevisionRestored); err != nil {
+		return nil, err
+	}
+
+	return s.response(ctx, todo)
+}
+
+// getRevision retrieves a revision of a todo, translating a missing record into ErrRevisionNotFound
+func (s *todoService) getRevision(ctx context.Context, id uuid.UUID, number int) (*models.TodoRevision, error) {
+	revision, err := s.revisions.GetByNumber(ctx, id, number)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrRevisionNotFound
+		}
+		return nil, fmt.Errorf("failed to get revision: %w", err)
+	}
+	return revision, nil
+}
+
+// advanceSeries creates the next occurrence of a recurring todo that was just completed.
+// Each occurrence spawns at most one successor, so re-completing a todo is harmless.
+func (s *todoService) advanceSeries(ctx context.Context, todo *models.Todo) error {
+	if todo.Recurrence == "" || todo.NextOccurrenceID != nil {
+		return nil
+	}
+
+	rule, err := recurrence.Parse(todo.Recurrence)
+	if err != nil {
+		return fmt.Errorf("failed to parse recurrence of todo %s: %w", todo.ID, err)
+	}
+
+	var due time.Time
+	var ok bool
+	if todo.RecurrenceMode == models.RecurrenceFromCompletion {
+		if limit := rule.Count(); limit > 0 && todo.Occurrence >= limit {
+			return nil
+		}
+		now := time.Now().UTC()
+		due, ok = rule.Next(now, now)
+	} else {
+		due, ok = rule.Next(seriesStart(todo), seriesCursor(todo))
+	}
+	if !ok {
+		return nil
+	}
+
+	workflow, err := s.workflow(ctx, todo.ListID)
+	if err != nil {
+		return err
+	}
+	position, err := s.endPosition(ctx, todo.ListID)
+	if err != nil {
+		return err
+	}
+
+	next := &models.Todo{
+		ID:             uuid.New(),
+		ListID:         todo.ListID,
+		ParentID:       todo.ParentID,
+		Title:          todo.Title,
+		Description:    todo.Description,
+		Priority:       todo.Priority,
+		DueDate:        &due,
+		Tags:           tagsFromNames(todo.TagNames()),
+		Status:         workflow.Initial(),
+		Position:       position,
+		Recurrence:     todo.Recurrence,
+		RecurrenceMode: todo.RecurrenceMode,
+		SeriesID:       todo.SeriesID,
+		SeriesStart:    todo.SeriesStart,
+		Occurrence:     todo.Occurrence + 1,
+	}
+	if err := s.repo.Create(ctx, next); err != nil {
+		return fmt.Errorf("failed to create next occurrence: %w", err)
+	}
+	if err := s.record(ctx, next, nil, models.RevisionCreated); err != nil {
+		return err
+	}
+	if err := s.reminders.CopyOffsets(ctx, todo.ID, next); err != nil {
+		return err
+	}
+
+	todo.NextOccurrenceID = &next.ID
+	if err := s.repo.Update(ctx, todo); err != nil {
+		return fmt.Errorf("failed to link next occurrence: %w", err)
+	}
+	return nil
+}
+
+// startSeries validates a recurrence rule and makes the todo the first occurrence of a new series
+func startSeries(todo *models.Todo, rule string, mode models.RecurrenceMode) error {
+	parsed, err := recurrence.Parse(rule)
+	if err != nil {
+		return err
+	}
+	if mode == "" {
+		mode = models.RecurrenceFromDueDate
 	}
+
+	start := time.Now().UTC()
+	if todo.DueDate != nil {
+		start = *todo.DueDate
+	}
+
+	todo.Recurrence = parsed.String()
+	todo.RecurrenceMode = mode
+	todo.SeriesID = &todo.ID
+	todo.SeriesStart = &start
+	todo.Occurrence = 1
+	todo.NextOccurrenceID = nil
 	return nil
 }
 
-// Toggle toggles the completed status of a todo
-func (s *todoService) Toggle(ctx context.Context, id uuid.UUID) (*models.TodoResponse, error) {
-	if err := s.repo.Toggle(ctx, id); err != nil {
-		return nil, fmt.Errorf("failed to toggle todo: %w", err)
+// seriesStart returns the anchor of a series' schedule, against which COUNT and UNTIL are evaluated
+func seriesStart(todo *models.Todo) time.Time {
+	if todo.SeriesStart != nil {
+		return *todo.SeriesStart
+	}
+	return todo.CreatedAt
+}
+
+// seriesCursor returns the point after which the next due date of a series is searched
+func seriesCursor(todo *models.Todo) time.Time {
+	if todo.DueDate != nil {
+		return *todo.DueDate
 	}
+	return time.Now().UTC()
+}
+
+// checkBlockers returns ErrTodoBlocked if the todo still has open blockers
+func (s *todoService) checkBlockers(ctx context.Context, id uuid.UUID) error {
+	open, err := s.deps.CountOpenBlockers(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to count blockers: %w", err)
+	}
+	if open > 0 {
+		return ErrTodoBlocked
+	}
+	return nil
+}
 
-	// Get updated todo
+// getTodo retrieves a todo, translating a missing record into ErrTodoNotFound
+func (s *todoService) getTodo(ctx context.Context, id uuid.UUID) (*models.Todo, error) {
 	todo, err := s.repo.GetByID(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get updated todo: %w", err)
+		if repository.IsNotFound(err) {
+			return nil, ErrTodoNotFound
+		}
+		return nil, fmt.Errorf("failed to get todo: %w", err)
 	}
+	return todo, nil
+}
 
-	response := todo.ToResponse()
-	return &response, nil
+// getParent retrieves a prospective parent todo, translating a missing record into ErrParentNotFound
+func (s *todoService) getParent(ctx context.Context, id uuid.UUID) (*models.Todo, error) {
+	parent, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrParentNotFound
+		}
+		return nil, fmt.Errorf("failed to get parent todo: %w", err)
+	}
+	return parent, nil
+}
+
+// response converts a single todo to its response, see responses
+func (s *todoService) response(ctx context.Context, todo *models.Todo) (*models.TodoResponse, error) {
+	responses, err := s.responses(ctx, []models.Todo{*todo})
+	if err != nil {
+		return nil, err
+	}
+	return &responses[0], nil
+}
+
+// responses converts todos to responses, loading the subtask progress,
+// dependencies, comment counts and tracked time of all of them at once
+func (s *todoService) responses(ctx context.Context, todos []models.Todo) ([]models.TodoResponse, error) {
+	ids := make([]uuid.UUID, len(todos))
+	for i, todo := range todos {
+		ids[i] = todo.ID
+	}
+
+	progress, err := s.repo.ChildCounts(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count subtasks: %w", err)
+	}
+
+	blockedBy, blocking, err := s.deps.ForTodos(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dependencies: %w", err)
+	}
+
+	commentCounts, err := s.comments.Counts(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count comments: %w", err)
+	}
+
+	tracked, err := s.timeEntries.Totals(ctx, ids, time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum tracked time: %w", err)
+	}
+
+	responses := make([]models.TodoResponse, len(todos))
+	for i, todo := range todos {
+		responses[i] = todo.ToResponse()
+		if p, ok := progress[todo.ID]; ok {
+			responses[i].Subtasks = &p
+		}
+		responses[i].BlockedBy = append([]models.DependencyRef{}, blockedBy[todo.ID]...)
+		responses[i].Blocking = append([]models.DependencyRef{}, blocking[todo.ID]...)
+		responses[i].CommentCount = commentCounts[todo.ID]
+		responses[i].TrackedSeconds = tracked[todo.ID]
+	}
+	return responses, nil
+}
+
+// checkList returns ErrListNotFound unless listID is nil or names a list in the current workspace
+func (s *todoService) checkList(ctx context.Context, listID *uuid.UUID) error {
+	if listID == nil {
+		return nil
+	}
+	if _, err := s.lists.GetByID(ctx, *listID); err != nil {
+		if repository.IsNotFound(err) {
+			return ErrListNotFound
+		}
+		return fmt.Errorf("failed to get list: %w", err)
+	}
+	return nil
+}
+
+// workflow returns the workflow of a list, or the default workflow for todos outside any list
+func (s *todoService) workflow(ctx context.Context, listID *uuid.UUID) (*models.Workflow, error) {
+	if listID == nil {
+		return models.DefaultWorkflow(), nil
+	}
+	list, err := s.lists.GetByID(ctx, *listID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrListNotFound
+		}
+		return nil, fmt.Errorf("failed to get list: %w", err)
+	}
+	return list.EffectiveWorkflow(), nil
+}
+
+// setStatus puts a todo in a status of its workflow and keeps Completed in step with it
+func setStatus(todo *models.Todo, workflow *models.Workflow, key string) {
+	status, _ := workflow.Status(key)
+	todo.Status = key
+	todo.Completed = status.Done
+}
+
+// endPosition returns a position after every todo of a list
+func (s *todoService) endPosition(ctx context.Context, listID *uuid.UUID) (string, error) {
+	last, err := s.repo.LastPosition(ctx, listID)
+	if err != nil {
+		return "", err
+	}
+	position, err := rank.Between(last, "")
+	if err != nil {
+		return "", fmt.Errorf("failed to compute position: %w", err)
+	}
+	return position, nil
+}
+
+// sameList reports whether two list IDs name the same list, or both no list
+func sameList(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// sameTime reports whether two optional timestamps are both unset or the same instant
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// utcTime normalizes a timestamp to UTC so stored values compare consistently
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
 }
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/1cbyc/go-todo-api/internal/auth"
+	"github.com/1cbyc/go-todo-api/internal/config"
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/1cbyc/go-todo-api/internal/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// newTestTodoService opens a migrated SQLite database in a temporary directory
+// and returns a todo service on it with a context bound to a new workspace.
+// Transactions take the write lock up front, as configured by config.Load.
+func newTestTodoService(t *testing.T) (TodoService, repository.TodoRepository, *gorm.DB, context.Context) {
+	t.Helper()
+	db, err := repository.NewDatabase(config.DatabaseConfig{
+		Driver: "sqlite",
+		DSN:    filepath.Join(t.TempDir(), "test.db") + "?_txlock=immediate",
+	})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+
+	repo := repository.NewTodoRepository(db)
+	service := NewTodoService(repo,
+		repository.NewListRepository(db),
+		repository.NewDependencyRepository(db),
+		repository.NewReminderRepository(db),
+		repository.NewCommentRepository(db),
+		repository.NewTimeEntryRepository(db),
+		repository.NewRevisionRepository(db),
+		repository.NewViewRepository(db),
+		repository.NewTransactor(db),
+		config.TodoConfig{CursorSecret: "test-secret", BatchMaxSize: 100})
+	return service, repo, db, newTestWorkspace(t, db, "ada")
+}
+
+// newTestWorkspace seeds a user and a workspace and returns a context bound to that workspace
+func newTestWorkspace(t *testing.T, db *gorm.DB, name string) context.Context {
+	t.Helper()
+	user := &models.User{ID: uuid.New(), Email: name + "@example.com", PasswordHash: "x"}
+	workspace := &models.Workspace{ID: uuid.New(), Name: name}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	if err := db.Create(workspace).Error; err != nil {
+		t.Fatalf("failed to create workspace: %v", err)
+	}
+	return auth.WithPrincipal(context.Background(), &auth.Principal{
+		UserID:      user.ID,
+		Role:        models.RoleMember,
+		WorkspaceID: workspace.ID,
+	})
+}
+
+// createTestTodo stores a todo with the given title in the context's workspace
+func createTestTodo(t *testing.T, ctx context.Context, repo repository.TodoRepository, title string) *models.Todo {
+	t.Helper()
+	todo := &models.Todo{
+		ID:       uuid.New(),
+		Title:    title,
+		Priority: models.PriorityMedium,
+		Status:   models.StatusBacklog,
+		Position: "a",
+	}
+	if err := repo.Create(ctx, todo); err != nil {
+		t.Fatalf("failed to create todo: %v", err)
+	}
+	return todo
+}
+
+// TestAddDependencyConcurrentCycle adds "a blocked by b" and "b blocked by a" at
+// the same time, for many pairs at once, and expects exactly one dependency of
+// each pair to be refused as a cycle
+func TestAddDependencyConcurrentCycle(t *testing.T) {
+	service, repo, _, ctx := newTestTodoService(t)
+
+	const pairs = 50
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	errs := make([][2]error, pairs)
+	for i := range errs {
+		a := createTestTodo(t, ctx, repo, "a")
+		b := createTestTodo(t, ctx, repo, "b")
+		for j, edge := range [][2]*models.Todo{{a, b}, {b, a}} {
+			wg.Add(1)
+			go func(err *error, todo, blocker *models.Todo) {
+				defer wg.Done()
+				<-start
+				_, *err = service.AddDependency(ctx, todo.ID, &models.AddDependencyRequest{BlockedByID: blocker.ID})
+			}(&errs[i][j], edge[0], edge[1])
+		}
+	}
+	close(start)
+	wg.Wait()
+
+	for i, pair := range errs {
+		refused := 0
+		for _, err := range pair {
+			switch {
+			case err == nil:
+			case errors.Is(err, ErrDependencyCycle):
+				refused++
+			default:
+				t.Fatalf("AddDependency: %v", err)
+			}
+		}
+		if refused != 1 {
+			t.Errorf("pair %d: %d of the two opposite dependencies were refused, want 1", i, refused)
+		}
+	}
+}
+
+// TestGetPageRejectsForeignCursor continues a listing with a cursor issued for
+// another listing or workspace, or tampered with, and expects it to be refused
+func TestGetPageRejectsForeignCursor(t *testing.T) {
+	service, repo, db, ctx := newTestTodoService(t)
+	for _, title := range []string{"a", "b", "c"} {
+		createTestTodo(t, ctx, repo, title)
+	}
+	other := newTestWorkspace(t, db, "grace")
+	createTestTodo(t, other, repo, "d")
+
+	first, err := service.GetPage(ctx, models.TodoFilter{}, "", 1)
+	if err != nil {
+		t.Fatalf("GetPage: %v", err)
+	}
+	cursor := first.Meta.NextCursor
+	if cursor == "" {
+		t.Fatal("first page has no next cursor")
+	}
+	if _, err := service.GetPage(ctx, models.TodoFilter{}, cursor, 1); err != nil {
+		t.Fatalf("GetPage with the cursor of its own listing: %v", err)
+	}
+
+	tampered := cursor[:len(cursor)-1] + "A"
+	if tampered == cursor {
+		tampered = cursor[:len(cursor)-1] + "B"
+	}
+	open := false
+	byDueDate := []models.TodoSort{{Field: models.TodoSortDueDate}}
+	for name, tt := range map[string]struct {
+		ctx    context.Context
+		filter models.TodoFilter
+		cursor string
+	}{
+		"other filter":    {ctx, models.TodoFilter{Completed: &open}, cursor},
+		"other sort":      {ctx, models.TodoFilter{Sort: byDueDate}, cursor},
+		"other workspace": {other, models.TodoFilter{}, cursor},
+		"tampered":        {ctx, models.TodoFilter{}, tampered},
+	} {
+		if _, err := service.GetPage(tt.ctx, tt.filter, tt.cursor, 1); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("%s: got %v, want %v", name, err, ErrInvalidCursor)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/1cbyc/go-todo-api/internal/auth"
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/1cbyc/go-todo-api/internal/query"
+)
+
+// ViewFilter evaluates a built-in view or a view visible to the current user
+// into the filter of its todo listing. Relative dates in the definition are
+// resolved against the current time, so the listing follows the calendar.
+func (s *todoService) ViewFilter(ctx context.Context, id string) (models.TodoFilter, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosRead); err != nil {
+		return models.TodoFilter{}, err
+	}
+
+	if system, ok := models.FindSystemView(id); ok {
+		return viewFilter(system.Definition, time.Now())
+	}
+
+	view, err := getView(ctx, s.views, id)
+	if err != nil {
+		return models.TodoFilter{}, err
+	}
+	return viewFilter(view.Definition, time.Now())
+}
+
+// viewFilter turns a view definition into a listing filter as of now. Views are
+// checked with it before they are saved, so saved views always evaluate.
+func viewFilter(definition models.ViewDefinition, now time.Time) (models.TodoFilter, error) {
+	filter := models.TodoFilter{
+		Completed:  definition.Completed,
+		Priorities: definition.Priorities,
+		HasDueDate: definition.HasDueDate,
+		Overdue:    definition.Overdue,
+		Tags:       definition.Tags,
+		TagMode:    definition.TagMode,
+	}
+
+	if filter.Overdue && filter.HasDueDate != nil && !*filter.HasDueDate {
+		return filter, fmt.Errorf("%w: overdue todos always have a due date, so overdue cannot be combined with has_due_date false", ErrInvalidView)
+	}
+
+	if definition.DueAfter != "" {
+		t, _, err := query.Date(definition.DueAfter, now)
+		if err != nil {
+			return filter, fmt.Errorf("%w: due_after: %v", ErrInvalidView, err)
+		}
+		filter.DueAfter = &t
+	}
+	if definition.DueBefore != "" {
+		t, _, err := query.Date(definition.DueBefore, now)
+		if err != nil {
+			return filter, fmt.Errorf("%w: due_before: %v", ErrInvalidView, err)
+		}
+		filter.DueBefore = &t
+	}
+	if filter.DueAfter != nil && filter.DueBefore != nil && !filter.DueAfter.Before(*filter.DueBefore) {
+		return filter, fmt.Errorf("%w: due_after must be before due_before", ErrInvalidView)
+	}
+
+	if definition.Sort != "" {
+		sort, err := models.ParseTodoSort(definition.Sort)
+		if err != nil {
+			return filter, fmt.Errorf("%w: sort: %v", ErrInvalidView, err)
+		}
+		filter.Sort = sort
+	}
+	return filter, nil
+}
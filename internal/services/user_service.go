@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/1cbyc/go-todo-api/internal/auth"
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/1cbyc/go-todo-api/internal/repository"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrUserNotFound is returned when a user does not exist
+	ErrUserNotFound = errors.New("user not found")
+	// ErrLastAdmin is returned when a change would leave the deployment without an administrator
+	ErrLastAdmin = errors.New("cannot remove the last admin")
+)
+
+// UserService defines the interface for user management operations
+type UserService interface {
+	GetAll(ctx context.Context, page, perPage int) (*models.UserListResponse, error)
+	UpdateRole(ctx context.Context, id uuid.UUID, req *models.UpdateRoleRequest) (*models.UserResponse, error)
+	ResolveRole(ctx context.Context, userID uuid.UUID) (models.Role, error)
+}
+
+// userService implements UserService
+type userService struct {
+	repo repository.UserRepository
+}
+
+// NewUserService creates a new user service
+func NewUserService(repo repository.UserRepository) UserService {
+	return &userService{repo: repo}
+}
+
+// GetAll retrieves all users with pagination
+func (s *userService) GetAll(ctx context.Context, page, perPage int) (*models.UserListResponse, error) {
+	if err := auth.Authorize(ctx, auth.PermissionUsersManage); err != nil {
+		return nil, err
+	}
+
+	// Validate pagination parameters
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	users, total, err := s.repo.GetAll(ctx, page, perPage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users: %w", err)
+	}
+
+	responses := make([]models.UserResponse, len(users))
+	for i, user := range users {
+		responses[i] = user.ToResponse()
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(perPage)))
+	if totalPages == 0 {
+		totalPages = 1
+	}
+
+	return &models.UserListResponse{
+		Data: responses,
+		Meta: models.Meta{
+			Total:       total,
+			Page:        page,
+			PerPage:     perPage,
+			TotalPages:  totalPages,
+			HasNext:     page < totalPages,
+			HasPrevious: page > 1,
+		},
+	}, nil
+}
+
+// UpdateRole changes the role of a user
+func (s *userService) UpdateRole(ctx context.Context, id uuid.UUID, req *models.UpdateRoleRequest) (*models.UserResponse, error) {
+	if err := auth.Authorize(ctx, auth.PermissionUsersManage); err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if user.Role == models.RoleAdmin && req.Role != models.RoleAdmin {
+		admins, err := s.repo.CountByRole(ctx, models.RoleAdmin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count admins: %w", err)
+		}
+		if admins <= 1 {
+			return nil, ErrLastAdmin
+		}
+	}
+
+	if err := s.repo.UpdateRole(ctx, id, req.Role); err != nil {
+		return nil, fmt.Errorf("failed to update role: %w", err)
+	}
+
+	user.Role = req.Role
+	response := user.ToResponse()
+	return &response, nil
+}
+
+// ResolveRole returns the current role of a user
+func (s *userService) ResolveRole(ctx context.Context, userID uuid.UUID) (models.Role, error) {
+	user, err := s.repo.GetByID(ctx, userID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return "", auth.ErrUnknownUser
+		}
+		return "", fmt.Errorf("failed to get user: %w", err)
+	}
+	return user.Role, nil
+}
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/1cbyc/go-todo-api/internal/auth"
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/1cbyc/go-todo-api/internal/repository"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrViewNotFound is returned when a view does not exist or is another user's unshared view
+	ErrViewNotFound = errors.New("view not found")
+	// ErrNotViewOwner is returned when someone other than its owner changes or deletes a shared view
+	ErrNotViewOwner = errors.New("only the owner can change a view")
+	// ErrSystemView is returned when changing or deleting a built-in view
+	ErrSystemView = errors.New("built-in views cannot be changed")
+	// ErrInvalidView is returned for view definitions that cannot be evaluated
+	ErrInvalidView = errors.New("invalid view definition")
+)
+
+// ViewService defines the interface for saved view business operations. Views
+// are addressed by UUID, or by key for the built-in views.
+type ViewService interface {
+	GetAll(ctx context.Context) ([]models.ViewResponse, error)
+	GetByID(ctx context.Context, id string) (*models.ViewResponse, error)
+	Create(ctx context.Context, req *models.CreateViewRequest) (*models.ViewResponse, error)
+	Update(ctx context.Context, id string, req *models.UpdateViewRequest) (*models.ViewResponse, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// viewService implements ViewService
+type viewService struct {
+	repo repository.ViewRepository
+}
+
+// NewViewService creates a new saved view service
+func NewViewService(repo repository.ViewRepository) ViewService {
+	return &viewService{repo: repo}
+}
+
+// GetAll retrieves the built-in views followed by the current user's views and
+// the views shared in the workspace
+func (s *viewService) GetAll(ctx context.Context) ([]models.ViewResponse, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosRead); err != nil {
+		return nil, err
+	}
+
+	views, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get views: %w", err)
+	}
+
+	responses := make([]models.ViewResponse, 0, len(models.SystemViews)+len(views))
+	for i := range models.SystemViews {
+		responses = append(responses, models.SystemViews[i].ToResponse())
+	}
+	for i := range views {
+		responses = append(responses, views[i].ToResponse())
+	}
+	return responses, nil
+}
+
+// GetByID retrieves a built-in view or a view visible to the current user
+func (s *viewService) GetByID(ctx context.Context, id string) (*models.ViewResponse, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosRead); err != nil {
+		return nil, err
+	}
+
+	if system, ok := models.FindSystemView(id); ok {
+		response := system.ToResponse()
+		return &response, nil
+	}
+
+	view, err := getView(ctx, s.repo, id)
+	if err != nil {
+		return nil, err
+	}
+	response := view.ToResponse()
+	return &response, nil
+}
+
+// Create saves a view of the current user
+func (s *viewService) Create(ctx context.Context, req *models.CreateViewRequest) (*models.ViewResponse, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosWrite); err != nil {
+		return nil, err
+	}
+
+	if _, err := viewFilter(req.Definition, time.Now()); err != nil {
+		return nil, err
+	}
+
+	view := &models.SavedView{
+		Name:       strings.TrimSpace(req.Name),
+		Shared:     req.Shared,
+		Definition: req.Definition,
+	}
+	if err := s.repo.Create(ctx, view); err != nil {
+		return nil, fmt.Errorf("failed to create view: %w", err)
+	}
+
+	response := view.ToResponse()
+	return &response, nil
+}
+
+// Update renames, shares or redefines one of the current user's views
+func (s *viewService) Update(ctx context.Context, id string, req *models.UpdateViewRequest) (*models.ViewResponse, error) {
+	if err := auth.Authorize(ctx, auth.PermissionTodosWrite); err != nil {
+		return nil, err
+	}
+
+	view, err := s.owned(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		view.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Shared != nil {
+		view.Shared = *req.Shared
+	}
+	if req.Definition != nil {
+		if _, err := viewFilter(*req.Definition, time.Now()); err != nil {
+			return nil, err
+		}
+		view.Definition = *req.Definition
+	}
+
+	if err := s.repo.Update(ctx, view); err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrViewNotFound
+		}
+		return nil, fmt.Errorf("failed to update view: %w", err)
+	}
+
+	response := view.ToResponse()
+	return &response, nil
+}
+
+// Delete deletes one of the current user's views
+func (s *viewService) Delete(ctx context.Context, id string) error {
+	if err := auth.Authorize(ctx, auth.PermissionTodosWrite); err != nil {
+		return err
+	}
+
+	view, err := s.owned(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, view.ID); err != nil {
+		if repository.IsNotFound(err) {
+			return ErrViewNotFound
+		}
+		return fmt.Errorf("failed to delete view: %w", err)
+	}
+	return nil
+}
+
+// owned retrieves a view the current user may change
+func (s *viewService) owned(ctx context.Context, id string) (*models.SavedView, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, auth.ErrForbidden
+	}
+	if _, ok := models.FindSystemView(id); ok {
+		return nil, ErrSystemView
+	}
+
+	view, err := getView(ctx, s.repo, id)
+	if err != nil {
+		return nil, err
+	}
+	if view.UserID != principal.UserID {
+		return nil, ErrNotViewOwner
+	}
+	return view, nil
+}
+
+// getView retrieves a saved view visible to the current user by its UUID
+func getView(ctx context.Context, repo repository.ViewRepository, id string) (*models.SavedView, error) {
+	viewID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrViewNotFound
+	}
+
+	view, err := repo.GetByID(ctx, viewID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrViewNotFound
+		}
+		return nil, fmt.Errorf("failed to get view: %w", err)
+	}
+	return view, nil
+}
@@ -0,0 +1,284 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/1cbyc/go-todo-api/internal/auth"
+	"github.com/1cbyc/go-todo-api/internal/models"
+	"github.com/1cbyc/go-todo-api/internal/repository"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrWorkspaceNotFound is returned when a workspace does not exist or the user is not a member
+	ErrWorkspaceNotFound = errors.New("workspace not found")
+	// ErrNotWorkspaceOwner is returned when a non-owner tries to manage a workspace
+	ErrNotWorkspaceOwner = errors.New("only workspace owners can manage members")
+	// ErrAlreadyMember is returned when adding a user who already belongs to the workspace
+	ErrAlreadyMember = errors.New("user is already a member")
+	// ErrMemberNotFound is returned when removing a user who is not a member
+	ErrMemberNotFound = errors.New("member not found")
+	// ErrLastOwner is returned when a change would leave a workspace without an owner
+	ErrLastOwner = errors.New("cannot remove the last owner")
+)
+
+// WorkspaceService defines the interface for workspace operations
+type WorkspaceService interface {
+	Create(ctx context.Context, req *models.CreateWorkspaceRequest) (*models.WorkspaceResponse, error)
+	List(ctx context.Context) ([]models.WorkspaceResponse, error)
+	ListMembers(ctx context.Context, workspaceID uuid.UUID) ([]models.WorkspaceMemberResponse, error)
+	AddMember(ctx context.Context, workspaceID uuid.UUID, req *models.AddMemberRequest) (*models.WorkspaceMemberResponse, error)
+	RemoveMember(ctx context.Context, workspaceID, userID uuid.UUID) error
+	ResolveWorkspace(ctx context.Context, userID uuid.UUID, requested uuid.UUID) (uuid.UUID, error)
+}
+
+// workspaceService implements WorkspaceService
+type workspaceService struct {
+	repo  repository.WorkspaceRepository
+	users repository.UserRepository
+	txs   repository.Transactor
+}
+
+// NewWorkspaceService creates a new workspace service
+func NewWorkspaceService(repo repository.WorkspaceRepository, users repository.UserRepository, txs repository.Transactor) WorkspaceService {
+	return &workspaceService{repo: repo, users: users, txs: txs}
+}
+
+// Create creates a workspace owned by the authenticated user
+func (s *workspaceService) Create(ctx context.Context, req *models.CreateWorkspaceRequest) (*models.WorkspaceResponse, error) {
+	userID, ok := auth.UserIDFromContext(ctx)
+	if !ok {
+		return nil, repository.ErrUnauthenticated
+	}
+
+	workspace := &models.Workspace{Name: strings.TrimSpace(req.Name)}
+	if err := s.repo.Create(ctx, workspace, userID); err != nil {
+		return nil, fmt.Errorf("failed to create workspace: %w", err)
+	}
+
+	return &models.WorkspaceResponse{
+		ID:        workspace.ID,
+		Name:      workspace.Name,
+		Role:      models.WorkspaceRoleOwner,
+		CreatedAt: workspace.CreatedAt,
+	}, nil
+}
+
+// List retrieves the workspaces of the authenticated user
+func (s *workspaceService) List(ctx context.Context) ([]models.WorkspaceResponse, error) {
+	userID, ok := auth.UserIDFromContext(ctx)
+	if !ok {
+		return nil, repository.ErrUnauthenticated
+	}
+
+	if _, err := s.ResolveWorkspace(ctx, userID, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	workspaces, members, err := s.repo.ListForUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+
+	byID := make(map[uuid.UUID]models.Workspace, len(workspaces))
+	for _, workspace := range workspaces {
+		byID[workspace.ID] = workspace
+	}
+
+	responses := make([]models.WorkspaceResponse, 0, len(members))
+	for _, member := range members {
+		workspace, ok := byID[member.WorkspaceID]
+		if !ok {
+			continue
+		}
+		responses = append(responses, models.WorkspaceResponse{
+			ID:        workspace.ID,
+			Name:      workspace.Name,
+			Role:      member.Role,
+			CreatedAt: workspace.CreatedAt,
+		})
+	}
+	return responses, nil
+}
+
+// ListMembers retrieves the members of a workspace the authenticated user belongs to
+func (s *workspaceService) ListMembers(ctx context.Context, workspaceID uuid.UUID) ([]models.WorkspaceMemberResponse, error) {
+	if _, err := s.membership(ctx, workspaceID); err != nil {
+		return nil, err
+	}
+
+	members, err := s.repo.ListMembers(ctx, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list members: %w", err)
+	}
+
+	responses := make([]models.WorkspaceMemberResponse, len(members))
+	for i, member := range members {
+		responses[i] = member.ToResponse()
+	}
+	return responses, nil
+}
+
+// AddMember adds a registered user to a workspace owned by the authenticated user
+func (s *workspaceService) AddMember(ctx context.Context, workspaceID uuid.UUID, req *models.AddMemberRequest) (*models.WorkspaceMemberResponse, error) {
+	if err := s.requireOwner(ctx, workspaceID); err != nil {
+		return nil, err
+	}
+
+	user, err := s.users.GetByEmail(ctx, normalizeEmail(req.Email))
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if _, err := s.repo.GetMembership(ctx, workspaceID, user.ID); err == nil {
+		return nil, ErrAlreadyMember
+	} else if !repository.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to check membership: %w", err)
+	}
+
+	role := req.Role
+	if role == "" {
+		role = models.WorkspaceRoleMember
+	}
+
+	member := &models.WorkspaceMember{
+		WorkspaceID: workspaceID,
+		UserID:      user.ID,
+		Role:        role,
+	}
+	if err := s.repo.AddMember(ctx, member); err != nil {
+		return nil, fmt.Errorf("failed to add member: %w", err)
+	}
+
+	member.User = *user
+	response := member.ToResponse()
+	return &response, nil
+}
+
+// RemoveMember removes a user from a workspace. Owners can remove anyone; members can only leave.
+func (s *workspaceService) RemoveMember(ctx context.Context, workspaceID, userID uuid.UUID) error {
+	current, err := s.membership(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+	if current.Role != models.WorkspaceRoleOwner && current.UserID != userID {
+		return ErrNotWorkspaceOwner
+	}
+
+	target, err := s.repo.GetMembership(ctx, workspaceID, userID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return ErrMemberNotFound
+		}
+		return fmt.Errorf("failed to get membership: %w", err)
+	}
+
+	if target.Role == models.WorkspaceRoleOwner {
+		owners, err := s.repo.CountOwners(ctx, workspaceID)
+		if err != nil {
+			return fmt.Errorf("failed to count owners: %w", err)
+		}
+		if owners <= 1 {
+			return ErrLastOwner
+		}
+	}
+
+	if err := s.repo.RemoveMember(ctx, workspaceID, userID); err != nil {
+		if repository.IsNotFound(err) {
+			return ErrMemberNotFound
+		}
+		return fmt.Errorf("failed to remove member: %w", err)
+	}
+	return nil
+}
+
+// ResolveWorkspace returns the workspace a request should be bound to. An explicit
+// workspace must be one the user belongs to; otherwise the user's oldest workspace is
+// used, creating a personal workspace for accounts that predate workspaces. The
+// personal workspace is created and the account's todos moved into it together, or not at all.
+func (s *workspaceService) ResolveWorkspace(ctx context.Context, userID uuid.UUID, requested uuid.UUID) (uuid.UUID, error) {
+	if requested != uuid.Nil {
+		if _, err := s.repo.GetMembership(ctx, requested, userID); err != nil {
+			if repository.IsNotFound(err) {
+				return uuid.Nil, auth.ErrWorkspaceAccess
+			}
+			return uuid.Nil, fmt.Errorf("failed to get membership: %w", err)
+		}
+		return requested, nil
+	}
+
+	member, err := s.repo.GetDefaultMembership(ctx, userID)
+	if err == nil {
+		return member.WorkspaceID, nil
+	}
+	if !repository.IsNotFound(err) {
+		return uuid.Nil, fmt.Errorf("failed to get membership: %w", err)
+	}
+
+	// A user's first requests may arrive at once; the first to take the lock
+	// creates the workspace and adopts the todos, the others find it afterwards
+	var workspaceID uuid.UUID
+	err = s.txs.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.users.Lock(ctx, userID); err != nil {
+			return err
+		}
+
+		member, err := s.repo.GetDefaultMembership(ctx, userID)
+		if err == nil {
+			workspaceID = member.WorkspaceID
+			return nil
+		}
+		if !repository.IsNotFound(err) {
+			return fmt.Errorf("failed to get membership: %w", err)
+		}
+
+		workspace := &models.Workspace{Name: "Personal"}
+		if err := s.repo.Create(ctx, workspace, userID); err != nil {
+			return fmt.Errorf("failed to create personal workspace: %w", err)
+		}
+		if err := s.repo.AdoptUnassignedTodos(ctx, workspace.ID, userID); err != nil {
+			return err
+		}
+		workspaceID = workspace.ID
+		return nil
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return workspaceID, nil
+}
+
+// membership returns the authenticated user's membership in a workspace
+func (s *workspaceService) membership(ctx context.Context, workspaceID uuid.UUID) (*models.WorkspaceMember, error) {
+	userID, ok := auth.UserIDFromContext(ctx)
+	if !ok {
+		return nil, repository.ErrUnauthenticated
+	}
+
+	member, err := s.repo.GetMembership(ctx, workspaceID, userID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrWorkspaceNotFound
+		}
+		return nil, fmt.Errorf("failed to get membership: %w", err)
+	}
+	return member, nil
+}
+
+// requireOwner returns ErrNotWorkspaceOwner unless the authenticated user owns the workspace
+func (s *workspaceService) requireOwner(ctx context.Context, workspaceID uuid.UUID) error {
+	member, err := s.membership(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+	if member.Role != models.WorkspaceRoleOwner {
+		return ErrNotWorkspaceOwner
+	}
+	return nil
+}
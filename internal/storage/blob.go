@@ -0,0 +1,22 @@
+// Package storage keeps the contents of uploaded files in a pluggable blob store.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrBlobNotFound is returned when a blob does not exist in the store
+var ErrBlobNotFound = errors.New("blob not found")
+
+// BlobStore stores opaque blobs under string keys. Keys are slash-separated
+// paths generated by the application, never user input.
+type BlobStore interface {
+	// Put stores size bytes read from r under key, replacing any existing blob
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Open returns a seekable reader over a blob, suitable for serving byte ranges
+	Open(ctx context.Context, key string) (io.ReadSeekCloser, error)
+	// Delete removes a blob. Deleting a missing blob is not an error.
+	Delete(ctx context.Context, key string) error
+}
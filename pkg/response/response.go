@@ -83,6 +83,42 @@ func Conflict(c *gin.Context, message string, err interface{}) {
 	})
 }
 
+// PayloadTooLarge sends a 413 Request Entity Too Large response
+func PayloadTooLarge(c *gin.Context, message string, err interface{}) {
+	c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{
+		Success: false,
+		Message: message,
+		Error:   err,
+	})
+}
+
+// UnsupportedMediaType sends a 415 Unsupported Media Type response
+func UnsupportedMediaType(c *gin.Context, message string, err interface{}) {
+	c.JSON(http.StatusUnsupportedMediaType, ErrorResponse{
+		Success: false,
+		Message: message,
+		Error:   err,
+	})
+}
+
+// UnprocessableEntity sends a 422 Unprocessable Entity response
+func UnprocessableEntity(c *gin.Context, message string, err interface{}) {
+	c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+		Success: false,
+		Message: message,
+		Error:   err,
+	})
+}
+
+// ServiceUnavailable sends a 503 Service Unavailable response
+func ServiceUnavailable(c *gin.Context, message string, err interface{}) {
+	c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+		Success: false,
+		Message: message,
+		Error:   err,
+	})
+}
+
 // InternalServerError sends a 500 Internal Server Error response
 func InternalServerError(c *gin.Context, message string, err interface{}) {
 	c.JSON(http.StatusInternalServerError, ErrorResponse{
@@ -0,0 +1,15 @@
+package text
+
+import "unicode/utf8"
+
+// Truncate shortens s to at most n bytes, cutting before a multi-byte character
+// rather than through it so that the result stays valid UTF-8
+func Truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}